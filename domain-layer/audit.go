@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	CreateAuditOperation string = "create"
+	UpdateAuditOperation string = "update"
+	DeleteAuditOperation string = "delete"
+
+	TransactionAuditRecord string = "transaction"
+	CategoryAuditRecord    string = "category"
+	EntityAuditRecord      string = "entity"
+)
+
+// AuditEntry is a change of a transaction, a category or an entity.
+type AuditEntry struct {
+	ID        int
+	Timestamp time.Time
+	Actor     string
+	RequestID string
+	Operation string
+	Record    string
+	Entity    string
+	Kind      string
+	RecordID  int
+	Request   string
+	Before    string
+	After     string
+}
+
+type AuditEntries []AuditEntry
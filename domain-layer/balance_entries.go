@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+const (
+	OpeningBalanceEntry    string = "opening"
+	AdjustmentBalanceEntry string = "adjustment"
+)
+
+var (
+	BalanceEntriesTypes = []string{
+		OpeningBalanceEntry, AdjustmentBalanceEntry,
+	}
+)
+
+// BalanceEntry changes the balance of an entity without being a transaction.
+type BalanceEntry struct {
+	ID        int
+	Entity    string
+	Kind      string
+	Type      string
+	EntryDate time.Time
+	Amount    float32
+	Note      string
+}
+
+type BalanceEntries []BalanceEntry
@@ -7,6 +7,14 @@ type Category struct {
 
 type Categories []Category
 
+const (
+	NoCategoryLabel string = "NO-CATEGORY"
+)
+
 var (
 	RefToCategories *Categories
+
+	DefaultCategoriesLabels = []string{
+		NoCategoryLabel, "FUEL", "TRIPS", "SALARY", "SUPERMARKET",
+	}
 )
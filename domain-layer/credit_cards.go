@@ -0,0 +1,33 @@
+package models
+
+const (
+	OpenStatementStatus        string = "open"
+	PaidStatementStatus        string = "paid"
+	MinimumPaidStatementStatus string = "minimum_paid"
+	UnpaidStatementStatus      string = "unpaid"
+	OverdueStatementStatus     string = "overdue"
+)
+
+// CreditCard is the statement cycle of a credit card entity.
+type CreditCard struct {
+	Entity            string
+	Kind              string
+	ClosingDay        int
+	DueDay            int
+	MinimumPercentage float32
+	MinimumAmount     float32
+}
+
+type CreditCards []CreditCard
+
+// StatementPayment links a transaction to the statement of a card.
+type StatementPayment struct {
+	CardEntity    string
+	CardKind      string
+	Statement     string
+	Entity        string
+	Kind          string
+	TransactionID int
+}
+
+type StatementsPayments []StatementPayment
@@ -0,0 +1,38 @@
+package models
+
+// CustomField is a field defined by the user for the transactions of an entity.
+type CustomField struct {
+	ID       int
+	Entity   string
+	Kind     string
+	Name     string
+	Type     CustomFieldType
+	Options  []string
+	Required bool
+}
+
+type CustomFields []CustomField
+
+type CustomFieldType string
+
+const (
+	StringCustomFieldType CustomFieldType = "string"
+	NumberCustomFieldType CustomFieldType = "number"
+	DateCustomFieldType   CustomFieldType = "date"
+	EnumCustomFieldType   CustomFieldType = "enum"
+)
+
+var (
+	CustomFieldTypes = []CustomFieldType{
+		StringCustomFieldType, NumberCustomFieldType, DateCustomFieldType, EnumCustomFieldType,
+	}
+)
+
+// FieldValue is the value of a custom field in a transaction.
+type FieldValue struct {
+	Name  string
+	Type  CustomFieldType
+	Value string
+}
+
+type FieldValues []FieldValue
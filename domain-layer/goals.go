@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+const (
+	EntityGoalType   string = "entity"
+	TagGoalType      string = "tag"
+	CategoryGoalType string = "category"
+)
+
+var (
+	GoalsTypes = []string{
+		EntityGoalType, TagGoalType, CategoryGoalType,
+	}
+)
+
+// Goal is a target amount to save by a date, e.g. 3000 for a trip by June.
+type Goal struct {
+	ID         int
+	Name       string
+	Target     float32
+	StartDate  time.Time
+	TargetDate time.Time
+	Type       string
+	Entity     string
+	Kind       string
+	Label      string
+}
+
+type Goals []Goal
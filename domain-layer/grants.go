@@ -0,0 +1,24 @@
+package models
+
+const (
+	ViewerRole string = "viewer"
+	EditorRole string = "editor"
+	OwnerRole  string = "owner"
+)
+
+var (
+	Roles = []string{
+		ViewerRole, EditorRole, OwnerRole,
+	}
+)
+
+// Grant is the role of a user on an entity of another user.
+type Grant struct {
+	OwnerID int
+	Entity  string
+	Kind    string
+	UserID  int
+	Role    string
+}
+
+type Grants []Grant
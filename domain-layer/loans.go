@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Loan is a loan, or a purchase in installments, repaid monthly.
+type Loan struct {
+	ID         int
+	Name       string
+	Principal  float32
+	AnnualRate float32
+	Term       int
+	StartDate  time.Time
+	Entity     string
+	Kind       string
+	Category   string
+	Recorded   int
+}
+
+type Loans []Loan
+
+// LoanInstallment links an installment of a loan to its transaction.
+type LoanInstallment struct {
+	LoanID        int
+	Number        int
+	Entity        string
+	Kind          string
+	TransactionID int
+}
+
+type LoansInstallments []LoanInstallment
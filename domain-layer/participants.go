@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+const (
+	EqualShareSplit      string = "equal"
+	PercentageShareSplit string = "percentage"
+	FixedShareSplit      string = "fixed"
+)
+
+var (
+	ShareSplits = []string{
+		EqualShareSplit, PercentageShareSplit, FixedShareSplit,
+	}
+)
+
+// Participant is one of the people sharing the expenses of the household.
+type Participant struct {
+	ID   int
+	Name string
+}
+
+type Participants []Participant
+
+// Share is the part of a shared transaction of a participant.
+type Share struct {
+	ParticipantID int
+	Value         float32
+}
+
+type Shares []Share
+
+// SharedTransaction is a transaction paid by a participant and shared with the others.
+type SharedTransaction struct {
+	Entity        string
+	Kind          string
+	TransactionID int
+	PayerID       int
+	Split         string
+	Shares        Shares
+}
+
+type SharedTransactions []SharedTransaction
+
+// Settlement is a payment between two participants.
+type Settlement struct {
+	ID             int
+	SettlementDate time.Time
+	FromID         int
+	ToID           int
+	Amount         float32
+	Note           string
+}
+
+type Settlements []Settlement
@@ -0,0 +1,16 @@
+package models
+
+// Payee is a merchant or anyone else the money is paid to or received from.
+type Payee struct {
+	ID              int
+	Name            string
+	Aliases         []string
+	Patterns        []string
+	DefaultCategory string
+}
+
+type Payees []Payee
+
+const (
+	NoPayeeName string = "NO-PAYEE"
+)
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+const (
+	PendingTransactionStatus    string = "pending"
+	ClearedTransactionStatus    string = "cleared"
+	ReconciledTransactionStatus string = "reconciled"
+
+	OpenReconciliationStatus     string = "open"
+	FinishedReconciliationStatus string = "finished"
+)
+
+var (
+	TransactionStatuses = []string{
+		PendingTransactionStatus, ClearedTransactionStatus, ReconciledTransactionStatus,
+	}
+)
+
+// Reconciliation is the check of the transactions of an entity against a statement.
+type Reconciliation struct {
+	ID              int
+	Entity          string
+	Kind            string
+	StatementDate   time.Time
+	EndingBalance   float32
+	Status          string
+	StartedAt       time.Time
+	FinishedAt      time.Time
+	TransactionsIDs []int
+}
+
+type Reconciliations []Reconciliation
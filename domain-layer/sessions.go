@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Credential is the password of a user.
+type Credential struct {
+	UserID       int
+	Scopes       []string
+	PasswordHash string
+	FailedLogins int
+	LockedUntil  time.Time
+}
+
+// Session is the login of a user in the web front-end.
+type Session struct {
+	Hash      string
+	UserID    int
+	Scopes    []string
+	CSRFToken string
+	ExpiresAt time.Time
+}
+
+type Sessions []Session
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+const (
+	ReadScope  string = "read"
+	WriteScope string = "write"
+	AdminScope string = "admin"
+)
+
+var (
+	Scopes = []string{
+		ReadScope, WriteScope, AdminScope,
+	}
+)
+
+// Token is the bearer token of the requests of a user.
+type Token struct {
+	ID        int
+	UserID    int
+	Name      string
+	Scopes    []string
+	Hash      string
+	CreatedAt time.Time
+}
+
+type Tokens []Token
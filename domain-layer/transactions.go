@@ -12,10 +12,25 @@ type Transaction struct {
 	Categories      Categories
 	Kind            string
 	Amount          float32
+	Splits          Splits
+	PayeeID         int
+	Tags            []string
+	Memo            string
+	Fields          FieldValues
+	Status          string
 }
 
 type Transactions []Transaction
 
+// Split is the part of the amount of a transaction going to one category.
+type Split struct {
+	Category Category
+	Amount   float32
+	Note     string
+}
+
+type Splits []Split
+
 type TransactionEntity string
 type TransactionKind string
 
@@ -36,11 +51,6 @@ var (
 	TransactionKinds = []TransactionKind{
 		DebitBankAccountKind, DebitCreditBankAccountKind,
 	}
-
-	RefsToTransactions = &map[string]*Transactions{
-		EntityKindKey(TestEntity, DebitBankAccountKind):        nil,
-		EntityKindKey(Test2Entity, DebitCreditBankAccountKind): nil,
-	}
 )
 
 func EntityKindKey(entity TransactionEntity, kind TransactionKind) string {
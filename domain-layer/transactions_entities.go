@@ -9,6 +9,19 @@ type TransactionsEntity struct {
 
 type TransactionsEntities []TransactionsEntity
 
+// AccountNature tells how the debits and the credits change the balance of an entity.
+type AccountNature string
+
+const (
+	AssetAccountNature     AccountNature = "asset"
+	LiabilityAccountNature AccountNature = "liability"
+)
+
 var (
 	RefToTransactionsEntities *TransactionsEntities
+
+	AccountNatures = map[TransactionKind]AccountNature{
+		DebitBankAccountKind:       AssetAccountNature,
+		DebitCreditBankAccountKind: LiabilityAccountNature,
+	}
 )
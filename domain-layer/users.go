@@ -0,0 +1,10 @@
+package models
+
+// User is someone using the server.
+type User struct {
+	ID          int
+	Name        string
+	ServerAdmin bool
+}
+
+type Users []User
@@ -0,0 +1,77 @@
+package files
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ContentStore keeps files by the SHA-256 of their content.
+type ContentStore struct {
+	dir string
+}
+
+func NewContentStore(dir string) (*ContentStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &ContentStore{
+		dir: dir,
+	}, nil
+}
+
+func (cs ContentStore) path(hash string) (string, error) {
+	if len(hash) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid content hash %q", hash)
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return "", fmt.Errorf("invalid content hash %q", hash)
+	}
+	return filepath.Join(cs.dir, hash[:2], hash), nil
+}
+
+// Put stores the content, unless it's already there, and returns its hash.
+func (cs ContentStore) Put(content []byte) (string, error) {
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+	path, err := cs.path(hash)
+	if err != nil {
+		return "", err
+	}
+	if _, err = os.Stat(path); err == nil {
+		return hash, nil
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, content, 0644); err != nil {
+		return "", err
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+func (cs ContentStore) Get(hash string) ([]byte, error) {
+	path, err := cs.path(hash)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+func (cs ContentStore) Remove(hash string) error {
+	path, err := cs.path(hash)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
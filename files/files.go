@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -24,6 +25,58 @@ func NewFileWrapper(filePath string) *FileWrapper {
 	return fw
 }
 
+// CreateFileIfNotExists creates the file with the header line.
+func CreateFileIfNotExists(filePath, header string) error {
+	if _, err := os.Stat(filePath); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, []byte(fmt.Sprintf("%s\n", header)), 0644)
+}
+
+// MoveIfExists moves the file or the directory, if it exists.
+func MoveIfExists(oldPath, newPath string) error {
+	if _, err := os.Stat(oldPath); os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return err
+	}
+
+	return os.Rename(oldPath, newPath)
+}
+
+// CopyDir copies the directory recursively.
+func CopyDir(dir, newDir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		newPath := filepath.Join(newDir, rel)
+		if d.IsDir() {
+			return os.MkdirAll(newPath, 0755)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(newPath, content, 0644)
+	})
+}
+
 func (fw *FileWrapper) closeFile() error {
 	err := fw.file.Close()
 	if err != nil {
@@ -161,7 +214,7 @@ func (fw *FileWrapper) ReplaceLine(idxLine int, newLine string) error {
 }
 
 func (fw *FileWrapper) RemoveLine(idxLine int) error {
-	*fw.Lines = append((*fw.Lines)[0:idxLine], (*fw.Lines)[idxLine+1:len(*fw.Lines)-1]...)
+	*fw.Lines = append((*fw.Lines)[0:idxLine], (*fw.Lines)[idxLine+1:len(*fw.Lines)]...)
 
 	if err := fw.deleteFile(); err != nil {
 		return err
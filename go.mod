@@ -1,3 +1,5 @@
 module github.com/h-abranches-dev/daily-expenses-be
 
 go 1.21.5
+
+require golang.org/x/crypto v0.31.0
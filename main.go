@@ -1,24 +1,105 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
 	"github.com/h-abranches-dev/daily-expenses-be/service-layer/handlers"
 	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	startupActor   = "(startup)"
+	schedulerActor = "(scheduler)"
 )
 
 func main() {
+	bootstrapUser := flag.String("bootstrap-user", "admin",
+		"the user of the admin token created when there isn't any token")
+	flag.StringVar(&handlers.AllowedOrigin, "allowed-origin", "",
+		"the origin of the web front-end, e.g. https://expenses.example.com, which sends the session cookie")
+	flag.BoolVar(&handlers.SecureCookie, "secure-cookie", true,
+		"send the session cookie only over HTTPS, which a TLS proxy in front of the app serves")
+	cookieSameSite := flag.String("cookie-same-site", "lax",
+		"the SameSite of the session cookie, lax for a front-end of the same site or none for one of another site")
+	flag.Parse()
+
+	switch *cookieSameSite {
+	case "lax":
+		handlers.CookieSameSite = http.SameSiteLaxMode
+	case "none":
+		if !handlers.SecureCookie {
+			fmt.Printf("err: the session cookie must be secure with -cookie-same-site none\n")
+			return
+		}
+		handlers.CookieSameSite = http.SameSiteNoneMode
+	default:
+		fmt.Printf("err: -cookie-same-site must be lax or none\n")
+		return
+	}
+
+	if err := bootstrapAdminToken(*bootstrapUser); err != nil {
+		fmt.Printf("err: %s\n", err.Error())
+		return
+	}
+	startedAt := time.Now().UTC().Truncate(time.Second)
+	if err := forEachUser(auditedAppChange(normalizeTransactionsAmounts, startupActor, "normalize amounts",
+		startedAt)); err != nil {
+		fmt.Printf("err: %s\n", err.Error())
+		return
+	}
+	if err := forEachUser(auditedAppChange(recordDueLoansInstallments, startupActor, "record due installments",
+		startedAt)); err != nil {
+		fmt.Printf("err: %s\n", err.Error())
+		return
+	}
+	go scheduleDueLoansInstallments()
+
 	hmux := http.NewServeMux()
 	hmux.HandleFunc("/transactions", handlers.TransactionHandlerFunc)
 	hmux.HandleFunc("/transactions/", handlers.UpdateTransactionHandlerFunc)
 	hmux.HandleFunc("/transactions/types", handlers.TransactionsTypesHandlerFunc)
 	hmux.HandleFunc("/entities", handlers.TransactionsEntitiesHandlerFunc)
+	hmux.HandleFunc("/entities/", handlers.TransactionsEntityHandlerFunc)
 	hmux.HandleFunc("/categories", handlers.CategoriesHandlerFunc)
 	hmux.HandleFunc("/categories/", handlers.UpdateCategoryHandlerFunc)
 	hmux.HandleFunc("/transactions/categories/", handlers.TransactionsCategoriesHandlerFunc)
+	hmux.HandleFunc("/export", handlers.ExportHandlerFunc)
+	hmux.HandleFunc("/import", handlers.ImportHandlerFunc)
+	hmux.HandleFunc("/export/", handlers.ExportJournalHandlerFunc)
+	hmux.HandleFunc("/import/beancount", handlers.ImportBeancountHandlerFunc)
+	hmux.HandleFunc("/reports/summary", handlers.SummaryReportHandlerFunc)
+	hmux.HandleFunc("/reports/net-worth", handlers.NetWorthReportHandlerFunc)
+	hmux.HandleFunc("/reports/payees", handlers.PayeesReportHandlerFunc)
+	hmux.HandleFunc("/payees", handlers.PayeesHandlerFunc)
+	hmux.HandleFunc("/payees/", handlers.PayeeHandlerFunc)
+	hmux.HandleFunc("/reports/tags", handlers.TagsReportHandlerFunc)
+	hmux.HandleFunc("/tags", handlers.TagsHandlerFunc)
+	hmux.HandleFunc("/search", handlers.SearchHandlerFunc)
+	hmux.HandleFunc("/loans", handlers.LoansHandlerFunc)
+	hmux.HandleFunc("/loans/", handlers.LoanHandlerFunc)
+	hmux.HandleFunc("/reports/loans", handlers.LoansInterestReportHandlerFunc)
+	hmux.HandleFunc("/goals", handlers.GoalsHandlerFunc)
+	hmux.HandleFunc("/goals/", handlers.GoalHandlerFunc)
+	hmux.HandleFunc("/participants", handlers.ParticipantsHandlerFunc)
+	hmux.HandleFunc("/participants/", handlers.ParticipantHandlerFunc)
+	hmux.HandleFunc("/settlements", handlers.SettlementsHandlerFunc)
+	hmux.HandleFunc("/settlements/", handlers.SettlementHandlerFunc)
+	hmux.HandleFunc("/audit", handlers.AuditHandlerFunc)
+	hmux.HandleFunc("/users", handlers.UsersHandlerFunc)
+	hmux.HandleFunc("/tokens", handlers.TokensHandlerFunc)
+	hmux.HandleFunc("/tokens/", handlers.TokenHandlerFunc)
+	hmux.HandleFunc("/auth/login", handlers.LoginHandlerFunc)
+	hmux.HandleFunc("/auth/logout", handlers.LogoutHandlerFunc)
+	hmux.HandleFunc("/auth/me", handlers.MeHandlerFunc)
+	hmux.HandleFunc("/auth/password", handlers.PasswordHandlerFunc)
 
 	api := http.Server{
 		Addr:    ":8080",
-		Handler: hmux,
+		Handler: handlers.WithAuthentication(hmux),
 	}
 	fmt.Printf("Listening on port %q\n", api.Addr)
 	if err := api.ListenAndServe(); err != nil {
@@ -26,3 +107,116 @@ func main() {
 		return
 	}
 }
+
+func bootstrapAdminToken(user string) error {
+	tsRepo, err := repositories.GetTokensRepo()
+	if err != nil {
+		return err
+	}
+	usersRepo, err := repositories.GetUsersRepo()
+	if err != nil {
+		return err
+	}
+	token, err := services.BootstrapAdminToken(tsRepo, usersRepo, user, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		fmt.Printf("Created the admin token of the user %q, shown only now: %s\n", user, token)
+	}
+	return nil
+}
+
+func forEachUser(f func(ns *repositories.Namespace) error) error {
+	repositories.GlobalDBFilesMu.Lock()
+	usersRepo, err := repositories.GetUsersRepo()
+	if err != nil {
+		repositories.GlobalDBFilesMu.Unlock()
+		return err
+	}
+	us, err := services.GetAllUsers(usersRepo)
+	repositories.GlobalDBFilesMu.Unlock()
+	if err != nil {
+		return err
+	}
+	for _, u := range us {
+		ns := repositories.UserNamespace(u.Name)
+		ns.Lock()
+		err = f(ns)
+		ns.Unlock()
+		if err != nil {
+			return fmt.Errorf("user %q: %w", u.Name, err)
+		}
+	}
+	return nil
+}
+
+func auditedAppChange(f func(ns *repositories.Namespace) error, actor, change string,
+	at time.Time) func(ns *repositories.Namespace) error {
+	return func(ns *repositories.Namespace) error {
+		services.StartAuditingChanges(ns)
+		if err := f(ns); err != nil {
+			return err
+		}
+		before, after, err := services.TakeAuditedChanges(ns)
+		if err != nil {
+			return err
+		}
+		repo, err := ns.GetAuditRepo()
+		if err != nil {
+			return err
+		}
+		requestID := fmt.Sprintf("%s-%d", strings.Trim(actor, "()"), at.Unix())
+		return services.AddAuditEntries(repo, before, after, actor, requestID, change, at)
+	}
+}
+
+func normalizeTransactionsAmounts(ns *repositories.Namespace) error {
+	tsesRepo, err := ns.GetTransEntRepo()
+	if err != nil {
+		return err
+	}
+	repos, err := ns.GetAllRepos()
+	if err != nil {
+		return err
+	}
+	normalized, err := services.NormalizeTransactionsAmounts(tsesRepo, *repos)
+	if err != nil {
+		return err
+	}
+	if normalized > 0 {
+		fmt.Printf("Normalized the amounts of %d transactions\n", normalized)
+	}
+	return nil
+}
+
+func recordDueLoansInstallments(ns *repositories.Namespace) error {
+	lsRepo, err := ns.GetLoansRepo()
+	if err != nil {
+		return err
+	}
+	liRepo, err := ns.GetLoansInstallmentsRepo()
+	if err != nil {
+		return err
+	}
+	recorded, err := services.RecordDueInstallments(lsRepo, liRepo, time.Now().UTC().Truncate(24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if recorded > 0 {
+		fmt.Printf("Recorded %d installments of the loans\n", recorded)
+	}
+	return nil
+}
+
+func scheduleDueLoansInstallments() {
+	for {
+		now := time.Now().UTC()
+		time.Sleep(now.Truncate(24 * time.Hour).Add(24 * time.Hour).Sub(now))
+		at := time.Now().UTC().Truncate(time.Second)
+		if err := forEachUser(auditedAppChange(recordDueLoansInstallments, schedulerActor, "record due installments",
+			at)); err != nil {
+			fmt.Printf("err: %s\n", err.Error())
+		}
+	}
+}
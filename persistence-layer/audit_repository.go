@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AuditEntryDAO struct {
+	ID        int
+	Timestamp time.Time
+	Actor     string
+	RequestID string
+	Operation string
+	Record    string
+	Entity    string
+	Kind      string
+	RecordID  int
+	Request   string
+	Before    string
+	After     string
+}
+
+type AuditEntriesDAO []AuditEntryDAO
+
+type auditSnapshots struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditRepo keeps the audit log of the namespace.
+type AuditRepo struct {
+	*Repo
+}
+
+const (
+	auditDBFile   string = "audit.csv"
+	auditDBHeader string = "id;timestamp;actor;request_id;operation;record;entity;kind;record_id;request;snapshots"
+	auditPattern  string = "%d;%s;%s;%s;%s;%s;%s;%s;%d;%s;%s"
+)
+
+func NewAuditRepo(dbDir string) (*AuditRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, auditDBFile), auditDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo AuditRepo) ToRow(e AuditEntryDAO) (string, error) {
+	for _, column := range []string{e.Actor, e.RequestID, e.Operation, e.Record, e.Entity, e.Kind, e.Request} {
+		if strings.Index(column, repo.FileSeparator) != -1 {
+			return "", fmt.Errorf("invalid audit entry because includes the char %q => %q", repo.FileSeparator, column)
+		}
+	}
+	snapshots := auditSnapshots{}
+	if e.Before != "" {
+		snapshots.Before = json.RawMessage(e.Before)
+	}
+	if e.After != "" {
+		snapshots.After = json.RawMessage(e.After)
+	}
+	snapshotsJSON, err := json.Marshal(snapshots)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(auditPattern, e.ID, formatOptionalTime(e.Timestamp), e.Actor, e.RequestID, e.Operation, e.Record,
+		e.Entity, e.Kind, e.RecordID, e.Request, snapshotsJSON), nil
+}
+
+func (repo AuditRepo) rowToAuditEntry(row string) (AuditEntryDAO, error) {
+	emptyEntry := AuditEntryDAO{}
+	columns := strings.SplitN(row, repo.FileSeparator, 11)
+	if len(columns) != 11 {
+		return emptyEntry, fmt.Errorf("invalid audit entry row %q", row)
+	}
+	eID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyEntry, err
+	}
+	timestamp, err := parseOptionalTime(columns[1])
+	if err != nil {
+		return emptyEntry, err
+	}
+	rID, err := strconv.Atoi(columns[8])
+	if err != nil {
+		return emptyEntry, err
+	}
+	snapshots := auditSnapshots{}
+	if err = json.Unmarshal([]byte(columns[10]), &snapshots); err != nil {
+		return emptyEntry, err
+	}
+
+	return AuditEntryDAO{
+		ID:        eID,
+		Timestamp: timestamp,
+		Actor:     columns[2],
+		RequestID: columns[3],
+		Operation: columns[4],
+		Record:    columns[5],
+		Entity:    columns[6],
+		Kind:      columns[7],
+		RecordID:  rID,
+		Request:   columns[9],
+		Before:    string(snapshots.Before),
+		After:     string(snapshots.After),
+	}, nil
+}
+
+func (repo AuditRepo) GetAllAuditEntries() (AuditEntriesDAO, error) {
+	entries := AuditEntriesDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		e, err := repo.rowToAuditEntry((*rows)[i])
+		if err != nil {
+			return AuditEntriesDAO{}, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// AddAuditEntries appends the entries, with the IDs following the last one.
+func (repo AuditRepo) AddAuditEntries(es AuditEntriesDAO) error {
+	nextID := 1
+	if rows := repo.FileWrapper.Lines; len(*rows) > 2 {
+		lastID, _, _ := strings.Cut((*rows)[len(*rows)-2], repo.FileSeparator)
+		id, err := strconv.Atoi(lastID)
+		if err != nil {
+			return err
+		}
+		nextID = id + 1
+	}
+	for _, e := range es {
+		e.ID = nextID
+		line, err := repo.ToRow(e)
+		if err != nil {
+			return err
+		}
+		if err = repo.FileWrapper.AppendLine(line); err != nil {
+			return err
+		}
+		nextID++
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+type BalanceEntryDAO struct {
+	ID        int
+	Entity    string
+	Kind      string
+	Type      string
+	EntryDate time.Time
+	Amount    float32
+	Note      string
+}
+
+type BalanceEntriesDAO []BalanceEntryDAO
+
+// BalanceEntriesRepo keeps the opening balances and the adjustments of all the entities.
+type BalanceEntriesRepo struct {
+	*Repo
+}
+
+const (
+	balanceEntriesDBFile   string = "balance_entries.csv"
+	balanceEntriesDBHeader string = "id;entity;kind;type;entry_date;amount;note"
+	balanceEntriesPattern  string = "%d;%s;%s;%s;%s;%.2f;%s"
+)
+
+func NewBalanceEntriesRepo(dbDir string) (*BalanceEntriesRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, balanceEntriesDBFile), balanceEntriesDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &BalanceEntriesRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo BalanceEntriesRepo) ToRow(be BalanceEntryDAO) (string, error) {
+	if strings.ContainsAny(be.Note, "\r\n") {
+		return "", fmt.Errorf("invalid note because it has more than one line => %q", be.Note)
+	}
+	return fmt.Sprintf(balanceEntriesPattern, be.ID, be.Entity, be.Kind, be.Type, be.EntryDate.Format(utils.DateFormat),
+		be.Amount, be.Note), nil
+}
+
+func (repo BalanceEntriesRepo) rowToBalanceEntry(row string) (BalanceEntryDAO, error) {
+	emptyBalanceEntry := BalanceEntryDAO{}
+	columns := strings.SplitN(row, repo.FileSeparator, 7)
+	if len(columns) != 7 {
+		return emptyBalanceEntry, fmt.Errorf("invalid balance entry row %q", row)
+	}
+	beID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyBalanceEntry, err
+	}
+	entryDate, err := time.Parse(utils.DateFormat, columns[4])
+	if err != nil {
+		return emptyBalanceEntry, err
+	}
+	amount, err := strconv.ParseFloat(columns[5], 32)
+	if err != nil {
+		return emptyBalanceEntry, err
+	}
+
+	return BalanceEntryDAO{
+		ID:        beID,
+		Entity:    columns[1],
+		Kind:      columns[2],
+		Type:      columns[3],
+		EntryDate: entryDate,
+		Amount:    float32(amount),
+		Note:      columns[6],
+	}, nil
+}
+
+func (repo BalanceEntriesRepo) GetAllBalanceEntries() (BalanceEntriesDAO, error) {
+	entries := BalanceEntriesDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		be, err := repo.rowToBalanceEntry((*rows)[i])
+		if err != nil {
+			return BalanceEntriesDAO{}, err
+		}
+		entries = append(entries, be)
+	}
+	return entries, nil
+}
+
+// GetBalanceEntries gets the balance entries of the entity and kind.
+func (repo BalanceEntriesRepo) GetBalanceEntries(entity, kind string) (BalanceEntriesDAO, error) {
+	all, err := repo.GetAllBalanceEntries()
+	if err != nil {
+		return nil, err
+	}
+	entries := BalanceEntriesDAO{}
+	for _, be := range all {
+		if be.Entity == entity && be.Kind == kind {
+			entries = append(entries, be)
+		}
+	}
+	return entries, nil
+}
+
+func (repo BalanceEntriesRepo) AddBalanceEntry(be BalanceEntryDAO) error {
+	line, err := repo.ToRow(be)
+	if err != nil {
+		return err
+	}
+	return repo.FileWrapper.AppendLine(line)
+}
+
+func (repo BalanceEntriesRepo) DeleteBalanceEntry(beID int) error {
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.Split((*repo.FileWrapper.Lines)[i], repo.FileSeparator)[0] == strconv.Itoa(beID) {
+			return repo.FileWrapper.RemoveLine(i)
+		}
+	}
+	return fmt.Errorf("balance entry %d not found", beID)
+}
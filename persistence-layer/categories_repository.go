@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -18,11 +19,12 @@ type CategoriesRepo struct {
 }
 
 const (
-	categoriesDBFile string = "db/categories.csv"
+	categoriesDBFile   string = "categories.csv"
+	categoriesDBHeader string = "id;label"
 )
 
-func NewCategoriesRepo() (*CategoriesRepo, error) {
-	r, err := NewRepo(categoriesDBFile)
+func NewCategoriesRepo(dbDir string) (*CategoriesRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, categoriesDBFile), categoriesDBHeader)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CredentialDAO struct {
+	UserID       int
+	Scopes       []string
+	PasswordHash string
+	FailedLogins int
+	LockedUntil  time.Time
+}
+
+type CredentialsDAO []CredentialDAO
+
+// CredentialsRepo keeps the password hashes of the users.
+type CredentialsRepo struct {
+	*Repo
+}
+
+const (
+	credentialsDBFile   string = "credentials.csv"
+	credentialsDBHeader string = "user_id;scopes;password_hash;failed_logins;locked_until"
+	credentialsPattern  string = "%d;%s;%s;%d;%s"
+)
+
+func NewCredentialsRepo(dbDir string) (*CredentialsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, credentialsDBFile), credentialsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &CredentialsRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo CredentialsRepo) ToRow(c CredentialDAO) (string, error) {
+	if strings.Index(c.PasswordHash, repo.FileSeparator) != -1 {
+		return "", fmt.Errorf("invalid 'PasswordHash' because includes the char %q", repo.FileSeparator)
+	}
+	return fmt.Sprintf(credentialsPattern, c.UserID, strings.Join(c.Scopes, "#"), c.PasswordHash, c.FailedLogins,
+		formatOptionalTime(c.LockedUntil)), nil
+}
+
+func (repo CredentialsRepo) rowToCredential(row string) (CredentialDAO, error) {
+	emptyCredential := CredentialDAO{}
+	columns := strings.Split(row, repo.FileSeparator)
+	if len(columns) != 5 {
+		return emptyCredential, fmt.Errorf("invalid credential row of %d columns", len(columns))
+	}
+	uID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyCredential, err
+	}
+	failedLogins, err := strconv.Atoi(columns[3])
+	if err != nil {
+		return emptyCredential, err
+	}
+	lockedUntil, err := parseOptionalTime(columns[4])
+	if err != nil {
+		return emptyCredential, err
+	}
+
+	return CredentialDAO{
+		UserID:       uID,
+		Scopes:       strings.Split(columns[1], "#"),
+		PasswordHash: columns[2],
+		FailedLogins: failedLogins,
+		LockedUntil:  lockedUntil,
+	}, nil
+}
+
+func (repo CredentialsRepo) lineIndex(uID int) int {
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.Split((*repo.FileWrapper.Lines)[i], repo.FileSeparator)[0] == strconv.Itoa(uID) {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetCredential gets the credential of the user.
+func (repo CredentialsRepo) GetCredential(uID int) (CredentialDAO, bool, error) {
+	idx := repo.lineIndex(uID)
+	if idx == -1 {
+		return CredentialDAO{}, false, nil
+	}
+	c, err := repo.rowToCredential((*repo.FileWrapper.Lines)[idx])
+	if err != nil {
+		return CredentialDAO{}, false, err
+	}
+	return c, true, nil
+}
+
+// SetCredential adds or replaces the credential of the user.
+func (repo CredentialsRepo) SetCredential(c CredentialDAO) error {
+	line, err := repo.ToRow(c)
+	if err != nil {
+		return err
+	}
+	if idx := repo.lineIndex(c.UserID); idx != -1 {
+		return repo.FileWrapper.ReplaceLine(idx, line)
+	}
+	return repo.FileWrapper.AppendLine(line)
+}
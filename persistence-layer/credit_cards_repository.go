@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type CreditCardDAO struct {
+	Entity            string
+	Kind              string
+	ClosingDay        int
+	DueDay            int
+	MinimumPercentage float32
+	MinimumAmount     float32
+}
+
+type CreditCardsDAO []CreditCardDAO
+
+// CreditCardsRepo keeps the statement cycles of the credit cards.
+type CreditCardsRepo struct {
+	*Repo
+}
+
+const (
+	creditCardsDBFile   string = "credit_cards.csv"
+	creditCardsDBHeader string = "entity;kind;closing_day;due_day;minimum_percentage;minimum_amount"
+	creditCardsPattern  string = "%s;%s;%d;%d;%.2f;%.2f"
+)
+
+func NewCreditCardsRepo(dbDir string) (*CreditCardsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, creditCardsDBFile), creditCardsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &CreditCardsRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo CreditCardsRepo) ToRow(cc CreditCardDAO) string {
+	return fmt.Sprintf(creditCardsPattern, cc.Entity, cc.Kind, cc.ClosingDay, cc.DueDay, cc.MinimumPercentage,
+		cc.MinimumAmount)
+}
+
+func (repo CreditCardsRepo) rowToCreditCard(row string) (CreditCardDAO, error) {
+	emptyCreditCard := CreditCardDAO{}
+	columns := strings.Split(row, repo.FileSeparator)
+	if len(columns) != 6 {
+		return emptyCreditCard, fmt.Errorf("invalid credit card row %q", row)
+	}
+	closingDay, err := strconv.Atoi(columns[2])
+	if err != nil {
+		return emptyCreditCard, err
+	}
+	dueDay, err := strconv.Atoi(columns[3])
+	if err != nil {
+		return emptyCreditCard, err
+	}
+	minimumPercentage, err := strconv.ParseFloat(columns[4], 32)
+	if err != nil {
+		return emptyCreditCard, err
+	}
+	minimumAmount, err := strconv.ParseFloat(columns[5], 32)
+	if err != nil {
+		return emptyCreditCard, err
+	}
+
+	return CreditCardDAO{
+		Entity:            columns[0],
+		Kind:              columns[1],
+		ClosingDay:        closingDay,
+		DueDay:            dueDay,
+		MinimumPercentage: float32(minimumPercentage),
+		MinimumAmount:     float32(minimumAmount),
+	}, nil
+}
+
+func (repo CreditCardsRepo) GetAllCreditCards() (CreditCardsDAO, error) {
+	creditCards := CreditCardsDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		cc, err := repo.rowToCreditCard((*rows)[i])
+		if err != nil {
+			return CreditCardsDAO{}, err
+		}
+		creditCards = append(creditCards, cc)
+	}
+	return creditCards, nil
+}
+
+// GetCreditCard gets the statement cycle of the entity and kind.
+func (repo CreditCardsRepo) GetCreditCard(entity, kind string) (CreditCardDAO, bool, error) {
+	creditCards, err := repo.GetAllCreditCards()
+	if err != nil {
+		return CreditCardDAO{}, false, err
+	}
+	for _, cc := range creditCards {
+		if cc.Entity == entity && cc.Kind == kind {
+			return cc, true, nil
+		}
+	}
+	return CreditCardDAO{}, false, nil
+}
+
+func (repo CreditCardsRepo) lineIndex(entity, kind string) int {
+	prefix := fmt.Sprintf("%s;%s;", entity, kind)
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.HasPrefix((*repo.FileWrapper.Lines)[i], prefix) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetCreditCard adds or replaces the statement cycle of the entity.
+func (repo CreditCardsRepo) SetCreditCard(cc CreditCardDAO) error {
+	if idx := repo.lineIndex(cc.Entity, cc.Kind); idx != -1 {
+		return repo.FileWrapper.ReplaceLine(idx, repo.ToRow(cc))
+	}
+	return repo.FileWrapper.AppendLine(repo.ToRow(cc))
+}
+
+func (repo CreditCardsRepo) DeleteCreditCard(entity, kind string) error {
+	idxLineToRemove := repo.lineIndex(entity, kind)
+	if idxLineToRemove == -1 {
+		return fmt.Errorf("the entity %q of the kind %q isn't a credit card", entity, kind)
+	}
+	return repo.FileWrapper.RemoveLine(idxLineToRemove)
+}
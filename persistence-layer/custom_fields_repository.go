@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type CustomFieldDAO struct {
+	ID       int
+	Entity   string
+	Kind     string
+	Name     string
+	Type     string
+	Options  []string
+	Required bool
+}
+
+type CustomFieldsDAO []CustomFieldDAO
+
+// CustomFieldsRepo keeps the custom fields of the entities.
+type CustomFieldsRepo struct {
+	*Repo
+}
+
+const (
+	customFieldsDBFile         string = "custom_fields.csv"
+	customFieldsDBHeader       string = "id;entity;kind;name;type;options;required"
+	customFieldsListsSeparator string = "#"
+)
+
+func NewCustomFieldsRepo(dbDir string) (*CustomFieldsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, customFieldsDBFile), customFieldsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &CustomFieldsRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo CustomFieldsRepo) ToRow(cf CustomFieldDAO) (string, error) {
+	for _, v := range append([]string{cf.Name}, cf.Options...) {
+		if strings.Contains(v, repo.FileSeparator) || strings.Contains(v, customFieldsListsSeparator) {
+			return "", fmt.Errorf("invalid custom field because %q includes the char %q or %q", v, repo.FileSeparator,
+				customFieldsListsSeparator)
+		}
+	}
+
+	return fmt.Sprintf("%d;%s;%s;%s;%s;%s;%t", cf.ID, cf.Entity, cf.Kind, cf.Name, cf.Type,
+		strings.Join(cf.Options, customFieldsListsSeparator), cf.Required), nil
+}
+
+func (repo CustomFieldsRepo) rowToCustomField(row string) (CustomFieldDAO, error) {
+	emptyCustomField := CustomFieldDAO{}
+	columns := strings.Split(row, repo.FileSeparator)
+	if len(columns) != 7 {
+		return emptyCustomField, fmt.Errorf("invalid custom field row %q", row)
+	}
+	cfID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyCustomField, err
+	}
+	required, err := strconv.ParseBool(columns[6])
+	if err != nil {
+		return emptyCustomField, err
+	}
+
+	options := []string{}
+	if columns[5] != "" {
+		options = strings.Split(columns[5], customFieldsListsSeparator)
+	}
+	return CustomFieldDAO{
+		ID:       cfID,
+		Entity:   columns[1],
+		Kind:     columns[2],
+		Name:     columns[3],
+		Type:     columns[4],
+		Options:  options,
+		Required: required,
+	}, nil
+}
+
+func (repo CustomFieldsRepo) GetAllCustomFields() (CustomFieldsDAO, error) {
+	customFields := CustomFieldsDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		cf, err := repo.rowToCustomField((*rows)[i])
+		if err != nil {
+			return CustomFieldsDAO{}, err
+		}
+		customFields = append(customFields, cf)
+	}
+	return customFields, nil
+}
+
+func (repo CustomFieldsRepo) AddCustomField(cf CustomFieldDAO) error {
+	line, err := repo.ToRow(cf)
+	if err != nil {
+		return err
+	}
+
+	return repo.FileWrapper.AppendLine(line)
+}
+
+func (repo CustomFieldsRepo) lineIndex(cfID int) int {
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.Split((*repo.FileWrapper.Lines)[i], repo.FileSeparator)[0] == strconv.Itoa(cfID) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (repo CustomFieldsRepo) UpdateCustomField(cf CustomFieldDAO) error {
+	line, err := repo.ToRow(cf)
+	if err != nil {
+		return err
+	}
+
+	idxLineToUpdate := repo.lineIndex(cf.ID)
+	if idxLineToUpdate == -1 {
+		return fmt.Errorf("custom field %d not found", cf.ID)
+	}
+
+	return repo.FileWrapper.ReplaceLine(idxLineToUpdate, line)
+}
+
+func (repo CustomFieldsRepo) DeleteCustomField(cfID int) error {
+	idxLineToRemove := repo.lineIndex(cfID)
+	if idxLineToRemove == -1 {
+		return fmt.Errorf("custom field %d not found", cfID)
+	}
+
+	return repo.FileWrapper.RemoveLine(idxLineToRemove)
+}
@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+type GoalDAO struct {
+	ID         int
+	Name       string
+	Target     float32
+	StartDate  time.Time
+	TargetDate time.Time
+	Type       string
+	Entity     string
+	Kind       string
+	Label      string
+}
+
+type GoalsDAO []GoalDAO
+
+// GoalsRepo keeps the savings goals.
+type GoalsRepo struct {
+	*Repo
+}
+
+const (
+	goalsDBFile   string = "goals.csv"
+	goalsDBHeader string = "id;target;start_date;target_date;type;entity;kind;label;name"
+	goalsPattern  string = "%d;%.2f;%s;%s;%s;%s;%s;%s;%s"
+)
+
+func NewGoalsRepo(dbDir string) (*GoalsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, goalsDBFile), goalsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &GoalsRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo GoalsRepo) ToRow(g GoalDAO) (string, error) {
+	if strings.ContainsAny(g.Name, "\r\n") {
+		return "", fmt.Errorf("invalid name because it has more than one line => %q", g.Name)
+	}
+	if strings.Contains(g.Label, repo.FileSeparator) {
+		return "", fmt.Errorf("invalid goal because %q includes the char %q", g.Label, repo.FileSeparator)
+	}
+	return fmt.Sprintf(goalsPattern, g.ID, g.Target, g.StartDate.Format(utils.DateFormat),
+		g.TargetDate.Format(utils.DateFormat), g.Type, g.Entity, g.Kind, g.Label, g.Name), nil
+}
+
+func (repo GoalsRepo) rowToGoal(row string) (GoalDAO, error) {
+	emptyGoal := GoalDAO{}
+	columns := strings.SplitN(row, repo.FileSeparator, 9)
+	if len(columns) != 9 {
+		return emptyGoal, fmt.Errorf("invalid goal row %q", row)
+	}
+	gID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyGoal, err
+	}
+	target, err := strconv.ParseFloat(columns[1], 32)
+	if err != nil {
+		return emptyGoal, err
+	}
+	startDate, err := time.Parse(utils.DateFormat, columns[2])
+	if err != nil {
+		return emptyGoal, err
+	}
+	targetDate, err := time.Parse(utils.DateFormat, columns[3])
+	if err != nil {
+		return emptyGoal, err
+	}
+
+	return GoalDAO{
+		ID:         gID,
+		Name:       columns[8],
+		Target:     float32(target),
+		StartDate:  startDate,
+		TargetDate: targetDate,
+		Type:       columns[4],
+		Entity:     columns[5],
+		Kind:       columns[6],
+		Label:      columns[7],
+	}, nil
+}
+
+func (repo GoalsRepo) GetAllGoals() (GoalsDAO, error) {
+	goals := GoalsDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		g, err := repo.rowToGoal((*rows)[i])
+		if err != nil {
+			return GoalsDAO{}, err
+		}
+		goals = append(goals, g)
+	}
+	return goals, nil
+}
+
+func (repo GoalsRepo) AddGoal(g GoalDAO) error {
+	line, err := repo.ToRow(g)
+	if err != nil {
+		return err
+	}
+	return repo.FileWrapper.AppendLine(line)
+}
+
+func (repo GoalsRepo) lineIndex(gID int) int {
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.Split((*repo.FileWrapper.Lines)[i], repo.FileSeparator)[0] == strconv.Itoa(gID) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (repo GoalsRepo) UpdateGoal(g GoalDAO) error {
+	line, err := repo.ToRow(g)
+	if err != nil {
+		return err
+	}
+
+	idxLineToUpdate := repo.lineIndex(g.ID)
+	if idxLineToUpdate == -1 {
+		return fmt.Errorf("goal %d not found", g.ID)
+	}
+
+	return repo.FileWrapper.ReplaceLine(idxLineToUpdate, line)
+}
+
+func (repo GoalsRepo) DeleteGoal(gID int) error {
+	idxLineToRemove := repo.lineIndex(gID)
+	if idxLineToRemove == -1 {
+		return fmt.Errorf("goal %d not found", gID)
+	}
+
+	return repo.FileWrapper.RemoveLine(idxLineToRemove)
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type GrantDAO struct {
+	OwnerID int
+	Entity  string
+	Kind    string
+	UserID  int
+	Role    string
+}
+
+type GrantsDAO []GrantDAO
+
+// GrantsRepo keeps the roles of the users on the entities of other users.
+type GrantsRepo struct {
+	*Repo
+}
+
+const (
+	grantsDBFile   string = "grants.csv"
+	grantsDBHeader string = "owner_id;entity;kind;user_id;role"
+	grantsPattern  string = "%d;%s;%s;%d;%s"
+)
+
+func NewGrantsRepo(dbDir string) (*GrantsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, grantsDBFile), grantsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &GrantsRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo GrantsRepo) ToRow(g GrantDAO) string {
+	return fmt.Sprintf(grantsPattern, g.OwnerID, g.Entity, g.Kind, g.UserID, g.Role)
+}
+
+func (repo GrantsRepo) rowToGrant(row string) (GrantDAO, error) {
+	emptyGrant := GrantDAO{}
+	columns := strings.Split(row, repo.FileSeparator)
+	if len(columns) != 5 {
+		return emptyGrant, fmt.Errorf("invalid grant row %q", row)
+	}
+	oID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyGrant, err
+	}
+	uID, err := strconv.Atoi(columns[3])
+	if err != nil {
+		return emptyGrant, err
+	}
+
+	return GrantDAO{
+		OwnerID: oID,
+		Entity:  columns[1],
+		Kind:    columns[2],
+		UserID:  uID,
+		Role:    columns[4],
+	}, nil
+}
+
+func (repo GrantsRepo) GetAllGrants() (GrantsDAO, error) {
+	grants := GrantsDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		g, err := repo.rowToGrant((*rows)[i])
+		if err != nil {
+			return GrantsDAO{}, err
+		}
+		grants = append(grants, g)
+	}
+	return grants, nil
+}
+
+func (repo GrantsRepo) lineIndex(oID int, entity, kind string, uID int) int {
+	key := fmt.Sprintf("%d;%s;%s;%d;", oID, entity, kind, uID)
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.HasPrefix((*repo.FileWrapper.Lines)[i], key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetGrant adds or replaces the role of the user on the entity.
+func (repo GrantsRepo) SetGrant(g GrantDAO) error {
+	if idx := repo.lineIndex(g.OwnerID, g.Entity, g.Kind, g.UserID); idx != -1 {
+		return repo.FileWrapper.ReplaceLine(idx, repo.ToRow(g))
+	}
+	return repo.FileWrapper.AppendLine(repo.ToRow(g))
+}
+
+func (repo GrantsRepo) DeleteGrant(oID int, entity, kind string, uID int) error {
+	idxLineToRemove := repo.lineIndex(oID, entity, kind, uID)
+	if idxLineToRemove == -1 {
+		return fmt.Errorf("the user %d has no role on the entity %q of type %q", uID, entity, kind)
+	}
+	return repo.FileWrapper.RemoveLine(idxLineToRemove)
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type LoanInstallmentDAO struct {
+	LoanID        int
+	Number        int
+	Entity        string
+	Kind          string
+	TransactionID int
+}
+
+type LoansInstallmentsDAO []LoanInstallmentDAO
+
+// LoansInstallmentsRepo keeps the transactions of the loans installments.
+type LoansInstallmentsRepo struct {
+	*Repo
+}
+
+const (
+	loansInstallmentsDBFile   string = "loans_installments.csv"
+	loansInstallmentsDBHeader string = "loan_id;number;entity;kind;transaction_id"
+	loansInstallmentsPattern  string = "%d;%d;%s;%s;%d"
+)
+
+func NewLoansInstallmentsRepo(dbDir string) (*LoansInstallmentsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, loansInstallmentsDBFile), loansInstallmentsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &LoansInstallmentsRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo LoansInstallmentsRepo) ToRow(li LoanInstallmentDAO) string {
+	return fmt.Sprintf(loansInstallmentsPattern, li.LoanID, li.Number, li.Entity, li.Kind, li.TransactionID)
+}
+
+func (repo LoansInstallmentsRepo) rowToLoanInstallment(row string) (LoanInstallmentDAO, error) {
+	emptyLoanInstallment := LoanInstallmentDAO{}
+	columns := strings.Split(row, repo.FileSeparator)
+	if len(columns) != 5 {
+		return emptyLoanInstallment, fmt.Errorf("invalid loan installment row %q", row)
+	}
+	lID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyLoanInstallment, err
+	}
+	number, err := strconv.Atoi(columns[1])
+	if err != nil {
+		return emptyLoanInstallment, err
+	}
+	tID, err := strconv.Atoi(columns[4])
+	if err != nil {
+		return emptyLoanInstallment, err
+	}
+
+	return LoanInstallmentDAO{
+		LoanID:        lID,
+		Number:        number,
+		Entity:        columns[2],
+		Kind:          columns[3],
+		TransactionID: tID,
+	}, nil
+}
+
+func (repo LoansInstallmentsRepo) GetAllLoansInstallments() (LoansInstallmentsDAO, error) {
+	installments := LoansInstallmentsDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		li, err := repo.rowToLoanInstallment((*rows)[i])
+		if err != nil {
+			return LoansInstallmentsDAO{}, err
+		}
+		installments = append(installments, li)
+	}
+	return installments, nil
+}
+
+// GetLoanInstallments gets the recorded installments of the loan.
+func (repo LoansInstallmentsRepo) GetLoanInstallments(lID int) (LoansInstallmentsDAO, error) {
+	all, err := repo.GetAllLoansInstallments()
+	if err != nil {
+		return nil, err
+	}
+	installments := LoansInstallmentsDAO{}
+	for _, li := range all {
+		if li.LoanID == lID {
+			installments = append(installments, li)
+		}
+	}
+	return installments, nil
+}
+
+func (repo LoansInstallmentsRepo) AddLoanInstallment(li LoanInstallmentDAO) error {
+	return repo.FileWrapper.AppendLine(repo.ToRow(li))
+}
+
+// DeleteLoanInstallment unlinks the transaction from its installment.
+func (repo LoansInstallmentsRepo) DeleteLoanInstallment(entity, kind string, tID int) error {
+	suffix := fmt.Sprintf(";%s;%s;%d", entity, kind, tID)
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.HasSuffix((*repo.FileWrapper.Lines)[i], suffix) {
+			return repo.FileWrapper.RemoveLine(i)
+		}
+	}
+	return nil
+}
+
+// DeleteLoanInstallments unlinks all the installments of the loan.
+func (repo LoansInstallmentsRepo) DeleteLoanInstallments(lID int) error {
+	prefix := fmt.Sprintf("%d;", lID)
+	for i := len(*repo.FileWrapper.Lines) - 2; i >= 1; i-- {
+		if strings.HasPrefix((*repo.FileWrapper.Lines)[i], prefix) {
+			if err := repo.FileWrapper.RemoveLine(i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+type LoanDAO struct {
+	ID         int
+	Name       string
+	Principal  float32
+	AnnualRate float32
+	Term       int
+	StartDate  time.Time
+	Entity     string
+	Kind       string
+	Category   string
+	Recorded   int
+}
+
+type LoansDAO []LoanDAO
+
+// LoansRepo keeps the loans and the purchases in installments.
+type LoansRepo struct {
+	*Repo
+}
+
+const (
+	loansDBFile   string = "loans.csv"
+	loansDBHeader string = "id;principal;annual_rate;term;start_date;entity;kind;category;recorded;name"
+	loansPattern  string = "%d;%.2f;%.4f;%d;%s;%s;%s;%s;%d;%s"
+)
+
+func NewLoansRepo(dbDir string) (*LoansRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, loansDBFile), loansDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &LoansRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo LoansRepo) ToRow(l LoanDAO) (string, error) {
+	if strings.ContainsAny(l.Name, "\r\n") {
+		return "", fmt.Errorf("invalid name because it has more than one line => %q", l.Name)
+	}
+	return fmt.Sprintf(loansPattern, l.ID, l.Principal, l.AnnualRate, l.Term, l.StartDate.Format(utils.DateFormat),
+		l.Entity, l.Kind, l.Category, l.Recorded, l.Name), nil
+}
+
+func (repo LoansRepo) rowToLoan(row string) (LoanDAO, error) {
+	emptyLoan := LoanDAO{}
+	columns := strings.SplitN(row, repo.FileSeparator, 10)
+	if len(columns) != 10 {
+		return emptyLoan, fmt.Errorf("invalid loan row %q", row)
+	}
+	lID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyLoan, err
+	}
+	principal, err := strconv.ParseFloat(columns[1], 32)
+	if err != nil {
+		return emptyLoan, err
+	}
+	annualRate, err := strconv.ParseFloat(columns[2], 32)
+	if err != nil {
+		return emptyLoan, err
+	}
+	term, err := strconv.Atoi(columns[3])
+	if err != nil {
+		return emptyLoan, err
+	}
+	startDate, err := time.Parse(utils.DateFormat, columns[4])
+	if err != nil {
+		return emptyLoan, err
+	}
+	recorded, err := strconv.Atoi(columns[8])
+	if err != nil {
+		return emptyLoan, err
+	}
+
+	return LoanDAO{
+		ID:         lID,
+		Name:       columns[9],
+		Principal:  float32(principal),
+		AnnualRate: float32(annualRate),
+		Term:       term,
+		StartDate:  startDate,
+		Entity:     columns[5],
+		Kind:       columns[6],
+		Category:   columns[7],
+		Recorded:   recorded,
+	}, nil
+}
+
+func (repo LoansRepo) GetAllLoans() (LoansDAO, error) {
+	loans := LoansDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		l, err := repo.rowToLoan((*rows)[i])
+		if err != nil {
+			return LoansDAO{}, err
+		}
+		loans = append(loans, l)
+	}
+	return loans, nil
+}
+
+func (repo LoansRepo) AddLoan(l LoanDAO) error {
+	line, err := repo.ToRow(l)
+	if err != nil {
+		return err
+	}
+	return repo.FileWrapper.AppendLine(line)
+}
+
+func (repo LoansRepo) lineIndex(lID int) int {
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.Split((*repo.FileWrapper.Lines)[i], repo.FileSeparator)[0] == strconv.Itoa(lID) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (repo LoansRepo) UpdateLoan(l LoanDAO) error {
+	line, err := repo.ToRow(l)
+	if err != nil {
+		return err
+	}
+
+	idxLineToUpdate := repo.lineIndex(l.ID)
+	if idxLineToUpdate == -1 {
+		return fmt.Errorf("loan %d not found", l.ID)
+	}
+
+	return repo.FileWrapper.ReplaceLine(idxLineToUpdate, line)
+}
+
+func (repo LoansRepo) DeleteLoan(lID int) error {
+	idxLineToRemove := repo.lineIndex(lID)
+	if idxLineToRemove == -1 {
+		return fmt.Errorf("loan %d not found", lID)
+	}
+
+	return repo.FileWrapper.RemoveLine(idxLineToRemove)
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type ParticipantDAO struct {
+	ID   int
+	Name string
+}
+
+type ParticipantsDAO []ParticipantDAO
+
+// ParticipantsRepo keeps the people sharing the expenses.
+type ParticipantsRepo struct {
+	*Repo
+}
+
+const (
+	participantsDBFile   string = "participants.csv"
+	participantsDBHeader string = "id;name"
+	participantsPattern  string = "%d;%s"
+)
+
+func NewParticipantsRepo(dbDir string) (*ParticipantsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, participantsDBFile), participantsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &ParticipantsRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo ParticipantsRepo) ToRow(p ParticipantDAO) (string, error) {
+	if strings.ContainsAny(p.Name, "\r\n") {
+		return "", fmt.Errorf("invalid name because it has more than one line => %q", p.Name)
+	}
+	return fmt.Sprintf(participantsPattern, p.ID, p.Name), nil
+}
+
+func (repo ParticipantsRepo) rowToParticipant(row string) (ParticipantDAO, error) {
+	emptyParticipant := ParticipantDAO{}
+	columns := strings.SplitN(row, repo.FileSeparator, 2)
+	if len(columns) != 2 {
+		return emptyParticipant, fmt.Errorf("invalid participant row %q", row)
+	}
+	pID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyParticipant, err
+	}
+
+	return ParticipantDAO{
+		ID:   pID,
+		Name: columns[1],
+	}, nil
+}
+
+func (repo ParticipantsRepo) GetAllParticipants() (ParticipantsDAO, error) {
+	participants := ParticipantsDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		p, err := repo.rowToParticipant((*rows)[i])
+		if err != nil {
+			return ParticipantsDAO{}, err
+		}
+		participants = append(participants, p)
+	}
+	return participants, nil
+}
+
+func (repo ParticipantsRepo) AddParticipant(p ParticipantDAO) error {
+	line, err := repo.ToRow(p)
+	if err != nil {
+		return err
+	}
+	return repo.FileWrapper.AppendLine(line)
+}
+
+func (repo ParticipantsRepo) lineIndex(pID int) int {
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.Split((*repo.FileWrapper.Lines)[i], repo.FileSeparator)[0] == strconv.Itoa(pID) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (repo ParticipantsRepo) UpdateParticipant(p ParticipantDAO) error {
+	line, err := repo.ToRow(p)
+	if err != nil {
+		return err
+	}
+
+	idxLineToUpdate := repo.lineIndex(p.ID)
+	if idxLineToUpdate == -1 {
+		return fmt.Errorf("participant %d not found", p.ID)
+	}
+
+	return repo.FileWrapper.ReplaceLine(idxLineToUpdate, line)
+}
+
+func (repo ParticipantsRepo) DeleteParticipant(pID int) error {
+	idxLineToRemove := repo.lineIndex(pID)
+	if idxLineToRemove == -1 {
+		return fmt.Errorf("participant %d not found", pID)
+	}
+
+	return repo.FileWrapper.RemoveLine(idxLineToRemove)
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type PayeeDAO struct {
+	ID              int
+	Name            string
+	Aliases         []string
+	Patterns        []string
+	DefaultCategory string
+}
+
+type PayeesDAO []PayeeDAO
+
+type PayeesRepo struct {
+	*Repo
+}
+
+const (
+	payeesDBFile         string = "payees.csv"
+	payeesDBHeader       string = "id;name;aliases;patterns;default_category"
+	payeesListsSeparator string = "#"
+)
+
+func NewPayeesRepo(dbDir string) (*PayeesRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, payeesDBFile), payeesDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &PayeesRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo PayeesRepo) ToRow(p PayeeDAO) (string, error) {
+	values := append(append([]string{p.Name, p.DefaultCategory}, p.Aliases...), p.Patterns...)
+	for _, v := range values {
+		if strings.Contains(v, repo.FileSeparator) || strings.Contains(v, payeesListsSeparator) {
+			return "", fmt.Errorf("invalid payee because %q includes the char %q or %q", v, repo.FileSeparator,
+				payeesListsSeparator)
+		}
+		if strings.ContainsAny(v, "\r\n") {
+			return "", fmt.Errorf("invalid payee because %q has more than one line", v)
+		}
+	}
+
+	return fmt.Sprintf("%d;%s;%s;%s;%s", p.ID, p.Name, strings.Join(p.Aliases, payeesListsSeparator),
+		strings.Join(p.Patterns, payeesListsSeparator), p.DefaultCategory), nil
+}
+
+func (repo PayeesRepo) rowToPayee(row string) (PayeeDAO, error) {
+	emptyPayee := PayeeDAO{}
+	columns := strings.Split(row, repo.FileSeparator)
+	if len(columns) != 5 {
+		return emptyPayee, fmt.Errorf("invalid payee row %q", row)
+	}
+	pID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyPayee, err
+	}
+
+	list := func(column string) []string {
+		if column == "" {
+			return []string{}
+		}
+		return strings.Split(column, payeesListsSeparator)
+	}
+	return PayeeDAO{
+		ID:              pID,
+		Name:            columns[1],
+		Aliases:         list(columns[2]),
+		Patterns:        list(columns[3]),
+		DefaultCategory: columns[4],
+	}, nil
+}
+
+func (repo PayeesRepo) GetAllPayees() (PayeesDAO, error) {
+	payees := PayeesDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		payee, err := repo.rowToPayee((*rows)[i])
+		if err != nil {
+			return PayeesDAO{}, err
+		}
+		payees = append(payees, payee)
+	}
+	return payees, nil
+}
+
+func (repo PayeesRepo) AddPayee(p PayeeDAO) error {
+	line, err := repo.ToRow(p)
+	if err != nil {
+		return err
+	}
+
+	if err = repo.FileWrapper.AppendLine(line); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo PayeesRepo) lineIndex(pID int) int {
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.Split((*repo.FileWrapper.Lines)[i], repo.FileSeparator)[0] == strconv.Itoa(pID) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (repo PayeesRepo) UpdatePayee(p PayeeDAO) error {
+	line, err := repo.ToRow(p)
+	if err != nil {
+		return err
+	}
+
+	idxLineToUpdate := repo.lineIndex(p.ID)
+	if idxLineToUpdate == -1 {
+		return fmt.Errorf("payee %d not found", p.ID)
+	}
+
+	return repo.FileWrapper.ReplaceLine(idxLineToUpdate, line)
+}
+
+func (repo PayeesRepo) DeletePayee(pID int) error {
+	idxLineToRemove := repo.lineIndex(pID)
+	if idxLineToRemove == -1 {
+		return fmt.Errorf("payee %d not found", pID)
+	}
+
+	return repo.FileWrapper.RemoveLine(idxLineToRemove)
+}
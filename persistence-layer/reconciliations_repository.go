@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+type ReconciliationDAO struct {
+	ID              int
+	Entity          string
+	Kind            string
+	StatementDate   time.Time
+	EndingBalance   float32
+	Status          string
+	StartedAt       time.Time
+	FinishedAt      time.Time
+	TransactionsIDs []int
+}
+
+type ReconciliationsDAO []ReconciliationDAO
+
+type ReconciliationsRepo struct {
+	*Repo
+}
+
+const (
+	reconciliationsDBFile         string = "reconciliations.csv"
+	reconciliationsDBHeader       string = "id;entity;kind;statement_date;ending_balance;status;started_at;finished_at;transactions_ids"
+	reconciliationsPattern        string = "%d;%s;%s;%s;%.2f;%s;%s;%s;%s"
+	reconciliationsListsSeparator string = "#"
+)
+
+func NewReconciliationsRepo(dbDir string) (*ReconciliationsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, reconciliationsDBFile), reconciliationsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &ReconciliationsRepo{
+		Repo: r,
+	}, nil
+}
+
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func parseOptionalTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func (repo ReconciliationsRepo) ToRow(r ReconciliationDAO) string {
+	ids := make([]string, 0, len(r.TransactionsIDs))
+	for _, id := range r.TransactionsIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+	return fmt.Sprintf(reconciliationsPattern, r.ID, r.Entity, r.Kind, r.StatementDate.Format(utils.DateFormat),
+		r.EndingBalance, r.Status, formatOptionalTime(r.StartedAt), formatOptionalTime(r.FinishedAt),
+		strings.Join(ids, reconciliationsListsSeparator))
+}
+
+func (repo ReconciliationsRepo) rowToReconciliation(row string) (ReconciliationDAO, error) {
+	emptyReconciliation := ReconciliationDAO{}
+	columns := strings.Split(row, repo.FileSeparator)
+	if len(columns) != 9 {
+		return emptyReconciliation, fmt.Errorf("invalid reconciliation row %q", row)
+	}
+	rID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyReconciliation, err
+	}
+	statementDate, err := time.Parse(utils.DateFormat, columns[3])
+	if err != nil {
+		return emptyReconciliation, err
+	}
+	endingBalance, err := strconv.ParseFloat(columns[4], 32)
+	if err != nil {
+		return emptyReconciliation, err
+	}
+	startedAt, err := parseOptionalTime(columns[6])
+	if err != nil {
+		return emptyReconciliation, err
+	}
+	finishedAt, err := parseOptionalTime(columns[7])
+	if err != nil {
+		return emptyReconciliation, err
+	}
+	var ids []int
+	if columns[8] != "" {
+		for _, idStr := range strings.Split(columns[8], reconciliationsListsSeparator) {
+			id, err := strconv.Atoi(idStr)
+			if err != nil {
+				return emptyReconciliation, err
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	return ReconciliationDAO{
+		ID:              rID,
+		Entity:          columns[1],
+		Kind:            columns[2],
+		StatementDate:   statementDate,
+		EndingBalance:   float32(endingBalance),
+		Status:          columns[5],
+		StartedAt:       startedAt,
+		FinishedAt:      finishedAt,
+		TransactionsIDs: ids,
+	}, nil
+}
+
+func (repo ReconciliationsRepo) GetAllReconciliations() (ReconciliationsDAO, error) {
+	reconciliations := ReconciliationsDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		r, err := repo.rowToReconciliation((*rows)[i])
+		if err != nil {
+			return ReconciliationsDAO{}, err
+		}
+		reconciliations = append(reconciliations, r)
+	}
+	return reconciliations, nil
+}
+
+func (repo ReconciliationsRepo) AddReconciliation(r ReconciliationDAO) error {
+	return repo.FileWrapper.AppendLine(repo.ToRow(r))
+}
+
+func (repo ReconciliationsRepo) lineIndex(rID int) int {
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.Split((*repo.FileWrapper.Lines)[i], repo.FileSeparator)[0] == strconv.Itoa(rID) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (repo ReconciliationsRepo) UpdateReconciliation(r ReconciliationDAO) error {
+	idxLineToUpdate := repo.lineIndex(r.ID)
+	if idxLineToUpdate == -1 {
+		return fmt.Errorf("reconciliation %d not found", r.ID)
+	}
+
+	return repo.FileWrapper.ReplaceLine(idxLineToUpdate, repo.ToRow(r))
+}
+
+func (repo ReconciliationsRepo) DeleteReconciliation(rID int) error {
+	idxLineToRemove := repo.lineIndex(rID)
+	if idxLineToRemove == -1 {
+		return fmt.Errorf("reconciliation %d not found", rID)
+	}
+
+	return repo.FileWrapper.RemoveLine(idxLineToRemove)
+}
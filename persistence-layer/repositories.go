@@ -2,22 +2,81 @@ package repositories
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
 models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
 "github.com/h-abranches-dev/daily-expenses-be/files"
 )
 type Repo struct {
 	FileWrapper   *files.FileWrapper
 	FileSeparator string
+	Namespace *Namespace
 }
 
+// Namespace keeps the repositories of the data of a user.
+type Namespace struct {
+	sync.Mutex
+	user string
+	dir  string
+	namespaceRepos
+}
+
+type namespaceRepos struct {
+	transTestDebRepo       *TransactionsRepo
+	transTest2DebCredRepo  *TransactionsRepo
+	transEntRepo           *TransactionsEntitiesRepo
+	categoriesRepo         *CategoriesRepo
+	transSplitsRepo        *TransactionsSplitsRepo
+	payeesRepo             *PayeesRepo
+	transPayeesRepo        *TransactionsPayeesRepo
+	tagsRepo               *TagsRepo
+	transTagsRepo          *TransactionsTagsRepo
+	transAttachmentsRepo   *TransactionsAttachmentsRepo
+	customFieldsRepo       *CustomFieldsRepo
+	transMemosRepo         *TransactionsMemosRepo
+	transFieldsRepo        *TransactionsFieldsRepo
+	transStatusesRepo      *TransactionsStatusesRepo
+	reconciliationsRepo    *ReconciliationsRepo
+	balanceEntriesRepo     *BalanceEntriesRepo
+	creditCardsRepo        *CreditCardsRepo
+	statementsPaymentsRepo *StatementsPaymentsRepo
+	loansRepo              *LoansRepo
+	loansInstallmentsRepo  *LoansInstallmentsRepo
+	goalsRepo              *GoalsRepo
+	participantsRepo       *ParticipantsRepo
+	transSharesRepo        *TransactionsSharesRepo
+	settlementsRepo        *SettlementsRepo
+	auditRepo              *AuditRepo
+}
+
+const (
+	dBDir string = "db"
+	stagingDirSuffix string = ".staging"
+)
+
 var (
-	transTestDebRepo      *TransactionsRepo
-	transTest2DebCredRepo *TransactionsRepo
-	transEntRepo          *TransactionsEntitiesRepo
-	categoriesRepo        *CategoriesRepo
+	globalDBFiles = []string{
+		usersDBFile, tokensDBFile, credentialsDBFile, sessionsDBFile, grantsDBFile,
+	}
+)
+
+var (
+	usersRepo       *UsersRepo
+	tokensRepo      *TokensRepo
+	credentialsRepo *CredentialsRepo
+	sessionsRepo    *SessionsRepo
+	grantsRepo      *GrantsRepo
+	GlobalDBFilesMu sync.Mutex
+	namespaces      = map[string]*Namespace{}
+	namespacesMu    sync.Mutex
 )
 
-func NewRepo(dbFile string) (*Repo, error) {
+func NewRepo(dbFile, dbHeader string) (*Repo, error) {
+	if err := files.CreateFileIfNotExists(dbFile, dbHeader); err != nil {
+		return nil, err
+	}
 	fw := files.NewFileWrapper(dbFile)
 	if fw == nil {
 		return nil, fmt.Errorf("database file %q not found", dbFile)
@@ -28,64 +87,454 @@ func NewRepo(dbFile string) (*Repo, error) {
 	}, nil
 }
 
-func GetAllRepos() (*[]*TransactionsRepo, error) {
-	r1, err := GetTransTestDebRepo()
+// UserDBDir is the directory with the database files of the user.
+func UserDBDir(user string) string {
+	return filepath.Join(dBDir, user)
+}
+
+// UserNamespace is the namespace of the user.
+func UserNamespace(user string) *Namespace {
+	namespacesMu.Lock()
+	defer namespacesMu.Unlock()
+	ns, found := namespaces[user]
+	if !found {
+		ns = &Namespace{user: user, dir: UserDBDir(user)}
+		namespaces[user] = ns
+	}
+	return ns
+}
+
+func (ns *Namespace) User() string {
+	return ns.user
+}
+
+// Stage copies the data of the namespace, to be kept with Commit or dropped with Discard.
+func (ns *Namespace) Stage() (*Namespace, error) {
+	stagingDir := ns.dir + stagingDirSuffix
+	if err := os.RemoveAll(stagingDir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(ns.dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := files.CopyDir(ns.dir, stagingDir); err != nil {
+		return nil, err
+	}
+	return &Namespace{user: ns.user, dir: stagingDir}, nil
+}
+
+// Commit replaces the data of the namespace with the staged copy.
+func (ns *Namespace) Commit(staged *Namespace) error {
+	if staged.dir != ns.dir+stagingDirSuffix {
+		return fmt.Errorf("the namespace of the user %q isn't staged", ns.user)
+	}
+	oldDir := ns.dir + ".old"
+	if err := os.RemoveAll(oldDir); err != nil {
+		return err
+	}
+	if err := os.Rename(ns.dir, oldDir); err != nil {
+		return err
+	}
+	if err := os.Rename(staged.dir, ns.dir); err != nil {
+		return err
+	}
+	ns.namespaceRepos = namespaceRepos{}
+	return os.RemoveAll(oldDir)
+}
+
+// Discard drops the staged copy of the data of the namespace.
+func (ns *Namespace) Discard(staged *Namespace) error {
+	if staged.dir != ns.dir+stagingDirSuffix {
+		return fmt.Errorf("the namespace of the user %q isn't staged", ns.user)
+	}
+	return os.RemoveAll(staged.dir)
+}
+
+// AdoptLegacyDBFiles moves the database files of the single-user server into the namespace.
+func AdoptLegacyDBFiles(user string) error {
+	legacyFiles, err := filepath.Glob(filepath.Join(dBDir, "*.csv"))
+	if err != nil {
+		return err
+	}
+	for _, legacyFile := range legacyFiles {
+		if slices.Contains(globalDBFiles, filepath.Base(legacyFile)) {
+			continue
+		}
+		if err = files.MoveIfExists(legacyFile, filepath.Join(UserDBDir(user), filepath.Base(legacyFile))); err != nil {
+			return err
+		}
+	}
+	return files.MoveIfExists(attachmentsContentsDir, filepath.Join(UserDBDir(user), attachmentsContentsDir))
+}
+
+func GetUsersRepo() (*UsersRepo, error) {
+	if usersRepo == nil {
+		var err error
+		if usersRepo, err = NewUsersRepo(dBDir); err != nil {
+			return nil, err
+		}
+	}
+	return usersRepo, nil
+}
+
+func GetTokensRepo() (*TokensRepo, error) {
+	if tokensRepo == nil {
+		var err error
+		if tokensRepo, err = NewTokensRepo(dBDir); err != nil {
+			return nil, err
+		}
+	}
+	return tokensRepo, nil
+}
+
+func GetCredentialsRepo() (*CredentialsRepo, error) {
+	if credentialsRepo == nil {
+		var err error
+		if credentialsRepo, err = NewCredentialsRepo(dBDir); err != nil {
+			return nil, err
+		}
+	}
+	return credentialsRepo, nil
+}
+
+func GetSessionsRepo() (*SessionsRepo, error) {
+	if sessionsRepo == nil {
+		var err error
+		if sessionsRepo, err = NewSessionsRepo(dBDir); err != nil {
+			return nil, err
+		}
+	}
+	return sessionsRepo, nil
+}
+
+func GetGrantsRepo() (*GrantsRepo, error) {
+	if grantsRepo == nil {
+		var err error
+		if grantsRepo, err = NewGrantsRepo(dBDir); err != nil {
+			return nil, err
+		}
+	}
+	return grantsRepo, nil
+}
+
+func (ns *Namespace) GetAllRepos() (*[]*TransactionsRepo, error) {
+	r1, err := ns.GetTransTestDebRepo()
 	if err != nil {
 		return nil, err
 	}
-	r2, err := GetTransTest2DebCredRepo()
+	r2, err := ns.GetTransTest2DebCredRepo()
 	if err != nil {
 		return nil, err
 	}
 	return &[]*TransactionsRepo{r1, r2}, nil
 }
 
-func GetTransTestDebRepo() (*TransactionsRepo, error) {
-	if transTestDebRepo == nil {
-		var err error
-		if transTestDebRepo, err = NewTransactionsRepo(string(models.DebitBankAccountKind), string(models.TestEntity)); err != nil {
+func (ns *Namespace) GetTransTestDebRepo() (*TransactionsRepo, error) {
+	if ns.transTestDebRepo == nil {
+		repo, err := NewTransactionsRepo(ns.dir, string(models.DebitBankAccountKind), string(models.TestEntity))
+		if err != nil {
 			return nil, err
 		}
+		repo.Namespace = ns
+		ns.transTestDebRepo = repo
 	}
-	return transTestDebRepo, nil
+	return ns.transTestDebRepo, nil
 }
 
-func GetTransTest2DebCredRepo() (*TransactionsRepo, error) {
-	if transTest2DebCredRepo == nil {
-		var err error
-		if transTest2DebCredRepo, err = NewTransactionsRepo(string(models.DebitCreditBankAccountKind), string(models.Test2Entity)); err != nil {
+func (ns *Namespace) GetTransTest2DebCredRepo() (*TransactionsRepo, error) {
+	if ns.transTest2DebCredRepo == nil {
+		repo, err := NewTransactionsRepo(ns.dir, string(models.DebitCreditBankAccountKind), string(models.Test2Entity))
+		if err != nil {
 			return nil, err
 		}
+		repo.Namespace = ns
+		ns.transTest2DebCredRepo = repo
 	}
-	return transTest2DebCredRepo, nil
+	return ns.transTest2DebCredRepo, nil
 }
 
-func GetTransRepo(kind, entity string) (*TransactionsRepo, error) {
+func (ns *Namespace) GetTransRepo(kind, entity string) (*TransactionsRepo, error) {
 	if kind == string(models.DebitBankAccountKind) && entity == string(models.TestEntity) {
-		return GetTransTestDebRepo()
+		return ns.GetTransTestDebRepo()
 	}
 	if kind == string(models.DebitCreditBankAccountKind) && entity == string(models.Test2Entity) {
-		return GetTransTest2DebCredRepo()
+		return ns.GetTransTest2DebCredRepo()
 	}
 	return nil, fmt.Errorf("for the kind %q and entity %q any repository was found", kind, entity)
 }
 
-func GetTransEntRepo() (*TransactionsEntitiesRepo, error) {
-	if transEntRepo == nil {
-		var err error
-		if transEntRepo, err = NewTransactionsEntitiesRepo(); err != nil {
+func (ns *Namespace) GetTransEntRepo() (*TransactionsEntitiesRepo, error) {
+	if ns.transEntRepo == nil {
+		repo, err := NewTransactionsEntitiesRepo(ns.dir)
+		if err != nil {
 			return nil, err
 		}
+		repo.Namespace = ns
+		ns.transEntRepo = repo
 	}
-	return transEntRepo, nil
+	return ns.transEntRepo, nil
 }
 
-func GetCategoriesRepo() (*CategoriesRepo, error) {
-	if categoriesRepo == nil {
-		var err error
-		if categoriesRepo, err = NewCategoriesRepo(); err != nil {
+func (ns *Namespace) GetCategoriesRepo() (*CategoriesRepo, error) {
+	if ns.categoriesRepo == nil {
+		repo, err := NewCategoriesRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.categoriesRepo = repo
+	}
+	return ns.categoriesRepo, nil
+}
+
+func (ns *Namespace) GetTransSplitsRepo() (*TransactionsSplitsRepo, error) {
+	if ns.transSplitsRepo == nil {
+		repo, err := NewTransactionsSplitsRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.transSplitsRepo = repo
+	}
+	return ns.transSplitsRepo, nil
+}
+
+func (ns *Namespace) GetPayeesRepo() (*PayeesRepo, error) {
+	if ns.payeesRepo == nil {
+		repo, err := NewPayeesRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.payeesRepo = repo
+	}
+	return ns.payeesRepo, nil
+}
+
+func (ns *Namespace) GetTransPayeesRepo() (*TransactionsPayeesRepo, error) {
+	if ns.transPayeesRepo == nil {
+		repo, err := NewTransactionsPayeesRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.transPayeesRepo = repo
+	}
+	return ns.transPayeesRepo, nil
+}
+
+func (ns *Namespace) GetTagsRepo() (*TagsRepo, error) {
+	if ns.tagsRepo == nil {
+		repo, err := NewTagsRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.tagsRepo = repo
+	}
+	return ns.tagsRepo, nil
+}
+
+func (ns *Namespace) GetTransTagsRepo() (*TransactionsTagsRepo, error) {
+	if ns.transTagsRepo == nil {
+		repo, err := NewTransactionsTagsRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.transTagsRepo = repo
+	}
+	return ns.transTagsRepo, nil
+}
+
+func (ns *Namespace) GetTransAttachmentsRepo() (*TransactionsAttachmentsRepo, error) {
+	if ns.transAttachmentsRepo == nil {
+		repo, err := NewTransactionsAttachmentsRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.transAttachmentsRepo = repo
+	}
+	return ns.transAttachmentsRepo, nil
+}
+
+func (ns *Namespace) GetCustomFieldsRepo() (*CustomFieldsRepo, error) {
+	if ns.customFieldsRepo == nil {
+		repo, err := NewCustomFieldsRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.customFieldsRepo = repo
+	}
+	return ns.customFieldsRepo, nil
+}
+
+func (ns *Namespace) GetTransMemosRepo() (*TransactionsMemosRepo, error) {
+	if ns.transMemosRepo == nil {
+		repo, err := NewTransactionsMemosRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.transMemosRepo = repo
+	}
+	return ns.transMemosRepo, nil
+}
+
+func (ns *Namespace) GetTransFieldsRepo() (*TransactionsFieldsRepo, error) {
+	if ns.transFieldsRepo == nil {
+		repo, err := NewTransactionsFieldsRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.transFieldsRepo = repo
+	}
+	return ns.transFieldsRepo, nil
+}
+
+func (ns *Namespace) GetTransStatusesRepo() (*TransactionsStatusesRepo, error) {
+	if ns.transStatusesRepo == nil {
+		repo, err := NewTransactionsStatusesRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.transStatusesRepo = repo
+	}
+	return ns.transStatusesRepo, nil
+}
+
+func (ns *Namespace) GetReconciliationsRepo() (*ReconciliationsRepo, error) {
+	if ns.reconciliationsRepo == nil {
+		repo, err := NewReconciliationsRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.reconciliationsRepo = repo
+	}
+	return ns.reconciliationsRepo, nil
+}
+
+func (ns *Namespace) GetBalanceEntriesRepo() (*BalanceEntriesRepo, error) {
+	if ns.balanceEntriesRepo == nil {
+		repo, err := NewBalanceEntriesRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.balanceEntriesRepo = repo
+	}
+	return ns.balanceEntriesRepo, nil
+}
+
+func (ns *Namespace) GetCreditCardsRepo() (*CreditCardsRepo, error) {
+	if ns.creditCardsRepo == nil {
+		repo, err := NewCreditCardsRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.creditCardsRepo = repo
+	}
+	return ns.creditCardsRepo, nil
+}
+
+func (ns *Namespace) GetStatementsPaymentsRepo() (*StatementsPaymentsRepo, error) {
+	if ns.statementsPaymentsRepo == nil {
+		repo, err := NewStatementsPaymentsRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.statementsPaymentsRepo = repo
+	}
+	return ns.statementsPaymentsRepo, nil
+}
+
+func (ns *Namespace) GetLoansRepo() (*LoansRepo, error) {
+	if ns.loansRepo == nil {
+		repo, err := NewLoansRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.loansRepo = repo
+	}
+	return ns.loansRepo, nil
+}
+
+func (ns *Namespace) GetLoansInstallmentsRepo() (*LoansInstallmentsRepo, error) {
+	if ns.loansInstallmentsRepo == nil {
+		repo, err := NewLoansInstallmentsRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.loansInstallmentsRepo = repo
+	}
+	return ns.loansInstallmentsRepo, nil
+}
+
+func (ns *Namespace) GetGoalsRepo() (*GoalsRepo, error) {
+	if ns.goalsRepo == nil {
+		repo, err := NewGoalsRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.goalsRepo = repo
+	}
+	return ns.goalsRepo, nil
+}
+
+func (ns *Namespace) GetParticipantsRepo() (*ParticipantsRepo, error) {
+	if ns.participantsRepo == nil {
+		repo, err := NewParticipantsRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.participantsRepo = repo
+	}
+	return ns.participantsRepo, nil
+}
+
+func (ns *Namespace) GetTransSharesRepo() (*TransactionsSharesRepo, error) {
+	if ns.transSharesRepo == nil {
+		repo, err := NewTransactionsSharesRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.transSharesRepo = repo
+	}
+	return ns.transSharesRepo, nil
+}
+
+func (ns *Namespace) GetSettlementsRepo() (*SettlementsRepo, error) {
+	if ns.settlementsRepo == nil {
+		repo, err := NewSettlementsRepo(ns.dir)
+		if err != nil {
+			return nil, err
+		}
+		repo.Namespace = ns
+		ns.settlementsRepo = repo
+	}
+	return ns.settlementsRepo, nil
+}
+
+func (ns *Namespace) GetAuditRepo() (*AuditRepo, error) {
+	if ns.auditRepo == nil {
+		repo, err := NewAuditRepo(ns.dir)
+		if err != nil {
 			return nil, err
 		}
+		repo.Namespace = ns
+		ns.auditRepo = repo
 	}
-	return categoriesRepo, nil
+	return ns.auditRepo, nil
 }
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SessionDAO struct {
+	Hash      string
+	UserID    int
+	Scopes    []string
+	CSRFToken string
+	ExpiresAt time.Time
+}
+
+type SessionsDAO []SessionDAO
+
+// SessionsRepo keeps the sessions of all the users.
+type SessionsRepo struct {
+	*Repo
+}
+
+const (
+	sessionsDBFile   string = "sessions.csv"
+	sessionsDBHeader string = "session_hash;user_id;scopes;csrf_token;expires_at"
+	sessionsPattern  string = "%s;%d;%s;%s;%s"
+)
+
+func NewSessionsRepo(dbDir string) (*SessionsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, sessionsDBFile), sessionsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionsRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo SessionsRepo) ToRow(s SessionDAO) string {
+	return fmt.Sprintf(sessionsPattern, s.Hash, s.UserID, strings.Join(s.Scopes, "#"), s.CSRFToken,
+		s.ExpiresAt.UTC().Format(time.RFC3339))
+}
+
+func (repo SessionsRepo) rowToSession(row string) (SessionDAO, error) {
+	emptySession := SessionDAO{}
+	columns := strings.Split(row, repo.FileSeparator)
+	if len(columns) != 5 {
+		return emptySession, fmt.Errorf("invalid session row of %d columns", len(columns))
+	}
+	uID, err := strconv.Atoi(columns[1])
+	if err != nil {
+		return emptySession, err
+	}
+	expiresAt, err := time.Parse(time.RFC3339, columns[4])
+	if err != nil {
+		return emptySession, err
+	}
+
+	return SessionDAO{
+		Hash:      columns[0],
+		UserID:    uID,
+		Scopes:    strings.Split(columns[2], "#"),
+		CSRFToken: columns[3],
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (repo SessionsRepo) GetAllSessions() (SessionsDAO, error) {
+	sessions := SessionsDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		s, err := repo.rowToSession((*rows)[i])
+		if err != nil {
+			return SessionsDAO{}, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+func (repo SessionsRepo) AddSession(s SessionDAO) error {
+	return repo.FileWrapper.AppendLine(repo.ToRow(s))
+}
+
+func (repo SessionsRepo) DeleteSession(hash string) error {
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.Split((*repo.FileWrapper.Lines)[i], repo.FileSeparator)[0] == hash {
+			return repo.FileWrapper.RemoveLine(i)
+		}
+	}
+	return fmt.Errorf("session not found")
+}
+
+// DeleteUserSessions deletes all the sessions of the user, e.g. when the password changes.
+func (repo SessionsRepo) DeleteUserSessions(uID int) error {
+	for i := len(*repo.FileWrapper.Lines) - 2; i >= 1; i-- {
+		if strings.Split((*repo.FileWrapper.Lines)[i], repo.FileSeparator)[1] == strconv.Itoa(uID) {
+			if err := repo.FileWrapper.RemoveLine(i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+type SettlementDAO struct {
+	ID             int
+	SettlementDate time.Time
+	FromID         int
+	ToID           int
+	Amount         float32
+	Note           string
+}
+
+type SettlementsDAO []SettlementDAO
+
+// SettlementsRepo keeps the payments between the participants.
+type SettlementsRepo struct {
+	*Repo
+}
+
+const (
+	settlementsDBFile   string = "settlements.csv"
+	settlementsDBHeader string = "id;settlement_date;from_id;to_id;amount;note"
+	settlementsPattern  string = "%d;%s;%d;%d;%.2f;%s"
+)
+
+func NewSettlementsRepo(dbDir string) (*SettlementsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, settlementsDBFile), settlementsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &SettlementsRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo SettlementsRepo) ToRow(s SettlementDAO) (string, error) {
+	if strings.ContainsAny(s.Note, "\r\n") {
+		return "", fmt.Errorf("invalid note because it has more than one line => %q", s.Note)
+	}
+	return fmt.Sprintf(settlementsPattern, s.ID, s.SettlementDate.Format(utils.DateFormat), s.FromID, s.ToID, s.Amount,
+		s.Note), nil
+}
+
+func (repo SettlementsRepo) rowToSettlement(row string) (SettlementDAO, error) {
+	emptySettlement := SettlementDAO{}
+	columns := strings.SplitN(row, repo.FileSeparator, 6)
+	if len(columns) != 6 {
+		return emptySettlement, fmt.Errorf("invalid settlement row %q", row)
+	}
+	sID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptySettlement, err
+	}
+	settlementDate, err := time.Parse(utils.DateFormat, columns[1])
+	if err != nil {
+		return emptySettlement, err
+	}
+	fromID, err := strconv.Atoi(columns[2])
+	if err != nil {
+		return emptySettlement, err
+	}
+	toID, err := strconv.Atoi(columns[3])
+	if err != nil {
+		return emptySettlement, err
+	}
+	amount, err := strconv.ParseFloat(columns[4], 32)
+	if err != nil {
+		return emptySettlement, err
+	}
+
+	return SettlementDAO{
+		ID:             sID,
+		SettlementDate: settlementDate,
+		FromID:         fromID,
+		ToID:           toID,
+		Amount:         float32(amount),
+		Note:           columns[5],
+	}, nil
+}
+
+func (repo SettlementsRepo) GetAllSettlements() (SettlementsDAO, error) {
+	settlements := SettlementsDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		s, err := repo.rowToSettlement((*rows)[i])
+		if err != nil {
+			return SettlementsDAO{}, err
+		}
+		settlements = append(settlements, s)
+	}
+	return settlements, nil
+}
+
+func (repo SettlementsRepo) AddSettlement(s SettlementDAO) error {
+	line, err := repo.ToRow(s)
+	if err != nil {
+		return err
+	}
+	return repo.FileWrapper.AppendLine(line)
+}
+
+func (repo SettlementsRepo) DeleteSettlement(sID int) error {
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.Split((*repo.FileWrapper.Lines)[i], repo.FileSeparator)[0] == strconv.Itoa(sID) {
+			return repo.FileWrapper.RemoveLine(i)
+		}
+	}
+	return fmt.Errorf("settlement %d not found", sID)
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type StatementPaymentDAO struct {
+	CardEntity    string
+	CardKind      string
+	Statement     string
+	Entity        string
+	Kind          string
+	TransactionID int
+}
+
+type StatementsPaymentsDAO []StatementPaymentDAO
+
+// StatementsPaymentsRepo keeps the payments of the credit cards statements.
+type StatementsPaymentsRepo struct {
+	*Repo
+}
+
+const (
+	statementsPaymentsDBFile   string = "statements_payments.csv"
+	statementsPaymentsDBHeader string = "card_entity;card_kind;statement;entity;kind;transaction_id"
+	statementsPaymentsPattern  string = "%s;%s;%s;%s;%s;%d"
+)
+
+func NewStatementsPaymentsRepo(dbDir string) (*StatementsPaymentsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, statementsPaymentsDBFile), statementsPaymentsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &StatementsPaymentsRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo StatementsPaymentsRepo) ToRow(sp StatementPaymentDAO) string {
+	return fmt.Sprintf(statementsPaymentsPattern, sp.CardEntity, sp.CardKind, sp.Statement, sp.Entity, sp.Kind,
+		sp.TransactionID)
+}
+
+func (repo StatementsPaymentsRepo) rowToStatementPayment(row string) (StatementPaymentDAO, error) {
+	emptyStatementPayment := StatementPaymentDAO{}
+	columns := strings.Split(row, repo.FileSeparator)
+	if len(columns) != 6 {
+		return emptyStatementPayment, fmt.Errorf("invalid statement payment row %q", row)
+	}
+	tID, err := strconv.Atoi(columns[5])
+	if err != nil {
+		return emptyStatementPayment, err
+	}
+
+	return StatementPaymentDAO{
+		CardEntity:    columns[0],
+		CardKind:      columns[1],
+		Statement:     columns[2],
+		Entity:        columns[3],
+		Kind:          columns[4],
+		TransactionID: tID,
+	}, nil
+}
+
+func (repo StatementsPaymentsRepo) GetAllStatementsPayments() (StatementsPaymentsDAO, error) {
+	payments := StatementsPaymentsDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		sp, err := repo.rowToStatementPayment((*rows)[i])
+		if err != nil {
+			return StatementsPaymentsDAO{}, err
+		}
+		payments = append(payments, sp)
+	}
+	return payments, nil
+}
+
+// GetStatementsPayments gets the payments linked to the statements of the card.
+func (repo StatementsPaymentsRepo) GetStatementsPayments(cardEntity, cardKind string) (StatementsPaymentsDAO, error) {
+	all, err := repo.GetAllStatementsPayments()
+	if err != nil {
+		return nil, err
+	}
+	payments := StatementsPaymentsDAO{}
+	for _, sp := range all {
+		if sp.CardEntity == cardEntity && sp.CardKind == cardKind {
+			payments = append(payments, sp)
+		}
+	}
+	return payments, nil
+}
+
+func (repo StatementsPaymentsRepo) lineIndex(entity, kind string, tID int) int {
+	suffix := fmt.Sprintf(";%s;%s;%d", entity, kind, tID)
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.HasSuffix((*repo.FileWrapper.Lines)[i], suffix) {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddStatementPayment links the transaction to the statement.
+func (repo StatementsPaymentsRepo) AddStatementPayment(sp StatementPaymentDAO) error {
+	if repo.lineIndex(sp.Entity, sp.Kind, sp.TransactionID) != -1 {
+		return fmt.Errorf("the transaction %d already pays a statement", sp.TransactionID)
+	}
+	return repo.FileWrapper.AppendLine(repo.ToRow(sp))
+}
+
+// DeleteStatementPayment unlinks the transaction from the statement it pays, if any.
+func (repo StatementsPaymentsRepo) DeleteStatementPayment(entity, kind string, tID int) error {
+	if idx := repo.lineIndex(entity, kind, tID); idx != -1 {
+		return repo.FileWrapper.RemoveLine(idx)
+	}
+	return nil
+}
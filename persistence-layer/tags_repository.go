@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type TagDAO struct {
+	ID  int
+	Tag string
+}
+
+type TagsDAO []TagDAO
+
+type TagsRepo struct {
+	*Repo
+}
+
+const (
+	tagsDBFile   string = "tags.csv"
+	tagsDBHeader string = "id;tag"
+)
+
+func NewTagsRepo(dbDir string) (*TagsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, tagsDBFile), tagsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &TagsRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo TagsRepo) ToRow(t TagDAO) (string, error) {
+	if strings.Index(t.Tag, repo.FileSeparator) != -1 {
+		return "", fmt.Errorf("invalid 'Tag' because includes the char %q => %q", repo.FileSeparator, t.Tag)
+	}
+
+	return fmt.Sprintf("%d;%s", t.ID, t.Tag), nil
+}
+
+func (repo TagsRepo) rowToTag(row string) (TagDAO, error) {
+	emptyTag := TagDAO{}
+	columns := strings.Split(row, repo.FileSeparator)
+	if len(columns) != 2 {
+		return emptyTag, fmt.Errorf("invalid tag row %q", row)
+	}
+	tID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyTag, err
+	}
+
+	return TagDAO{
+		ID:  tID,
+		Tag: columns[1],
+	}, nil
+}
+
+func (repo TagsRepo) GetAllTags() (TagsDAO, error) {
+	tags := TagsDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		tag, err := repo.rowToTag((*rows)[i])
+		if err != nil {
+			return TagsDAO{}, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// AddMissingTags adds the tags not in the repository yet.
+func (repo TagsRepo) AddMissingTags(tags []string) error {
+	existing, err := repo.GetAllTags()
+	if err != nil {
+		return err
+	}
+	found := make(map[string]bool)
+	nextID := 1
+	for _, t := range existing {
+		found[t.Tag] = true
+		if t.ID >= nextID {
+			nextID = t.ID + 1
+		}
+	}
+
+	for _, tag := range tags {
+		if found[tag] {
+			continue
+		}
+		line, err := repo.ToRow(TagDAO{ID: nextID, Tag: tag})
+		if err != nil {
+			return err
+		}
+		if err = repo.FileWrapper.AppendLine(line); err != nil {
+			return err
+		}
+		found[tag] = true
+		nextID++
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TokenDAO struct {
+	ID        int
+	UserID    int
+	Scopes    []string
+	Hash      string
+	CreatedAt time.Time
+	Name      string
+}
+
+type TokensDAO []TokenDAO
+
+// TokensRepo keeps the tokens of all the users.
+type TokensRepo struct {
+	*Repo
+}
+
+const (
+	tokensDBFile   string = "tokens.csv"
+	tokensDBHeader string = "id;user_id;scopes;token_hash;created_at;name"
+	tokensPattern  string = "%d;%d;%s;%s;%s;%s"
+)
+
+func NewTokensRepo(dbDir string) (*TokensRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, tokensDBFile), tokensDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &TokensRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo TokensRepo) ToRow(t TokenDAO) (string, error) {
+	if strings.ContainsAny(t.Name, "\r\n") {
+		return "", fmt.Errorf("invalid name because it has more than one line => %q", t.Name)
+	}
+	return fmt.Sprintf(tokensPattern, t.ID, t.UserID, strings.Join(t.Scopes, "#"), t.Hash,
+		t.CreatedAt.UTC().Format(time.RFC3339), t.Name), nil
+}
+
+func (repo TokensRepo) rowToToken(row string) (TokenDAO, error) {
+	emptyToken := TokenDAO{}
+	columns := strings.SplitN(row, repo.FileSeparator, 6)
+	if len(columns) != 6 {
+		return emptyToken, fmt.Errorf("invalid token row %q", row)
+	}
+	tID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyToken, err
+	}
+	uID, err := strconv.Atoi(columns[1])
+	if err != nil {
+		return emptyToken, err
+	}
+	createdAt, err := time.Parse(time.RFC3339, columns[4])
+	if err != nil {
+		return emptyToken, err
+	}
+
+	return TokenDAO{
+		ID:        tID,
+		UserID:    uID,
+		Scopes:    strings.Split(columns[2], "#"),
+		Hash:      columns[3],
+		CreatedAt: createdAt,
+		Name:      columns[5],
+	}, nil
+}
+
+func (repo TokensRepo) GetAllTokens() (TokensDAO, error) {
+	tokens := TokensDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		t, err := repo.rowToToken((*rows)[i])
+		if err != nil {
+			return TokensDAO{}, err
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, nil
+}
+
+func (repo TokensRepo) AddToken(t TokenDAO) error {
+	line, err := repo.ToRow(t)
+	if err != nil {
+		return err
+	}
+	return repo.FileWrapper.AppendLine(line)
+}
+
+func (repo TokensRepo) DeleteToken(tID int) error {
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.Split((*repo.FileWrapper.Lines)[i], repo.FileSeparator)[0] == strconv.Itoa(tID) {
+			return repo.FileWrapper.RemoveLine(i)
+		}
+	}
+	return fmt.Errorf("token %d not found", tID)
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/h-abranches-dev/daily-expenses-be/files"
+)
+
+type AttachmentDAO struct {
+	ID            int
+	Entity        string
+	Kind          string
+	TransactionID int
+	FileName      string
+	ContentType   string
+	Size          int
+	Hash          string
+	UploadedAt    time.Time
+}
+
+type AttachmentsDAO []AttachmentDAO
+
+// TransactionsAttachmentsRepo keeps the attachments of the transactions.
+type TransactionsAttachmentsRepo struct {
+	*Repo
+	Contents *files.ContentStore
+}
+
+const (
+	transactionsAttachmentsDBFile   string = "transactions_attachments.csv"
+	transactionsAttachmentsDBHeader string = "id;entity;kind;transaction_id;file_name;content_type;size;sha256;uploaded_at"
+	attachmentsContentsDir          string = "attachments"
+)
+
+func NewTransactionsAttachmentsRepo(dbDir string) (*TransactionsAttachmentsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, transactionsAttachmentsDBFile), transactionsAttachmentsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := files.NewContentStore(filepath.Join(dbDir, attachmentsContentsDir))
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionsAttachmentsRepo{
+		Repo:     r,
+		Contents: cs,
+	}, nil
+}
+
+func (repo TransactionsAttachmentsRepo) ToRow(a AttachmentDAO) (string, error) {
+	if strings.Index(a.FileName, repo.FileSeparator) != -1 {
+		return "", fmt.Errorf("invalid 'FileName' because includes the char %q => %q", repo.FileSeparator, a.FileName)
+	}
+
+	return fmt.Sprintf("%d;%s;%s;%d;%s;%s;%d;%s;%s", a.ID, a.Entity, a.Kind, a.TransactionID, a.FileName,
+		a.ContentType, a.Size, a.Hash, a.UploadedAt.UTC().Format(time.RFC3339)), nil
+}
+
+func (repo TransactionsAttachmentsRepo) rowToAttachment(row string) (AttachmentDAO, error) {
+	emptyAttachment := AttachmentDAO{}
+	columns := strings.Split(row, repo.FileSeparator)
+	if len(columns) != 9 {
+		return emptyAttachment, fmt.Errorf("invalid attachment row %q", row)
+	}
+	aID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyAttachment, err
+	}
+	tID, err := strconv.Atoi(columns[3])
+	if err != nil {
+		return emptyAttachment, err
+	}
+	size, err := strconv.Atoi(columns[6])
+	if err != nil {
+		return emptyAttachment, err
+	}
+	uploadedAt, err := time.Parse(time.RFC3339, columns[8])
+	if err != nil {
+		return emptyAttachment, err
+	}
+
+	return AttachmentDAO{
+		ID:            aID,
+		Entity:        columns[1],
+		Kind:          columns[2],
+		TransactionID: tID,
+		FileName:      columns[4],
+		ContentType:   columns[5],
+		Size:          size,
+		Hash:          columns[7],
+		UploadedAt:    uploadedAt,
+	}, nil
+}
+
+func (repo TransactionsAttachmentsRepo) GetAllAttachments() (AttachmentsDAO, error) {
+	attachments := AttachmentsDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		a, err := repo.rowToAttachment((*rows)[i])
+		if err != nil {
+			return AttachmentsDAO{}, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, nil
+}
+
+// GetAttachments gets the attachments of the transaction.
+func (repo TransactionsAttachmentsRepo) GetAttachments(entity, kind string, tID int) (AttachmentsDAO, error) {
+	all, err := repo.GetAllAttachments()
+	if err != nil {
+		return AttachmentsDAO{}, err
+	}
+	attachments := AttachmentsDAO{}
+	for _, a := range all {
+		if a.Entity == entity && a.Kind == kind && a.TransactionID == tID {
+			attachments = append(attachments, a)
+		}
+	}
+	return attachments, nil
+}
+
+// AddAttachment stores the content and adds the attachment.
+func (repo TransactionsAttachmentsRepo) AddAttachment(a AttachmentDAO, content []byte) (AttachmentDAO, error) {
+	all, err := repo.GetAllAttachments()
+	if err != nil {
+		return a, err
+	}
+	a.ID = 1
+	for _, other := range all {
+		if other.ID >= a.ID {
+			a.ID = other.ID + 1
+		}
+	}
+	if a.Hash, err = repo.Contents.Put(content); err != nil {
+		return a, err
+	}
+	a.Size = len(content)
+
+	line, err := repo.ToRow(a)
+	if err != nil {
+		return a, err
+	}
+	if err = repo.FileWrapper.AppendLine(line); err != nil {
+		return a, err
+	}
+	return a, nil
+}
+
+// DeleteAttachment deletes the attachment and its content when unused.
+func (repo TransactionsAttachmentsRepo) DeleteAttachment(aID int) error {
+	all, err := repo.GetAllAttachments()
+	if err != nil {
+		return err
+	}
+	hash := ""
+	for i, a := range all {
+		if a.ID == aID {
+			hash = a.Hash
+			if err = repo.FileWrapper.RemoveLine(i + 1); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	if hash == "" {
+		return fmt.Errorf("attachment %d not found", aID)
+	}
+
+	for _, a := range all {
+		if a.ID != aID && a.Hash == hash {
+			return nil
+		}
+	}
+	return repo.Contents.Remove(hash)
+}
+
+// DeleteTransactionAttachments deletes all the attachments of the transaction.
+func (repo TransactionsAttachmentsRepo) DeleteTransactionAttachments(entity, kind string, tID int) error {
+	attachments, err := repo.GetAttachments(entity, kind, tID)
+	if err != nil {
+		return err
+	}
+	for _, a := range attachments {
+		if err = repo.DeleteAttachment(a.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -20,11 +21,12 @@ type TransactionsEntitiesRepo struct {
 }
 
 const (
-	transactionsEntitiesDBFile string = "db/transactions_entities.csv"
+	transactionsEntitiesDBFile   string = "transactions_entities.csv"
+	transactionsEntitiesDBHeader string = "id;entity;kind;balance"
 )
 
-func NewTransactionsEntitiesRepo() (*TransactionsEntitiesRepo, error) {
-	r, err := NewRepo(transactionsEntitiesDBFile)
+func NewTransactionsEntitiesRepo(dbDir string) (*TransactionsEntitiesRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, transactionsEntitiesDBFile), transactionsEntitiesDBHeader)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type FieldValueDAO struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+type FieldValuesDAO []FieldValueDAO
+
+// TransactionsFieldsRepo keeps the values of the custom fields of the transactions.
+type TransactionsFieldsRepo struct {
+	*Repo
+}
+
+const (
+	transactionsFieldsDBFile   string = "transactions_fields.csv"
+	transactionsFieldsDBHeader string = "entity;kind;transaction_id;field;type;value"
+)
+
+func NewTransactionsFieldsRepo(dbDir string) (*TransactionsFieldsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, transactionsFieldsDBFile), transactionsFieldsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionsFieldsRepo{
+		Repo: r,
+	}, nil
+}
+
+// GetFields gets the custom fields values of the transactions by ID.
+func (repo TransactionsFieldsRepo) GetFields(entity, kind string) (map[int]FieldValuesDAO, error) {
+	fields := make(map[int]FieldValuesDAO)
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		columns := strings.SplitN((*rows)[i], repo.FileSeparator, 6)
+		if len(columns) != 6 {
+			return nil, fmt.Errorf("invalid transaction field row %q", (*rows)[i])
+		}
+		if columns[0] != entity || columns[1] != kind {
+			continue
+		}
+		tID, err := strconv.Atoi(columns[2])
+		if err != nil {
+			return nil, err
+		}
+		fields[tID] = append(fields[tID], FieldValueDAO{
+			Name:  columns[3],
+			Type:  columns[4],
+			Value: columns[5],
+		})
+	}
+	return fields, nil
+}
+
+// SetFields replaces the custom fields values of the transaction.
+func (repo TransactionsFieldsRepo) SetFields(entity, kind string, tID int, fvs FieldValuesDAO) error {
+	if err := repo.removeRows(fmt.Sprintf("%s;%s;%d;", entity, kind, tID), ""); err != nil {
+		return err
+	}
+
+	for _, fv := range fvs {
+		if strings.Contains(fv.Name, repo.FileSeparator) || strings.ContainsAny(fv.Value, "\r\n") {
+			return fmt.Errorf("invalid value of the field %q => %q", fv.Name, fv.Value)
+		}
+		line := fmt.Sprintf("%s;%s;%d;%s;%s;%s", entity, kind, tID, fv.Name, fv.Type, fv.Value)
+		if err := repo.FileWrapper.AppendLine(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteFieldValues removes the values of the custom field.
+func (repo TransactionsFieldsRepo) DeleteFieldValues(entity, kind, field string) error {
+	return repo.removeRows(fmt.Sprintf("%s;%s;", entity, kind), field)
+}
+
+func (repo TransactionsFieldsRepo) removeRows(prefix, field string) error {
+	for i := len(*repo.FileWrapper.Lines) - 2; i >= 1; i-- {
+		row := (*repo.FileWrapper.Lines)[i]
+		if !strings.HasPrefix(row, prefix) {
+			continue
+		}
+		if columns := strings.SplitN(row, repo.FileSeparator, 6); field != "" && (len(columns) != 6 || columns[3] != field) {
+			continue
+		}
+		if err := repo.FileWrapper.RemoveLine(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
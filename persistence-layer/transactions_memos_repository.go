@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TransactionsMemosRepo keeps the memos of the transactions.
+type TransactionsMemosRepo struct {
+	*Repo
+}
+
+const (
+	transactionsMemosDBFile   string = "transactions_memos.csv"
+	transactionsMemosDBHeader string = "entity;kind;transaction_id;memo"
+)
+
+func NewTransactionsMemosRepo(dbDir string) (*TransactionsMemosRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, transactionsMemosDBFile), transactionsMemosDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionsMemosRepo{
+		Repo: r,
+	}, nil
+}
+
+// GetMemos gets the memos of the transactions by ID.
+func (repo TransactionsMemosRepo) GetMemos(entity, kind string) (map[int]string, error) {
+	memos := make(map[int]string)
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		columns := strings.SplitN((*rows)[i], repo.FileSeparator, 4)
+		if len(columns) != 4 {
+			return nil, fmt.Errorf("invalid transaction memo row %q", (*rows)[i])
+		}
+		if columns[0] != entity || columns[1] != kind {
+			continue
+		}
+		tID, err := strconv.Atoi(columns[2])
+		if err != nil {
+			return nil, err
+		}
+		memos[tID] = columns[3]
+	}
+	return memos, nil
+}
+
+// SetMemo sets the memo of the transaction, removing it when it's empty.
+func (repo TransactionsMemosRepo) SetMemo(entity, kind string, tID int, memo string) error {
+	if strings.ContainsAny(memo, "\r\n") {
+		return fmt.Errorf("invalid memo because it has more than one line => %q", memo)
+	}
+	prefix := fmt.Sprintf("%s;%s;%d;", entity, kind, tID)
+	line := prefix + memo
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if !strings.HasPrefix((*repo.FileWrapper.Lines)[i], prefix) {
+			continue
+		}
+		if memo == "" {
+			return repo.FileWrapper.RemoveLine(i)
+		}
+		if (*repo.FileWrapper.Lines)[i] == line {
+			return nil
+		}
+		return repo.FileWrapper.ReplaceLine(i, line)
+	}
+	if memo == "" {
+		return nil
+	}
+	return repo.FileWrapper.AppendLine(line)
+}
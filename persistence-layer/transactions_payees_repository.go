@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TransactionsPayeesRepo links the transactions to their payees.
+type TransactionsPayeesRepo struct {
+	*Repo
+}
+
+const (
+	transactionsPayeesDBFile   string = "transactions_payees.csv"
+	transactionsPayeesDBHeader string = "entity;kind;transaction_id;payee_id"
+)
+
+func NewTransactionsPayeesRepo(dbDir string) (*TransactionsPayeesRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, transactionsPayeesDBFile), transactionsPayeesDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionsPayeesRepo{
+		Repo: r,
+	}, nil
+}
+
+// GetPayees gets the payees IDs of the transactions by ID.
+func (repo TransactionsPayeesRepo) GetPayees(entity, kind string) (map[int]int, error) {
+	payees := make(map[int]int)
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		columns := strings.Split((*rows)[i], repo.FileSeparator)
+		if len(columns) != 4 {
+			return nil, fmt.Errorf("invalid transaction payee row %q", (*rows)[i])
+		}
+		if columns[0] != entity || columns[1] != kind {
+			continue
+		}
+		tID, err := strconv.Atoi(columns[2])
+		if err != nil {
+			return nil, err
+		}
+		pID, err := strconv.Atoi(columns[3])
+		if err != nil {
+			return nil, err
+		}
+		payees[tID] = pID
+	}
+	return payees, nil
+}
+
+// SetPayee links the transaction to the payee, or unlinks it when the payee ID is 0.
+func (repo TransactionsPayeesRepo) SetPayee(entity, kind string, tID, pID int) error {
+	prefix := fmt.Sprintf("%s;%s;%d;", entity, kind, tID)
+	line := fmt.Sprintf("%s%d", prefix, pID)
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if !strings.HasPrefix((*repo.FileWrapper.Lines)[i], prefix) {
+			continue
+		}
+		if pID == 0 {
+			return repo.FileWrapper.RemoveLine(i)
+		}
+		if (*repo.FileWrapper.Lines)[i] == line {
+			return nil
+		}
+		return repo.FileWrapper.ReplaceLine(i, line)
+	}
+	if pID == 0 {
+		return nil
+	}
+	return repo.FileWrapper.AppendLine(line)
+}
+
+// UnlinkPayee unlinks all the transactions from the payee.
+func (repo TransactionsPayeesRepo) UnlinkPayee(pID int) error {
+	suffix := fmt.Sprintf(";%d", pID)
+	for i := len(*repo.FileWrapper.Lines) - 2; i >= 1; i-- {
+		if strings.HasSuffix((*repo.FileWrapper.Lines)[i], suffix) {
+			if err := repo.FileWrapper.RemoveLine(i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
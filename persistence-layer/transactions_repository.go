@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
@@ -15,6 +16,12 @@ type TransactionDAO struct {
 	Categories      CategoriesDAO
 	Kind            string
 	Amount          float32
+	Splits          SplitsDAO
+	PayeeID         int
+	Tags            []string
+	Memo            string
+	Fields          FieldValuesDAO
+	Status          string
 }
 
 type TransactionsDAO []TransactionDAO
@@ -23,23 +30,33 @@ type TransactionsRepo struct {
 	*Repo
 	Kind   string
 	Entity string
+	RefToTransactions *models.Transactions
 }
 
 const (
 	bankAccountDebitPattern       = "%d;%s;%s;%s;%s"
 	bankAccountDebitCreditPattern = "%d;%s;%s;%s;%s;%s"
+	bankAccountDebitHeader        = "id;transaction_date;transaction;categories;amount"
+	bankAccountDebitCreditHeader  = "id;transaction_date;transaction;categories;type;amount"
 )
 
 var (
 	dBFiles = map[string]string{
-		models.EntityKindKey(models.TestEntity, models.DebitBankAccountKind):        "db/test_transactions.csv",
-		models.EntityKindKey(models.Test2Entity, models.DebitCreditBankAccountKind): "db/test2_transactions.csv",
+		models.EntityKindKey(models.TestEntity, models.DebitBankAccountKind):        "test_transactions.csv",
+		models.EntityKindKey(models.Test2Entity, models.DebitCreditBankAccountKind): "test2_transactions.csv",
 	}
 )
 
-func NewTransactionsRepo(kind, entity string) (*TransactionsRepo, error) {
+func NewTransactionsRepo(dbDir, kind, entity string) (*TransactionsRepo, error) {
 	dbFile := dBFiles[models.EntityKindKey(models.TransactionEntity(entity), models.TransactionKind(kind))]
-	r, err := NewRepo(dbFile)
+	if dbFile == "" {
+		return nil, fmt.Errorf("for the kind %q and entity %q any database file was found", kind, entity)
+	}
+	dbHeader := bankAccountDebitHeader
+	if models.TransactionKind(kind) == models.DebitCreditBankAccountKind {
+		dbHeader = bankAccountDebitCreditHeader
+	}
+	r, err := NewRepo(filepath.Join(dbDir, dbFile), dbHeader)
 	if err != nil {
 		return nil, err
 	}
@@ -59,9 +76,14 @@ func (repo TransactionsRepo) ToRow(t TransactionDAO) (string, error) {
 	var rowPattern, row string
 	switch models.TransactionKind(repo.Kind) {
 	case models.DebitBankAccountKind:
+		// there isn't a type column so the debits are stored with negative amounts
+		amount := t.Amount
+		if t.Kind == models.DebitKindTransaction {
+			amount = -amount
+		}
 		rowPattern = bankAccountDebitPattern
 		row = fmt.Sprintf(rowPattern, t.ID, t.TransactionDate.Format(utils.DateFormat),
-			t.Transaction, strings.Join(t.categoriesLabels(), "#"), fmt.Sprintf("%.2f", t.Amount))
+			t.Transaction, strings.Join(t.categoriesLabels(), "#"), fmt.Sprintf("%.2f", amount))
 	case models.DebitCreditBankAccountKind:
 		rowPattern = bankAccountDebitCreditPattern
 		row = fmt.Sprintf(rowPattern, t.ID, t.TransactionDate.Format(utils.DateFormat),
@@ -103,6 +125,13 @@ func (repo TransactionsRepo) rowToTransaction(categoriesRepo CategoriesRepo, row
 	if err != nil {
 		return emptyTransaction, err
 	}
+	if models.TransactionKind(repo.Kind) == models.DebitBankAccountKind {
+		tKind = models.CreditKindTransaction
+		if tAmount < 0 {
+			tKind = models.DebitKindTransaction
+			tAmount = -tAmount
+		}
+	}
 
 	csDAO := CategoriesDAO{}
 	if columns[3] != "" {
@@ -124,7 +153,56 @@ func (repo TransactionsRepo) rowToTransaction(categoriesRepo CategoriesRepo, row
 func (repo TransactionsRepo) GetAllTransactions() (TransactionsDAO, error) {
 	transactions := TransactionsDAO{}
 	rows := repo.FileWrapper.Lines
-	csRepo, err := GetCategoriesRepo()
+	csRepo, err := repo.Namespace.GetCategoriesRepo()
+	if err != nil {
+		return transactions, err
+	}
+
+	splitsRepo, err := repo.Namespace.GetTransSplitsRepo()
+	if err != nil {
+		return transactions, err
+	}
+	splits, err := splitsRepo.GetSplits(repo.Entity, repo.Kind)
+	if err != nil {
+		return transactions, err
+	}
+	payeesRepo, err := repo.Namespace.GetTransPayeesRepo()
+	if err != nil {
+		return transactions, err
+	}
+	payees, err := payeesRepo.GetPayees(repo.Entity, repo.Kind)
+	if err != nil {
+		return transactions, err
+	}
+	tagsRepo, err := repo.Namespace.GetTransTagsRepo()
+	if err != nil {
+		return transactions, err
+	}
+	tags, err := tagsRepo.GetTags(repo.Entity, repo.Kind)
+	if err != nil {
+		return transactions, err
+	}
+	memosRepo, err := repo.Namespace.GetTransMemosRepo()
+	if err != nil {
+		return transactions, err
+	}
+	memos, err := memosRepo.GetMemos(repo.Entity, repo.Kind)
+	if err != nil {
+		return transactions, err
+	}
+	fieldsRepo, err := repo.Namespace.GetTransFieldsRepo()
+	if err != nil {
+		return transactions, err
+	}
+	fields, err := fieldsRepo.GetFields(repo.Entity, repo.Kind)
+	if err != nil {
+		return transactions, err
+	}
+	statusesRepo, err := repo.Namespace.GetTransStatusesRepo()
+	if err != nil {
+		return transactions, err
+	}
+	statuses, err := statusesRepo.GetStatuses(repo.Entity, repo.Kind)
 	if err != nil {
 		return transactions, err
 	}
@@ -135,6 +213,15 @@ func (repo TransactionsRepo) GetAllTransactions() (TransactionsDAO, error) {
 		if err != nil {
 			return TransactionsDAO{}, err
 		}
+		transaction.Splits = splits[transaction.ID]
+		transaction.PayeeID = payees[transaction.ID]
+		transaction.Tags = tags[transaction.ID]
+		transaction.Memo = memos[transaction.ID]
+		transaction.Fields = fields[transaction.ID]
+		transaction.Status = statuses[transaction.ID]
+		if transaction.Status == "" {
+			transaction.Status = models.PendingTransactionStatus
+		}
 		transactions = append(transactions, transaction)
 	}
 	return transactions, nil
@@ -150,7 +237,7 @@ func (repo TransactionsRepo) AddTransaction(t TransactionDAO) error {
 		return err
 	}
 
-	return nil
+	return repo.setLinkedRows(t)
 }
 
 func (repo TransactionsRepo) UpdateTransaction(t TransactionDAO) error {
@@ -170,7 +257,103 @@ func (repo TransactionsRepo) UpdateTransaction(t TransactionDAO) error {
 		return err
 	}
 
-	return nil
+	return repo.setLinkedRows(t)
+}
+
+// DeleteTransaction deletes the transaction and its linked rows.
+func (repo TransactionsRepo) DeleteTransaction(tID int) error {
+	idxLineToRemove := -1
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.Split((*repo.FileWrapper.Lines)[i], repo.FileSeparator)[0] == strconv.Itoa(tID) {
+			idxLineToRemove = i
+		}
+	}
+	if idxLineToRemove == -1 {
+		return fmt.Errorf("transaction %d not found", tID)
+	}
+
+	if err := repo.FileWrapper.RemoveLine(idxLineToRemove); err != nil {
+		return err
+	}
+	if err := repo.setLinkedRows(TransactionDAO{ID: tID}); err != nil {
+		return err
+	}
+	statementsPaymentsRepo, err := repo.Namespace.GetStatementsPaymentsRepo()
+	if err != nil {
+		return err
+	}
+	if err = statementsPaymentsRepo.DeleteStatementPayment(repo.Entity, repo.Kind, tID); err != nil {
+		return err
+	}
+	loansInstallmentsRepo, err := repo.Namespace.GetLoansInstallmentsRepo()
+	if err != nil {
+		return err
+	}
+	if err = loansInstallmentsRepo.DeleteLoanInstallment(repo.Entity, repo.Kind, tID); err != nil {
+		return err
+	}
+	sharesRepo, err := repo.Namespace.GetTransSharesRepo()
+	if err != nil {
+		return err
+	}
+	if err = sharesRepo.DeleteSharedTransaction(repo.Entity, repo.Kind, tID); err != nil {
+		return err
+	}
+	attachmentsRepo, err := repo.Namespace.GetTransAttachmentsRepo()
+	if err != nil {
+		return err
+	}
+	return attachmentsRepo.DeleteTransactionAttachments(repo.Entity, repo.Kind, tID)
+}
+
+func (repo TransactionsRepo) setLinkedRows(t TransactionDAO) error {
+	splitsRepo, err := repo.Namespace.GetTransSplitsRepo()
+	if err != nil {
+		return err
+	}
+	if err = splitsRepo.SetSplits(repo.Entity, repo.Kind, t.ID, t.Splits); err != nil {
+		return err
+	}
+	payeesRepo, err := repo.Namespace.GetTransPayeesRepo()
+	if err != nil {
+		return err
+	}
+	if err = payeesRepo.SetPayee(repo.Entity, repo.Kind, t.ID, t.PayeeID); err != nil {
+		return err
+	}
+	tagsRepo, err := repo.Namespace.GetTagsRepo()
+	if err != nil {
+		return err
+	}
+	if err = tagsRepo.AddMissingTags(t.Tags); err != nil {
+		return err
+	}
+	transTagsRepo, err := repo.Namespace.GetTransTagsRepo()
+	if err != nil {
+		return err
+	}
+	if err = transTagsRepo.SetTags(repo.Entity, repo.Kind, t.ID, t.Tags); err != nil {
+		return err
+	}
+	memosRepo, err := repo.Namespace.GetTransMemosRepo()
+	if err != nil {
+		return err
+	}
+	if err = memosRepo.SetMemo(repo.Entity, repo.Kind, t.ID, t.Memo); err != nil {
+		return err
+	}
+	fieldsRepo, err := repo.Namespace.GetTransFieldsRepo()
+	if err != nil {
+		return err
+	}
+	if err = fieldsRepo.SetFields(repo.Entity, repo.Kind, t.ID, t.Fields); err != nil {
+		return err
+	}
+	statusesRepo, err := repo.Namespace.GetTransStatusesRepo()
+	if err != nil {
+		return err
+	}
+	return statusesRepo.SetStatus(repo.Entity, repo.Kind, t.ID, t.Status)
 }
 
 func (t TransactionDAO) categoriesLabels() []string {
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type ShareDAO struct {
+	ParticipantID int
+	Value         float32
+}
+
+type SharedTransactionDAO struct {
+	Entity        string
+	Kind          string
+	TransactionID int
+	PayerID       int
+	Split         string
+	Shares        []ShareDAO
+}
+
+type SharedTransactionsDAO []SharedTransactionDAO
+
+// TransactionsSharesRepo keeps the shared transactions.
+type TransactionsSharesRepo struct {
+	*Repo
+}
+
+const (
+	transactionsSharesDBFile         string = "transactions_shares.csv"
+	transactionsSharesDBHeader       string = "entity;kind;transaction_id;payer_id;split;shares"
+	transactionsSharesPattern        string = "%s;%s;%d;%d;%s;%s"
+	transactionsSharesListsSeparator string = "#"
+	transactionsShareValueSeparator  string = ":"
+)
+
+func NewTransactionsSharesRepo(dbDir string) (*TransactionsSharesRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, transactionsSharesDBFile), transactionsSharesDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionsSharesRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo TransactionsSharesRepo) ToRow(st SharedTransactionDAO) string {
+	shares := make([]string, 0, len(st.Shares))
+	for _, s := range st.Shares {
+		shares = append(shares, fmt.Sprintf("%d%s%.2f", s.ParticipantID, transactionsShareValueSeparator, s.Value))
+	}
+	return fmt.Sprintf(transactionsSharesPattern, st.Entity, st.Kind, st.TransactionID, st.PayerID, st.Split,
+		strings.Join(shares, transactionsSharesListsSeparator))
+}
+
+func (repo TransactionsSharesRepo) rowToSharedTransaction(row string) (SharedTransactionDAO, error) {
+	emptySharedTransaction := SharedTransactionDAO{}
+	columns := strings.Split(row, repo.FileSeparator)
+	if len(columns) != 6 {
+		return emptySharedTransaction, fmt.Errorf("invalid shared transaction row %q", row)
+	}
+	tID, err := strconv.Atoi(columns[2])
+	if err != nil {
+		return emptySharedTransaction, err
+	}
+	payerID, err := strconv.Atoi(columns[3])
+	if err != nil {
+		return emptySharedTransaction, err
+	}
+	shares := []ShareDAO{}
+	if columns[5] != "" {
+		for _, s := range strings.Split(columns[5], transactionsSharesListsSeparator) {
+			parts := strings.Split(s, transactionsShareValueSeparator)
+			if len(parts) != 2 {
+				return emptySharedTransaction, fmt.Errorf("invalid share %q", s)
+			}
+			pID, err := strconv.Atoi(parts[0])
+			if err != nil {
+				return emptySharedTransaction, err
+			}
+			value, err := strconv.ParseFloat(parts[1], 32)
+			if err != nil {
+				return emptySharedTransaction, err
+			}
+			shares = append(shares, ShareDAO{ParticipantID: pID, Value: float32(value)})
+		}
+	}
+
+	return SharedTransactionDAO{
+		Entity:        columns[0],
+		Kind:          columns[1],
+		TransactionID: tID,
+		PayerID:       payerID,
+		Split:         columns[4],
+		Shares:        shares,
+	}, nil
+}
+
+func (repo TransactionsSharesRepo) GetAllSharedTransactions() (SharedTransactionsDAO, error) {
+	sts := SharedTransactionsDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		st, err := repo.rowToSharedTransaction((*rows)[i])
+		if err != nil {
+			return SharedTransactionsDAO{}, err
+		}
+		sts = append(sts, st)
+	}
+	return sts, nil
+}
+
+func (repo TransactionsSharesRepo) lineIndex(entity, kind string, tID int) int {
+	prefix := fmt.Sprintf("%s;%s;%d;", entity, kind, tID)
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.HasPrefix((*repo.FileWrapper.Lines)[i], prefix) {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetSharedTransaction gets how the transaction is shared, telling if it is.
+func (repo TransactionsSharesRepo) GetSharedTransaction(entity, kind string, tID int) (SharedTransactionDAO, bool, error) {
+	idx := repo.lineIndex(entity, kind, tID)
+	if idx == -1 {
+		return SharedTransactionDAO{}, false, nil
+	}
+	st, err := repo.rowToSharedTransaction((*repo.FileWrapper.Lines)[idx])
+	if err != nil {
+		return SharedTransactionDAO{}, false, err
+	}
+	return st, true, nil
+}
+
+// SetSharedTransaction shares the transaction, replacing how it was shared before.
+func (repo TransactionsSharesRepo) SetSharedTransaction(st SharedTransactionDAO) error {
+	if idx := repo.lineIndex(st.Entity, st.Kind, st.TransactionID); idx != -1 {
+		return repo.FileWrapper.ReplaceLine(idx, repo.ToRow(st))
+	}
+	return repo.FileWrapper.AppendLine(repo.ToRow(st))
+}
+
+// DeleteSharedTransaction stops sharing the transaction, if it's shared.
+func (repo TransactionsSharesRepo) DeleteSharedTransaction(entity, kind string, tID int) error {
+	if idx := repo.lineIndex(entity, kind, tID); idx != -1 {
+		return repo.FileWrapper.RemoveLine(idx)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type SplitDAO struct {
+	Category CategoryDAO
+	Amount   float32
+	Note     string
+}
+
+type SplitsDAO []SplitDAO
+
+// TransactionsSplitsRepo keeps the splits of the transactions.
+type TransactionsSplitsRepo struct {
+	*Repo
+}
+
+const (
+	transactionsSplitsDBFile   string = "transactions_splits.csv"
+	transactionsSplitsDBHeader string = "entity;kind;transaction_id;category;amount;note"
+	transactionsSplitsPattern  string = "%s;%s;%d;%s;%.2f;%s"
+)
+
+func NewTransactionsSplitsRepo(dbDir string) (*TransactionsSplitsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, transactionsSplitsDBFile), transactionsSplitsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionsSplitsRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo TransactionsSplitsRepo) ToRow(entity, kind string, tID int, s SplitDAO) (string, error) {
+	if strings.Index(s.Note, repo.FileSeparator) != -1 {
+		return "", fmt.Errorf("invalid 'Note' because includes the char %q => %q", repo.FileSeparator, s.Note)
+	}
+	if strings.ContainsAny(s.Note, "\r\n") {
+		return "", fmt.Errorf("invalid note because it has more than one line => %q", s.Note)
+	}
+
+	return fmt.Sprintf(transactionsSplitsPattern, entity, kind, tID, s.Category.Label, s.Amount, s.Note), nil
+}
+
+func (repo TransactionsSplitsRepo) rowToSplit(categoriesRepo CategoriesRepo, row string) (string, string, int, SplitDAO, error) {
+	emptySplit := SplitDAO{}
+	columns := strings.Split(row, repo.FileSeparator)
+	if len(columns) != 6 {
+		return "", "", -1, emptySplit, fmt.Errorf("invalid split row %q", row)
+	}
+	tID, err := strconv.Atoi(columns[2])
+	if err != nil {
+		return "", "", -1, emptySplit, err
+	}
+	cDAO, err := categoriesRepo.CategoryDAO(columns[3])
+	if err != nil {
+		return "", "", -1, emptySplit, err
+	}
+	amount, err := strconv.ParseFloat(strings.Trim(columns[4], " "), 32)
+	if err != nil {
+		return "", "", -1, emptySplit, err
+	}
+	return columns[0], columns[1], tID, SplitDAO{
+		Category: cDAO,
+		Amount:   float32(amount),
+		Note:     columns[5],
+	}, nil
+}
+
+// GetSplits gets the splits of the transactions by ID.
+func (repo TransactionsSplitsRepo) GetSplits(entity, kind string) (map[int]SplitsDAO, error) {
+	splits := make(map[int]SplitsDAO)
+	rows := repo.FileWrapper.Lines
+	csRepo, err := repo.Namespace.GetCategoriesRepo()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < len(*rows)-1; i++ {
+		sEntity, sKind, tID, s, err := repo.rowToSplit(*csRepo, (*rows)[i])
+		if err != nil {
+			return nil, err
+		}
+		if sEntity == entity && sKind == kind {
+			splits[tID] = append(splits[tID], s)
+		}
+	}
+	return splits, nil
+}
+
+// SetSplits replaces the splits of the transaction.
+func (repo TransactionsSplitsRepo) SetSplits(entity, kind string, tID int, ss SplitsDAO) error {
+	prefix := fmt.Sprintf("%s;%s;%d;", entity, kind, tID)
+	for i := len(*repo.FileWrapper.Lines) - 2; i >= 1; i-- {
+		if strings.HasPrefix((*repo.FileWrapper.Lines)[i], prefix) {
+			if err := repo.FileWrapper.RemoveLine(i); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, s := range ss {
+		line, err := repo.ToRow(entity, kind, tID, s)
+		if err != nil {
+			return err
+		}
+		if err = repo.FileWrapper.AppendLine(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
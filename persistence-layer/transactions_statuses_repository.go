@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+)
+
+// TransactionsStatusesRepo keeps the statuses of the transactions.
+type TransactionsStatusesRepo struct {
+	*Repo
+}
+
+const (
+	transactionsStatusesDBFile   string = "transactions_statuses.csv"
+	transactionsStatusesDBHeader string = "entity;kind;transaction_id;status"
+)
+
+func NewTransactionsStatusesRepo(dbDir string) (*TransactionsStatusesRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, transactionsStatusesDBFile), transactionsStatusesDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionsStatusesRepo{
+		Repo: r,
+	}, nil
+}
+
+// GetStatuses gets the statuses of the transactions by ID.
+func (repo TransactionsStatusesRepo) GetStatuses(entity, kind string) (map[int]string, error) {
+	statuses := make(map[int]string)
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		columns := strings.Split((*rows)[i], repo.FileSeparator)
+		if len(columns) != 4 {
+			return nil, fmt.Errorf("invalid transaction status row %q", (*rows)[i])
+		}
+		if columns[0] != entity || columns[1] != kind {
+			continue
+		}
+		tID, err := strconv.Atoi(columns[2])
+		if err != nil {
+			return nil, err
+		}
+		statuses[tID] = columns[3]
+	}
+	return statuses, nil
+}
+
+// SetStatus sets the status of the transaction, removing it when it's pending.
+func (repo TransactionsStatusesRepo) SetStatus(entity, kind string, tID int, status string) error {
+	if status == models.PendingTransactionStatus {
+		status = ""
+	}
+	prefix := fmt.Sprintf("%s;%s;%d;", entity, kind, tID)
+	line := prefix + status
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if !strings.HasPrefix((*repo.FileWrapper.Lines)[i], prefix) {
+			continue
+		}
+		if status == "" {
+			return repo.FileWrapper.RemoveLine(i)
+		}
+		if (*repo.FileWrapper.Lines)[i] == line {
+			return nil
+		}
+		return repo.FileWrapper.ReplaceLine(i, line)
+	}
+	if status == "" {
+		return nil
+	}
+	return repo.FileWrapper.AppendLine(line)
+}
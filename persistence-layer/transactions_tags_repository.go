@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TransactionsTagsRepo keeps the tags of the transactions.
+type TransactionsTagsRepo struct {
+	*Repo
+}
+
+const (
+	transactionsTagsDBFile   string = "transactions_tags.csv"
+	transactionsTagsDBHeader string = "entity;kind;transaction_id;tag"
+)
+
+func NewTransactionsTagsRepo(dbDir string) (*TransactionsTagsRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, transactionsTagsDBFile), transactionsTagsDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionsTagsRepo{
+		Repo: r,
+	}, nil
+}
+
+// GetTags gets the tags of the transactions by ID.
+func (repo TransactionsTagsRepo) GetTags(entity, kind string) (map[int][]string, error) {
+	tags := make(map[int][]string)
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		columns := strings.Split((*rows)[i], repo.FileSeparator)
+		if len(columns) != 4 {
+			return nil, fmt.Errorf("invalid transaction tag row %q", (*rows)[i])
+		}
+		if columns[0] != entity || columns[1] != kind {
+			continue
+		}
+		tID, err := strconv.Atoi(columns[2])
+		if err != nil {
+			return nil, err
+		}
+		tags[tID] = append(tags[tID], columns[3])
+	}
+	return tags, nil
+}
+
+// SetTags replaces the tags of the transaction, removing them when there are none.
+func (repo TransactionsTagsRepo) SetTags(entity, kind string, tID int, tags []string) error {
+	prefix := fmt.Sprintf("%s;%s;%d;", entity, kind, tID)
+	for i := len(*repo.FileWrapper.Lines) - 2; i >= 1; i-- {
+		if strings.HasPrefix((*repo.FileWrapper.Lines)[i], prefix) {
+			if err := repo.FileWrapper.RemoveLine(i); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, tag := range tags {
+		if strings.Index(tag, repo.FileSeparator) != -1 {
+			return fmt.Errorf("invalid tag because includes the char %q => %q", repo.FileSeparator, tag)
+		}
+		if err := repo.FileWrapper.AppendLine(prefix + tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
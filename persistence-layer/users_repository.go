@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type UserDAO struct {
+	ID          int
+	Name        string
+	ServerAdmin bool
+}
+
+type UsersDAO []UserDAO
+
+// UsersRepo keeps the users of the server.
+type UsersRepo struct {
+	*Repo
+}
+
+const (
+	usersDBFile   string = "users.csv"
+	usersDBHeader string = "id;name;server_admin"
+	usersPattern  string = "%d;%s;%t"
+)
+
+func NewUsersRepo(dbDir string) (*UsersRepo, error) {
+	r, err := NewRepo(filepath.Join(dbDir, usersDBFile), usersDBHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &UsersRepo{
+		Repo: r,
+	}, nil
+}
+
+func (repo UsersRepo) ToRow(u UserDAO) (string, error) {
+	if strings.Index(u.Name, repo.FileSeparator) != -1 {
+		return "", fmt.Errorf("invalid 'Name' because includes the char %q => %q", repo.FileSeparator, u.Name)
+	}
+	return fmt.Sprintf(usersPattern, u.ID, u.Name, u.ServerAdmin), nil
+}
+
+func (repo UsersRepo) rowToUser(row string) (UserDAO, error) {
+	emptyUser := UserDAO{}
+	columns := strings.Split(row, repo.FileSeparator)
+	if len(columns) != 2 && len(columns) != 3 {
+		return emptyUser, fmt.Errorf("invalid user row %q", row)
+	}
+	uID, err := strconv.Atoi(columns[0])
+	if err != nil {
+		return emptyUser, err
+	}
+	serverAdmin := false
+	if len(columns) == 3 {
+		if serverAdmin, err = strconv.ParseBool(columns[2]); err != nil {
+			return emptyUser, err
+		}
+	}
+
+	return UserDAO{
+		ID:          uID,
+		Name:        columns[1],
+		ServerAdmin: serverAdmin,
+	}, nil
+}
+
+func (repo UsersRepo) GetAllUsers() (UsersDAO, error) {
+	users := UsersDAO{}
+	rows := repo.FileWrapper.Lines
+
+	for i := 1; i < len(*rows)-1; i++ {
+		u, err := repo.rowToUser((*rows)[i])
+		if err != nil {
+			return UsersDAO{}, err
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+func (repo UsersRepo) AddUser(u UserDAO) error {
+	line, err := repo.ToRow(u)
+	if err != nil {
+		return err
+	}
+	if err = repo.updateHeader(); err != nil {
+		return err
+	}
+	return repo.FileWrapper.AppendLine(line)
+}
+
+func (repo UsersRepo) UpdateUser(u UserDAO) error {
+	line, err := repo.ToRow(u)
+	if err != nil {
+		return err
+	}
+	for i := 1; i < len(*repo.FileWrapper.Lines)-1; i++ {
+		if strings.Split((*repo.FileWrapper.Lines)[i], repo.FileSeparator)[0] == strconv.Itoa(u.ID) {
+			if err = repo.updateHeader(); err != nil {
+				return err
+			}
+			return repo.FileWrapper.ReplaceLine(i, line)
+		}
+	}
+	return fmt.Errorf("user %d not found", u.ID)
+}
+
+func (repo UsersRepo) updateHeader() error {
+	if (*repo.FileWrapper.Lines)[0] == usersDBHeader {
+		return nil
+	}
+	return repo.FileWrapper.ReplaceLine(0, usersDBHeader)
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+)
+
+const (
+	MaxAttachmentSize int = 10 << 20
+)
+
+var (
+	AllowedAttachmentContentTypes = []string{
+		"image/jpeg", "image/png", "image/gif", "image/webp", "application/pdf",
+	}
+)
+
+type AttachmentDTO struct {
+	ID            string `json:"id"`
+	Entity        string `json:"entity"`
+	EntityKind    string `json:"entity_type"`
+	TransactionID string `json:"transaction_id"`
+	FileName      string `json:"file_name"`
+	ContentType   string `json:"content_type"`
+	Size          int    `json:"size"`
+	SHA256        string `json:"sha256"`
+	UploadedAt    string `json:"uploaded_at"`
+}
+
+type AttachmentsDTO []AttachmentDTO
+
+// AttachmentBackupDTO is an attachment with its content.
+type AttachmentBackupDTO struct {
+	Entity        string `json:"entity"`
+	EntityKind    string `json:"type"`
+	TransactionID string `json:"transaction_id"`
+	FileName      string `json:"file_name"`
+	UploadedAt    string `json:"uploaded_at,omitempty"`
+	Content       []byte `json:"content"`
+}
+
+func newAttachmentDTO(aDAO repositories.AttachmentDAO) AttachmentDTO {
+	return AttachmentDTO{
+		ID:            strconv.Itoa(aDAO.ID),
+		Entity:        aDAO.Entity,
+		EntityKind:    aDAO.Kind,
+		TransactionID: strconv.Itoa(aDAO.TransactionID),
+		FileName:      aDAO.FileName,
+		ContentType:   aDAO.ContentType,
+		Size:          aDAO.Size,
+		SHA256:        aDAO.Hash,
+		UploadedAt:    aDAO.UploadedAt.Format(time.RFC3339),
+	}
+}
+
+// AttachmentContentType gets the allowed type of the content.
+func AttachmentContentType(content []byte) (string, error) {
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(content))
+	if err != nil {
+		return "", err
+	}
+	for _, allowed := range AllowedAttachmentContentTypes {
+		if contentType == allowed {
+			return contentType, nil
+		}
+	}
+	return "", fmt.Errorf("the content type %q is not allowed, only %s", contentType,
+		strings.Join(AllowedAttachmentContentTypes, ", "))
+}
+
+func attachmentFileName(fileName string) string {
+	name := filepath.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r == ';' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "." || name == "/" {
+		return "attachment"
+	}
+	return name
+}
+
+// GetTransactionByID gets the transaction with the ID.
+func GetTransactionByID(repo *repositories.TransactionsRepo, id int) (models.Transaction, error) {
+	ts, err := GetAllTransactionsByRepo(repo, false)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	for _, t := range *ts {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return models.Transaction{}, fmt.Errorf("transaction %d not found", id)
+}
+
+func GetTransactionAttachments(repo *repositories.TransactionsRepo, tID int) (AttachmentsDTO, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("transactions repo wasn't initialized")
+	}
+	asRepo, err := repo.Namespace.GetTransAttachmentsRepo()
+	if err != nil {
+		return nil, err
+	}
+	asDAO, err := asRepo.GetAttachments(repo.Entity, repo.Kind, tID)
+	if err != nil {
+		return nil, err
+	}
+	asDTO := AttachmentsDTO{}
+	for _, aDAO := range asDAO {
+		asDTO = append(asDTO, newAttachmentDTO(aDAO))
+	}
+	return asDTO, nil
+}
+
+// GetTransactionAttachment gets the attachment of the transaction with its content.
+func GetTransactionAttachment(repo *repositories.TransactionsRepo, tID, aID int) (AttachmentDTO, []byte, error) {
+	if repo == nil {
+		return AttachmentDTO{}, nil, fmt.Errorf("transactions repo wasn't initialized")
+	}
+	asRepo, err := repo.Namespace.GetTransAttachmentsRepo()
+	if err != nil {
+		return AttachmentDTO{}, nil, err
+	}
+	asDAO, err := asRepo.GetAttachments(repo.Entity, repo.Kind, tID)
+	if err != nil {
+		return AttachmentDTO{}, nil, err
+	}
+	for _, aDAO := range asDAO {
+		if aDAO.ID == aID {
+			content, err := asRepo.Contents.Get(aDAO.Hash)
+			if err != nil {
+				return AttachmentDTO{}, nil, err
+			}
+			return newAttachmentDTO(aDAO), content, nil
+		}
+	}
+	return AttachmentDTO{}, nil, fmt.Errorf("attachment %d not found", aID)
+}
+
+// AddTransactionAttachment attaches the content to the transaction.
+func AddTransactionAttachment(repo *repositories.TransactionsRepo, tID int, fileName string,
+	content []byte) (AttachmentDTO, error) {
+	return addTransactionAttachment(repo, tID, fileName, content, time.Now().UTC())
+}
+
+func addTransactionAttachment(repo *repositories.TransactionsRepo, tID int, fileName string, content []byte,
+	uploadedAt time.Time) (AttachmentDTO, error) {
+	if repo == nil {
+		return AttachmentDTO{}, fmt.Errorf("transactions repo wasn't initialized")
+	}
+	if len(content) == 0 {
+		return AttachmentDTO{}, fmt.Errorf("the attachment is empty")
+	}
+	if len(content) > MaxAttachmentSize {
+		return AttachmentDTO{}, fmt.Errorf("the attachment has %d bytes, more than the max of %d", len(content),
+			MaxAttachmentSize)
+	}
+	contentType, err := AttachmentContentType(content)
+	if err != nil {
+		return AttachmentDTO{}, err
+	}
+	if _, err = GetTransactionByID(repo, tID); err != nil {
+		return AttachmentDTO{}, err
+	}
+
+	asRepo, err := repo.Namespace.GetTransAttachmentsRepo()
+	if err != nil {
+		return AttachmentDTO{}, err
+	}
+	aDAO, err := asRepo.AddAttachment(repositories.AttachmentDAO{
+		Entity:        repo.Entity,
+		Kind:          repo.Kind,
+		TransactionID: tID,
+		FileName:      attachmentFileName(fileName),
+		ContentType:   contentType,
+		UploadedAt:    uploadedAt,
+	}, content)
+	if err != nil {
+		return AttachmentDTO{}, err
+	}
+	return newAttachmentDTO(aDAO), nil
+}
+
+func DeleteTransactionAttachment(repo *repositories.TransactionsRepo, tID, aID int) error {
+	if _, _, err := GetTransactionAttachment(repo, tID, aID); err != nil {
+		return err
+	}
+	asRepo, err := repo.Namespace.GetTransAttachmentsRepo()
+	if err != nil {
+		return err
+	}
+	return asRepo.DeleteAttachment(aID)
+}
+
+func exportAttachments(ns *repositories.Namespace) ([]AttachmentBackupDTO, error) {
+	asRepo, err := ns.GetTransAttachmentsRepo()
+	if err != nil {
+		return nil, err
+	}
+	asDAO, err := asRepo.GetAllAttachments()
+	if err != nil {
+		return nil, err
+	}
+	var asDTO []AttachmentBackupDTO
+	for _, aDAO := range asDAO {
+		content, err := asRepo.Contents.Get(aDAO.Hash)
+		if err != nil {
+			return nil, err
+		}
+		asDTO = append(asDTO, AttachmentBackupDTO{
+			Entity:        aDAO.Entity,
+			EntityKind:    aDAO.Kind,
+			TransactionID: strconv.Itoa(aDAO.TransactionID),
+			FileName:      aDAO.FileName,
+			UploadedAt:    aDAO.UploadedAt.Format(time.RFC3339),
+			Content:       content,
+		})
+	}
+	return asDTO, nil
+}
+
+func importAttachment(repo *repositories.TransactionsRepo, tID int, aDTO AttachmentBackupDTO,
+	counters *ImportCountersDTO) error {
+	asRepo, err := repo.Namespace.GetTransAttachmentsRepo()
+	if err != nil {
+		return err
+	}
+	existing, err := asRepo.GetAttachments(repo.Entity, repo.Kind, tID)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(aDTO.Content)
+	for _, a := range existing {
+		if a.Hash == hex.EncodeToString(sum[:]) {
+			counters.Skipped++
+			return nil
+		}
+	}
+
+	uploadedAt, err := time.Parse(time.RFC3339, aDTO.UploadedAt)
+	if err != nil {
+		uploadedAt = time.Now().UTC()
+	}
+	if _, err = addTransactionAttachment(repo, tID, aDTO.FileName, aDTO.Content, uploadedAt); err != nil {
+		return err
+	}
+	counters.Created++
+	return nil
+}
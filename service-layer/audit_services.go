@@ -0,0 +1,433 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+)
+
+type AuditEntryDTO struct {
+	ID        string          `json:"id"`
+	Timestamp string          `json:"timestamp"`
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id"`
+	Operation string          `json:"operation"`
+	Record    string          `json:"record"`
+	Entity    string          `json:"entity,omitempty"`
+	Kind      string          `json:"type,omitempty"`
+	RecordID  string          `json:"record_id"`
+	Request   string          `json:"request"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+}
+
+type AuditEntriesDTO []AuditEntryDTO
+
+// AuditSnapshot is the JSON of the audited records of a namespace.
+type AuditSnapshot map[auditedRecord]string
+
+type auditedRecord struct {
+	record string
+	entity string
+	kind   string
+	id     int
+}
+
+func newAuditEntryDTO(e models.AuditEntry) AuditEntryDTO {
+	eDTO := AuditEntryDTO{
+		ID:        strconv.Itoa(e.ID),
+		Timestamp: e.Timestamp.Format(time.RFC3339),
+		Actor:     e.Actor,
+		RequestID: e.RequestID,
+		Operation: e.Operation,
+		Record:    e.Record,
+		Entity:    e.Entity,
+		Kind:      e.Kind,
+		RecordID:  strconv.Itoa(e.RecordID),
+		Request:   e.Request,
+	}
+	if e.Before != "" {
+		eDTO.Before = json.RawMessage(e.Before)
+	}
+	if e.After != "" {
+		eDTO.After = json.RawMessage(e.After)
+	}
+	return eDTO
+}
+
+func NewAuditEntriesDTO(es models.AuditEntries) AuditEntriesDTO {
+	esDTO := AuditEntriesDTO{}
+	for _, e := range es {
+		esDTO = append(esDTO, newAuditEntryDTO(e))
+	}
+	return esDTO
+}
+
+func newAuditEntry(eDAO repositories.AuditEntryDAO) models.AuditEntry {
+	return models.AuditEntry{
+		ID:        eDAO.ID,
+		Timestamp: eDAO.Timestamp,
+		Actor:     eDAO.Actor,
+		RequestID: eDAO.RequestID,
+		Operation: eDAO.Operation,
+		Record:    eDAO.Record,
+		Entity:    eDAO.Entity,
+		Kind:      eDAO.Kind,
+		RecordID:  eDAO.RecordID,
+		Request:   eDAO.Request,
+		Before:    eDAO.Before,
+		After:     eDAO.After,
+	}
+}
+
+func newAuditEntryDAO(e models.AuditEntry) repositories.AuditEntryDAO {
+	return repositories.AuditEntryDAO{
+		ID:        e.ID,
+		Timestamp: e.Timestamp,
+		Actor:     e.Actor,
+		RequestID: e.RequestID,
+		Operation: e.Operation,
+		Record:    e.Record,
+		Entity:    e.Entity,
+		Kind:      e.Kind,
+		RecordID:  e.RecordID,
+		Request:   e.Request,
+		Before:    e.Before,
+		After:     e.After,
+	}
+}
+
+func (s AuditSnapshot) add(ar auditedRecord, recordDTO any) error {
+	recordJSON, err := json.Marshal(recordDTO)
+	if err != nil {
+		return err
+	}
+	s[ar] = string(recordJSON)
+	return nil
+}
+
+type auditedScope struct {
+	record string
+	entity string
+	kind   string
+}
+
+type auditedChanges struct {
+	before  AuditSnapshot
+	changed map[auditedRecord]bool
+	scopes  map[auditedScope]bool
+}
+
+var (
+	auditedChangesByNamespace   = map[*repositories.Namespace]*auditedChanges{}
+	auditedChangesByNamespaceMu sync.Mutex
+)
+
+func namespaceAuditedChanges(ns *repositories.Namespace) *auditedChanges {
+	auditedChangesByNamespaceMu.Lock()
+	defer auditedChangesByNamespaceMu.Unlock()
+	return auditedChangesByNamespace[ns]
+}
+
+func (ar auditedRecord) scope() auditedScope {
+	if ar.record != models.TransactionAuditRecord {
+		return auditedScope{record: ar.record}
+	}
+	return auditedScope{record: ar.record, entity: ar.entity, kind: ar.kind}
+}
+
+// StartAuditingChanges starts following the changes of the namespace.
+func StartAuditingChanges(ns *repositories.Namespace) {
+	auditedChangesByNamespaceMu.Lock()
+	defer auditedChangesByNamespaceMu.Unlock()
+	auditedChangesByNamespace[ns] = &auditedChanges{
+		before:  AuditSnapshot{},
+		changed: map[auditedRecord]bool{},
+		scopes:  map[auditedScope]bool{},
+	}
+}
+
+// TakeAuditedChanges stops following the changes and gets the snapshots.
+func TakeAuditedChanges(ns *repositories.Namespace) (AuditSnapshot, AuditSnapshot, error) {
+	c := namespaceAuditedChanges(ns)
+	auditedChangesByNamespaceMu.Lock()
+	delete(auditedChangesByNamespace, ns)
+	auditedChangesByNamespaceMu.Unlock()
+	if c == nil {
+		return AuditSnapshot{}, AuditSnapshot{}, nil
+	}
+
+	after := AuditSnapshot{}
+	for scope := range c.scopes {
+		if err := snapshotAuditedScope(ns, scope, after); err != nil {
+			return nil, nil, err
+		}
+	}
+	for ar := range c.changed {
+		if c.scopes[ar.scope()] {
+			continue
+		}
+		if err := snapshotAuditedRecord(ns, ar, after); err != nil {
+			return nil, nil, err
+		}
+	}
+	return c.before, after, nil
+}
+
+func auditChange(ns *repositories.Namespace, ar auditedRecord) error {
+	c := namespaceAuditedChanges(ns)
+	if c == nil || c.changed[ar] || c.scopes[ar.scope()] {
+		return nil
+	}
+	c.changed[ar] = true
+	return snapshotAuditedRecord(ns, ar, c.before)
+}
+
+func auditScopeChange(ns *repositories.Namespace, scope auditedScope) error {
+	c := namespaceAuditedChanges(ns)
+	if c == nil || c.scopes[scope] {
+		return nil
+	}
+	c.scopes[scope] = true
+	scoped := AuditSnapshot{}
+	if err := snapshotAuditedScope(ns, scope, scoped); err != nil {
+		return err
+	}
+	for ar, recordJSON := range scoped {
+		if !c.changed[ar] {
+			c.changed[ar] = true
+			c.before[ar] = recordJSON
+		}
+	}
+	return nil
+}
+
+func auditTransactionChange(repo *repositories.TransactionsRepo, id int) error {
+	return auditChange(repo.Namespace, auditedRecord{models.TransactionAuditRecord, repo.Entity, repo.Kind, id})
+}
+
+func auditCategoryChange(ns *repositories.Namespace, id int) error {
+	return auditChange(ns, auditedRecord{models.CategoryAuditRecord, "", "", id})
+}
+
+func auditEntityChange(ns *repositories.Namespace, tse repositories.TransactionsEntityDAO) error {
+	return auditChange(ns, auditedRecord{models.EntityAuditRecord, tse.Entity, tse.Kind, tse.ID})
+}
+
+func auditAllChanges(ns *repositories.Namespace) error {
+	repos, err := ns.GetAllRepos()
+	if err != nil {
+		return err
+	}
+	for _, repo := range *repos {
+		if err = auditScopeChange(ns, auditedScope{models.TransactionAuditRecord, repo.Entity, repo.Kind}); err != nil {
+			return err
+		}
+	}
+	if err = auditScopeChange(ns, auditedScope{record: models.CategoryAuditRecord}); err != nil {
+		return err
+	}
+	return auditScopeChange(ns, auditedScope{record: models.EntityAuditRecord})
+}
+
+func snapshotAuditedScope(ns *repositories.Namespace, scope auditedScope, s AuditSnapshot) error {
+	switch scope.record {
+	case models.TransactionAuditRecord:
+		repo, err := ns.GetTransRepo(scope.kind, scope.entity)
+		if err != nil {
+			return err
+		}
+		ts, err := GetAllTransactionsByRepo(repo, false)
+		if err != nil {
+			return err
+		}
+		for _, t := range *ts {
+			if err = s.addTransaction(repo, t); err != nil {
+				return err
+			}
+		}
+	case models.CategoryAuditRecord:
+		csRepo, err := ns.GetCategoriesRepo()
+		if err != nil {
+			return err
+		}
+		csDAO, err := csRepo.GetAllCategories()
+		if err != nil {
+			return err
+		}
+		for _, c := range newCategories(csDAO) {
+			if err = s.add(auditedRecord{models.CategoryAuditRecord, "", "", c.ID}, newCategoryDTO(c)); err != nil {
+				return err
+			}
+		}
+	case models.EntityAuditRecord:
+		tsesRepo, err := ns.GetTransEntRepo()
+		if err != nil {
+			return err
+		}
+		tsesDAO, err := tsesRepo.GetAllTransactionsEntities()
+		if err != nil {
+			return err
+		}
+		for _, tse := range newTransactionsEntities(tsesDAO) {
+			ar := auditedRecord{models.EntityAuditRecord, tse.Entity, tse.Kind, tse.ID}
+			if err = s.add(ar, newTransactionsEntityDTO(tse)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func snapshotAuditedRecord(ns *repositories.Namespace, ar auditedRecord, s AuditSnapshot) error {
+	if ar.record != models.TransactionAuditRecord {
+		scoped := AuditSnapshot{}
+		if err := snapshotAuditedScope(ns, ar.scope(), scoped); err != nil {
+			return err
+		}
+		if recordJSON, found := scoped[ar]; found {
+			s[ar] = recordJSON
+		}
+		return nil
+	}
+
+	repo, err := ns.GetTransRepo(ar.kind, ar.entity)
+	if err != nil {
+		return err
+	}
+	ts, err := GetAllTransactionsByRepo(repo, false)
+	if err != nil {
+		return err
+	}
+	for _, t := range *ts {
+		if t.ID == ar.id {
+			return s.addTransaction(repo, t)
+		}
+	}
+	return nil
+}
+
+func (s AuditSnapshot) addTransaction(repo *repositories.TransactionsRepo, t models.Transaction) error {
+	tDTO, err := newTransactionDTO(repo.Namespace, t)
+	if err != nil {
+		return err
+	}
+	return s.add(auditedRecord{models.TransactionAuditRecord, repo.Entity, repo.Kind, t.ID}, tDTO)
+}
+
+// AddAuditEntries appends the changes between the snapshots to the audit log.
+func AddAuditEntries(repo *repositories.AuditRepo, before, after AuditSnapshot, actor, requestID, request string,
+	now time.Time) error {
+	if repo == nil {
+		return fmt.Errorf("audit repo wasn't initialized")
+	}
+	es := models.AuditEntries{}
+	newEntry := func(ar auditedRecord, operation string) models.AuditEntry {
+		return models.AuditEntry{
+			Timestamp: now,
+			Actor:     actor,
+			RequestID: requestID,
+			Operation: operation,
+			Record:    ar.record,
+			Entity:    ar.entity,
+			Kind:      ar.kind,
+			RecordID:  ar.id,
+			Request:   request,
+			Before:    before[ar],
+			After:     after[ar],
+		}
+	}
+	for ar, recordJSON := range after {
+		previousJSON, found := before[ar]
+		switch {
+		case !found:
+			es = append(es, newEntry(ar, models.CreateAuditOperation))
+		case previousJSON != recordJSON:
+			es = append(es, newEntry(ar, models.UpdateAuditOperation))
+		}
+	}
+	for ar := range before {
+		if _, found := after[ar]; !found {
+			es = append(es, newEntry(ar, models.DeleteAuditOperation))
+		}
+	}
+	if len(es) == 0 {
+		return nil
+	}
+
+	sort.Slice(es, func(i, j int) bool {
+		if es[i].Record != es[j].Record {
+			return es[i].Record > es[j].Record
+		}
+		if es[i].Entity != es[j].Entity {
+			return es[i].Entity < es[j].Entity
+		}
+		if es[i].Kind != es[j].Kind {
+			return es[i].Kind < es[j].Kind
+		}
+		return es[i].RecordID < es[j].RecordID
+	})
+	esDAO := repositories.AuditEntriesDAO{}
+	for _, e := range es {
+		esDAO = append(esDAO, newAuditEntryDAO(e))
+	}
+	return repo.AddAuditEntries(esDAO)
+}
+
+func getAllAuditEntries(repo *repositories.AuditRepo) (models.AuditEntries, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("audit repo wasn't initialized")
+	}
+	esDAO, err := repo.GetAllAuditEntries()
+	if err != nil {
+		return nil, err
+	}
+	es := models.AuditEntries{}
+	for _, eDAO := range esDAO {
+		es = append(es, newAuditEntry(eDAO))
+	}
+	return es, nil
+}
+
+// GetAuditEntries gets the entries of the audit log.
+func GetAuditEntries(repo *repositories.AuditRepo, entity string, from, to time.Time) (models.AuditEntries, error) {
+	es, err := getAllAuditEntries(repo)
+	if err != nil {
+		return nil, err
+	}
+	filtered := models.AuditEntries{}
+	for _, e := range es {
+		if entity != "" && e.Entity != entity {
+			continue
+		}
+		if !from.IsZero() && e.Timestamp.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !e.Timestamp.Before(to.AddDate(0, 0, 1)) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered, nil
+}
+
+// GetTransactionHistory gets the entries of the audit log of the transaction.
+func GetTransactionHistory(repo *repositories.AuditRepo, entity, kind string, tID int) (models.AuditEntries, error) {
+	es, err := getAllAuditEntries(repo)
+	if err != nil {
+		return nil, err
+	}
+	history := models.AuditEntries{}
+	for _, e := range es {
+		if e.Record == models.TransactionAuditRecord && e.Entity == entity && e.Kind == kind && e.RecordID == tID {
+			history = append(history, e)
+		}
+	}
+	return history, nil
+}
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	SessionDuration   = 12 * time.Hour
+	MaxFailedLogins   = 5
+	LockoutDuration   = 15 * time.Minute
+	MinPasswordLength = 8
+	// bcrypt ignores what's after the first 72 bytes
+	MaxPasswordLength = 72
+
+	maxUnknownLogins = 10000
+)
+
+var (
+	// compared with the passwords of the unknown users, so their logins take as long
+	unknownUserPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("unknown user password"), bcrypt.DefaultCost)
+
+	DefaultSessionScopes = []string{models.WriteScope}
+
+	unknownLogins = map[string]*failedLogins{}
+)
+
+type failedLogins struct {
+	count        int
+	lockedUntil  time.Time
+	lastFailedAt time.Time
+}
+
+type LoginDTO struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+type PasswordDTO struct {
+	User            string   `json:"user,omitempty"`
+	CurrentPassword string   `json:"current_password,omitempty"`
+	NewPassword     string   `json:"new_password"`
+	Scopes          []string `json:"scopes,omitempty"`
+}
+
+type SessionDTO struct {
+	User      string   `json:"user"`
+	Scopes    []string `json:"scopes"`
+	CSRFToken string   `json:"csrf_token,omitempty"`
+	ExpiresAt string   `json:"expires_at,omitempty"`
+}
+
+func NewSessionDTO(u models.User, s models.Session) SessionDTO {
+	return SessionDTO{
+		User:      u.Name,
+		Scopes:    s.Scopes,
+		CSRFToken: s.CSRFToken,
+		ExpiresAt: s.ExpiresAt.Format(time.RFC3339),
+	}
+}
+
+func newCredential(cDAO repositories.CredentialDAO) models.Credential {
+	return models.Credential{
+		UserID:       cDAO.UserID,
+		Scopes:       cDAO.Scopes,
+		PasswordHash: cDAO.PasswordHash,
+		FailedLogins: cDAO.FailedLogins,
+		LockedUntil:  cDAO.LockedUntil,
+	}
+}
+
+func newCredentialDAO(c models.Credential) repositories.CredentialDAO {
+	return repositories.CredentialDAO{
+		UserID:       c.UserID,
+		Scopes:       c.Scopes,
+		PasswordHash: c.PasswordHash,
+		FailedLogins: c.FailedLogins,
+		LockedUntil:  c.LockedUntil,
+	}
+}
+
+func newSession(sDAO repositories.SessionDAO) models.Session {
+	return models.Session{
+		Hash:      sDAO.Hash,
+		UserID:    sDAO.UserID,
+		Scopes:    sDAO.Scopes,
+		CSRFToken: sDAO.CSRFToken,
+		ExpiresAt: sDAO.ExpiresAt,
+	}
+}
+
+func newSessionDAO(s models.Session) repositories.SessionDAO {
+	return repositories.SessionDAO{
+		Hash:      s.Hash,
+		UserID:    s.UserID,
+		Scopes:    s.Scopes,
+		CSRFToken: s.CSRFToken,
+		ExpiresAt: s.ExpiresAt,
+	}
+}
+
+func randomHex(size int) (string, error) {
+	secret := make([]byte, size)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// CheckPassword checks the length of the password.
+func CheckPassword(password string) error {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return fmt.Errorf("the password must have between %d and %d bytes", MinPasswordLength, MaxPasswordLength)
+	}
+	return nil
+}
+
+func getCredential(repo *repositories.CredentialsRepo, uID int) (models.Credential, bool, error) {
+	if repo == nil {
+		return models.Credential{}, false, fmt.Errorf("credentials repo wasn't initialized")
+	}
+	cDAO, found, err := repo.GetCredential(uID)
+	if err != nil || !found {
+		return models.Credential{}, found, err
+	}
+	return newCredential(cDAO), true, nil
+}
+
+func HasPassword(repo *repositories.CredentialsRepo, uID int) (bool, error) {
+	_, found, err := getCredential(repo, uID)
+	return found, err
+}
+
+// SetPassword sets the password of the user.
+func SetPassword(repo *repositories.CredentialsRepo, uID int, password string, scopes []string) error {
+	if err := CheckPassword(password); err != nil {
+		return err
+	}
+	c, found, err := getCredential(repo, uID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(scopes) > 0:
+		if c.Scopes, err = newScopes(scopes); err != nil {
+			return err
+		}
+	case !found:
+		c.Scopes = DefaultSessionScopes
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	c.UserID = uID
+	c.PasswordHash = string(hash)
+	c.FailedLogins = 0
+	c.LockedUntil = time.Time{}
+	return repo.SetCredential(newCredentialDAO(c))
+}
+
+// CheckLoginIsNotLocked checks the logins of the user aren't locked.
+func CheckLoginIsNotLocked(repo *repositories.CredentialsRepo, usersRepo *repositories.UsersRepo, userName string,
+	now time.Time) error {
+	lockedUntil := time.Time{}
+	if fl, found := unknownLogins[userName]; found {
+		lockedUntil = fl.lockedUntil
+	}
+	if u, err := GetUserByName(usersRepo, userName); err == nil {
+		c, found, err := getCredential(repo, u.ID)
+		if err != nil {
+			return err
+		}
+		if found {
+			lockedUntil = c.LockedUntil
+		}
+	}
+	if now.Before(lockedUntil) {
+		return fmt.Errorf("the logins of the user %q are locked until %s", userName, lockedUntil.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func countUnknownFailedLogin(userName string, now time.Time) {
+	fl, found := unknownLogins[userName]
+	if !found {
+		if len(unknownLogins) >= maxUnknownLogins {
+			evictUnknownLogins(now)
+		}
+		fl = &failedLogins{}
+		unknownLogins[userName] = fl
+	}
+	fl.lastFailedAt = now
+	fl.count++
+	if fl.count >= MaxFailedLogins {
+		fl.count = 0
+		fl.lockedUntil = now.Add(LockoutDuration)
+	}
+}
+
+func evictUnknownLogins(now time.Time) {
+	for userName, fl := range unknownLogins {
+		if !now.Before(fl.lockedUntil) && now.Sub(fl.lastFailedAt) >= LockoutDuration {
+			delete(unknownLogins, userName)
+		}
+	}
+	for len(unknownLogins) >= maxUnknownLogins {
+		var oldest string
+		var oldestLocked, found bool
+		for userName, fl := range unknownLogins {
+			locked := now.Before(fl.lockedUntil)
+			if !found || (oldestLocked && !locked) ||
+				(oldestLocked == locked && fl.lastFailedAt.Before(unknownLogins[oldest].lastFailedAt)) {
+				oldest, oldestLocked, found = userName, locked, true
+			}
+		}
+		delete(unknownLogins, oldest)
+	}
+}
+
+// Login checks the password of the user and starts a session.
+func Login(repo *repositories.CredentialsRepo, sessionsRepo *repositories.SessionsRepo,
+	usersRepo *repositories.UsersRepo, lDTO LoginDTO, now time.Time) (models.User, models.Session, string, error) {
+	invalidLoginErr := fmt.Errorf("the user or the password is not valid")
+	u, err := GetUserByName(usersRepo, lDTO.User)
+	if err != nil {
+		_ = bcrypt.CompareHashAndPassword(unknownUserPasswordHash, []byte(lDTO.Password))
+		countUnknownFailedLogin(lDTO.User, now)
+		return u, models.Session{}, "", invalidLoginErr
+	}
+	c, found, err := getCredential(repo, u.ID)
+	if err != nil {
+		return u, models.Session{}, "", err
+	}
+	if !found {
+		_ = bcrypt.CompareHashAndPassword(unknownUserPasswordHash, []byte(lDTO.Password))
+		countUnknownFailedLogin(lDTO.User, now)
+		return u, models.Session{}, "", invalidLoginErr
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(c.PasswordHash), []byte(lDTO.Password)); err != nil {
+		c.FailedLogins++
+		if c.FailedLogins >= MaxFailedLogins {
+			c.FailedLogins = 0
+			c.LockedUntil = now.Add(LockoutDuration)
+		}
+		if err = repo.SetCredential(newCredentialDAO(c)); err != nil {
+			return u, models.Session{}, "", err
+		}
+		return u, models.Session{}, "", invalidLoginErr
+	}
+	if c.FailedLogins > 0 {
+		c.FailedLogins = 0
+		if err = repo.SetCredential(newCredentialDAO(c)); err != nil {
+			return u, models.Session{}, "", err
+		}
+	}
+
+	if err = deleteExpiredSessions(sessionsRepo, now); err != nil {
+		return u, models.Session{}, "", err
+	}
+	sessionID, err := randomHex(32)
+	if err != nil {
+		return u, models.Session{}, "", err
+	}
+	csrfToken, err := randomHex(32)
+	if err != nil {
+		return u, models.Session{}, "", err
+	}
+	s := models.Session{
+		Hash:      hashToken(sessionID),
+		UserID:    u.ID,
+		Scopes:    c.Scopes,
+		CSRFToken: csrfToken,
+		ExpiresAt: now.Add(SessionDuration),
+	}
+	if err = sessionsRepo.AddSession(newSessionDAO(s)); err != nil {
+		return u, models.Session{}, "", err
+	}
+	return u, s, sessionID, nil
+}
+
+// ChangePassword changes the password of the user and ends their sessions.
+func ChangePassword(repo *repositories.CredentialsRepo, sessionsRepo *repositories.SessionsRepo, uID int,
+	currentPassword, newPassword string, scopes []string) error {
+	c, found, err := getCredential(repo, uID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("the user has no password, which only a server admin can set")
+	}
+	if err = bcrypt.CompareHashAndPassword([]byte(c.PasswordHash), []byte(currentPassword)); err != nil {
+		return fmt.Errorf("the current password is not valid")
+	}
+	if err = SetPassword(repo, uID, newPassword, scopes); err != nil {
+		return err
+	}
+	return sessionsRepo.DeleteUserSessions(uID)
+}
+
+// ResetPassword sets the password of the user and ends their sessions.
+func ResetPassword(repo *repositories.CredentialsRepo, sessionsRepo *repositories.SessionsRepo, uID int,
+	newPassword string, scopes []string) error {
+	if err := SetPassword(repo, uID, newPassword, scopes); err != nil {
+		return err
+	}
+	return sessionsRepo.DeleteUserSessions(uID)
+}
+
+func deleteExpiredSessions(repo *repositories.SessionsRepo, now time.Time) error {
+	ssDAO, err := repo.GetAllSessions()
+	if err != nil {
+		return err
+	}
+	for _, sDAO := range ssDAO {
+		if !now.Before(sDAO.ExpiresAt) {
+			if err = repo.DeleteSession(sDAO.Hash); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// AuthenticateSession gets the session of the session ID.
+func AuthenticateSession(repo *repositories.SessionsRepo, usersRepo *repositories.UsersRepo, sessionID string,
+	now time.Time) (models.Session, models.User, error) {
+	if repo == nil {
+		return models.Session{}, models.User{}, fmt.Errorf("sessions repo wasn't initialized")
+	}
+	ssDAO, err := repo.GetAllSessions()
+	if err != nil {
+		return models.Session{}, models.User{}, err
+	}
+	hash := hashToken(sessionID)
+	for _, sDAO := range ssDAO {
+		if sDAO.Hash != hash {
+			continue
+		}
+		if !now.Before(sDAO.ExpiresAt) {
+			if err = repo.DeleteSession(sDAO.Hash); err != nil {
+				return models.Session{}, models.User{}, err
+			}
+			break
+		}
+		u, err := GetUserByID(usersRepo, sDAO.UserID)
+		if err != nil {
+			return models.Session{}, models.User{}, err
+		}
+		return newSession(sDAO), u, nil
+	}
+	return models.Session{}, models.User{}, fmt.Errorf("the session is not valid or expired")
+}
+
+// CheckCSRFToken checks the CSRF token sent with a request of the session.
+func CheckCSRFToken(s models.Session, csrfToken string) error {
+	if subtle.ConstantTimeCompare([]byte(s.CSRFToken), []byte(csrfToken)) != 1 {
+		return fmt.Errorf("the CSRF token is missing or not valid")
+	}
+	return nil
+}
+
+func Logout(repo *repositories.SessionsRepo, s models.Session) error {
+	if repo == nil {
+		return fmt.Errorf("sessions repo wasn't initialized")
+	}
+	return repo.DeleteSession(s.Hash)
+}
@@ -0,0 +1,653 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+)
+
+const (
+	BackupVersion int = 2
+	MaxBackupSize int = 256 << 20
+
+	legacyAmountsBackupVersion int = 1
+
+	SkipImportStrategy      ImportStrategy = "skip"
+	OverwriteImportStrategy ImportStrategy = "overwrite"
+	RenumberImportStrategy  ImportStrategy = "renumber"
+)
+
+// ImportStrategy tells what to do with an imported record whose ID already exists.
+type ImportStrategy string
+
+var (
+	ImportStrategies = []ImportStrategy{
+		SkipImportStrategy, OverwriteImportStrategy, RenumberImportStrategy,
+	}
+)
+
+type EntityTransactionsDTO struct {
+	Entity       string           `json:"entity"`
+	Kind         string           `json:"type"`
+	Transactions []TransactionDTO `json:"transactions"`
+}
+
+// BackupDTO is the versioned document with the whole ledger.
+type BackupDTO struct {
+	Version            int                         `json:"version"`
+	ExportedAt         string                      `json:"exported_at"`
+	Entities           TransactionsEntitiesDTO     `json:"entities"`
+	BalanceEntries     BalanceEntriesDTO           `json:"balance_entries,omitempty"`
+	CreditCards        []CreditCardDTO             `json:"credit_cards,omitempty"`
+	Categories         CategoriesDTO               `json:"categories"`
+	Payees             PayeesDTO                   `json:"payees,omitempty"`
+	CustomFields       CustomFieldsDTO             `json:"custom_fields,omitempty"`
+	Transactions       []EntityTransactionsDTO     `json:"transactions"`
+	Attachments        []AttachmentBackupDTO       `json:"attachments,omitempty"`
+	StatementsPayments []StatementPaymentBackupDTO `json:"statements_payments,omitempty"`
+	Loans              LoansDTO                    `json:"loans,omitempty"`
+	LoansInstallments  []LoanInstallmentBackupDTO  `json:"loans_installments,omitempty"`
+	Goals              GoalsDTO                    `json:"goals,omitempty"`
+	Participants       ParticipantsDTO             `json:"participants,omitempty"`
+	SharedTransactions []SharedTransactionDTO      `json:"shared_transactions,omitempty"`
+	Settlements        SettlementsDTO              `json:"settlements,omitempty"`
+}
+
+type ImportCountersDTO struct {
+	Created    int `json:"created"`
+	Updated    int `json:"updated"`
+	Skipped    int `json:"skipped"`
+	Renumbered int `json:"renumbered"`
+}
+
+type ImportReportDTO struct {
+	Strategy           ImportStrategy    `json:"strategy"`
+	Entities           ImportCountersDTO `json:"entities"`
+	BalanceEntries     ImportCountersDTO `json:"balance_entries"`
+	CreditCards        ImportCountersDTO `json:"credit_cards"`
+	Categories         ImportCountersDTO `json:"categories"`
+	Payees             ImportCountersDTO `json:"payees"`
+	CustomFields       ImportCountersDTO `json:"custom_fields"`
+	Transactions       ImportCountersDTO `json:"transactions"`
+	Attachments        ImportCountersDTO `json:"attachments"`
+	StatementsPayments ImportCountersDTO `json:"statements_payments"`
+	Loans              ImportCountersDTO `json:"loans"`
+	LoansInstallments  ImportCountersDTO `json:"loans_installments"`
+	Goals              ImportCountersDTO `json:"goals"`
+	Participants       ImportCountersDTO `json:"participants"`
+	SharedTransactions ImportCountersDTO `json:"shared_transactions"`
+	Settlements        ImportCountersDTO `json:"settlements"`
+}
+
+func ImportStrategyIsValid(strategy string) bool {
+	for _, s := range ImportStrategies {
+		if string(s) == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+func ExportBackup(tsesRepo *repositories.TransactionsEntitiesRepo, csRepo *repositories.CategoriesRepo,
+	tsRepos []*repositories.TransactionsRepo) (*BackupDTO, error) {
+	if tsesRepo == nil {
+		return nil, fmt.Errorf("transactions entities repo wasn't initialized")
+	}
+	if csRepo == nil {
+		return nil, fmt.Errorf("categories repo wasn't initialized")
+	}
+
+	ns := tsesRepo.Namespace
+	tses, err := GetAllTransactionsEntities(tsesRepo, models.RefToTransactionsEntities)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := GetAllCategories(csRepo, models.RefToCategories)
+	if err != nil {
+		return nil, err
+	}
+
+	psRepo, err := ns.GetPayeesRepo()
+	if err != nil {
+		return nil, err
+	}
+	ps, err := GetAllPayees(psRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	backup := &BackupDTO{
+		Version:      BackupVersion,
+		ExportedAt:   time.Now().UTC().Format(time.RFC3339),
+		Entities:     NewTransactionsEntitiesDTO(*tses),
+		Categories:   NewCategoriesDTO(*cs),
+		Payees:       NewPayeesDTO(ps),
+		Transactions: []EntityTransactionsDTO{},
+	}
+
+	for _, tsRepo := range tsRepos {
+		ts, err := GetAllTransactionsByRepo(tsRepo, false)
+		if err != nil {
+			return nil, err
+		}
+		tsDTO, err := NewTransactionsDTO(ns, *ts)
+		if err != nil {
+			return nil, err
+		}
+		etsDTO := EntityTransactionsDTO{
+			Entity:       tsRepo.Entity,
+			Kind:         tsRepo.Kind,
+			Transactions: tsDTO.TransactionsDTO,
+		}
+		if etsDTO.Transactions == nil {
+			etsDTO.Transactions = []TransactionDTO{}
+		}
+		backup.Transactions = append(backup.Transactions, etsDTO)
+	}
+
+	if backup.CustomFields, err = exportCustomFields(ns); err != nil {
+		return nil, err
+	}
+	if backup.BalanceEntries, err = exportBalanceEntries(ns); err != nil {
+		return nil, err
+	}
+	if backup.CreditCards, err = exportCreditCards(ns); err != nil {
+		return nil, err
+	}
+	if backup.StatementsPayments, err = exportStatementsPayments(ns); err != nil {
+		return nil, err
+	}
+	if backup.Attachments, err = exportAttachments(ns); err != nil {
+		return nil, err
+	}
+	if backup.Loans, backup.LoansInstallments, err = exportLoans(ns); err != nil {
+		return nil, err
+	}
+	if backup.Goals, err = exportGoals(ns); err != nil {
+		return nil, err
+	}
+	if backup.Participants, err = exportParticipants(ns); err != nil {
+		return nil, err
+	}
+	if backup.SharedTransactions, err = exportSharedTransactions(ns); err != nil {
+		return nil, err
+	}
+	if backup.Settlements, err = exportSettlements(ns); err != nil {
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+// ImportBackup imports the backup into a staged copy of the data, committed when it all succeeds.
+func ImportBackup(ns *repositories.Namespace, backup BackupDTO, strategy ImportStrategy) (*ImportReportDTO, error) {
+	if backup.Version != BackupVersion && backup.Version != legacyAmountsBackupVersion {
+		return nil, fmt.Errorf("the backup version %d is not supported", backup.Version)
+	}
+
+	if err := auditAllChanges(ns); err != nil {
+		return nil, err
+	}
+	staged, err := ns.Stage()
+	if err != nil {
+		return nil, err
+	}
+	report, err := importStagedBackup(staged, backup, strategy)
+	if err != nil {
+		if discardErr := ns.Discard(staged); discardErr != nil {
+			return nil, discardErr
+		}
+		invalidateSearchIndex(ns)
+		return nil, err
+	}
+	if err = ns.Commit(staged); err != nil {
+		return nil, err
+	}
+	invalidateSearchIndex(ns)
+
+	return report, nil
+}
+
+func importStagedBackup(ns *repositories.Namespace, backup BackupDTO, strategy ImportStrategy) (*ImportReportDTO, error) {
+	tsesRepo, err := ns.GetTransEntRepo()
+	if err != nil {
+		return nil, err
+	}
+	csRepo, err := ns.GetCategoriesRepo()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReportDTO{
+		Strategy: strategy,
+	}
+
+	for _, cDTO := range backup.Categories {
+		if err := importCategory(csRepo, cDTO, strategy, &report.Categories); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(backup.Payees) != 0 {
+		psRepo, err := ns.GetPayeesRepo()
+		if err != nil {
+			return nil, err
+		}
+		for _, pDTO := range backup.Payees {
+			if err = importPayee(psRepo, pDTO, strategy, &report.Payees); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(backup.CustomFields) != 0 {
+		cfsRepo, err := ns.GetCustomFieldsRepo()
+		if err != nil {
+			return nil, err
+		}
+		for _, cfDTO := range backup.CustomFields {
+			if err = importCustomField(cfsRepo, cfDTO, strategy, &report.CustomFields); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, tseDTO := range backup.Entities {
+		if err := importTransactionsEntity(tsesRepo, tseDTO, strategy, &report.Entities); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(backup.CreditCards) != 0 {
+		ccsRepo, err := ns.GetCreditCardsRepo()
+		if err != nil {
+			return nil, err
+		}
+		for _, ccDTO := range backup.CreditCards {
+			if err = importCreditCard(ccsRepo, ccDTO, strategy, &report.CreditCards); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(backup.BalanceEntries) != 0 {
+		besRepo, err := ns.GetBalanceEntriesRepo()
+		if err != nil {
+			return nil, err
+		}
+		for _, beDTO := range backup.BalanceEntries {
+			if err = importBalanceEntry(besRepo, beDTO, strategy, &report.BalanceEntries); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	importedIDs := make(map[string]int)
+	for _, etsDTO := range backup.Transactions {
+		tsRepo, err := ns.GetTransRepo(etsDTO.Kind, etsDTO.Entity)
+		if err != nil {
+			return nil, err
+		}
+		for _, tDTO := range etsDTO.Transactions {
+			if backup.Version == legacyAmountsBackupVersion {
+				tDTO.Kind, tDTO.Amount = normalizeLegacyAmount(tDTO.Kind, tDTO.Amount)
+			}
+			id, err := importTransaction(csRepo, tsRepo, tDTO, strategy, &report.Transactions)
+			if err != nil {
+				return nil, err
+			}
+			if tDTO.ID != "" && id != -1 {
+				importedIDs[fmt.Sprintf("%s;%s;%s", etsDTO.Entity, etsDTO.Kind, tDTO.ID)] = id
+			}
+		}
+		if err = updateRefToTransactions(tsRepo); err != nil {
+			return nil, err
+		}
+		if err = updateBalance(tsesRepo, tsRepo); err != nil {
+			return nil, err
+		}
+	}
+	for _, aDTO := range backup.Attachments {
+		id, found := importedIDs[fmt.Sprintf("%s;%s;%s", aDTO.Entity, aDTO.EntityKind, aDTO.TransactionID)]
+		if !found {
+			report.Attachments.Skipped++
+			continue
+		}
+		tsRepo, err := ns.GetTransRepo(aDTO.EntityKind, aDTO.Entity)
+		if err != nil {
+			return nil, err
+		}
+		if err = importAttachment(tsRepo, id, aDTO, &report.Attachments); err != nil {
+			return nil, err
+		}
+	}
+	if len(backup.StatementsPayments) != 0 {
+		spRepo, err := ns.GetStatementsPaymentsRepo()
+		if err != nil {
+			return nil, err
+		}
+		for _, spDTO := range backup.StatementsPayments {
+			id, found := importedIDs[fmt.Sprintf("%s;%s;%s", spDTO.Entity, spDTO.EntityKind, spDTO.TransactionID)]
+			if !found {
+				report.StatementsPayments.Skipped++
+				continue
+			}
+			if err = importStatementPayment(spRepo, spDTO, id, &report.StatementsPayments); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(backup.Loans) != 0 {
+		lsRepo, err := ns.GetLoansRepo()
+		if err != nil {
+			return nil, err
+		}
+		liRepo, err := ns.GetLoansInstallmentsRepo()
+		if err != nil {
+			return nil, err
+		}
+		loansIDs := make(map[string]int)
+		for _, lDTO := range backup.Loans {
+			id, err := importLoan(lsRepo, lDTO, strategy, &report.Loans)
+			if err != nil {
+				return nil, err
+			}
+			if id != -1 {
+				loansIDs[lDTO.ID] = id
+			}
+		}
+		for _, liDTO := range backup.LoansInstallments {
+			lID, lFound := loansIDs[liDTO.LoanID]
+			tID, tFound := importedIDs[fmt.Sprintf("%s;%s;%s", liDTO.Entity, liDTO.EntityKind, liDTO.TransactionID)]
+			if !lFound || !tFound {
+				report.LoansInstallments.Skipped++
+				continue
+			}
+			if err = importLoanInstallment(liRepo, liDTO, lID, tID, &report.LoansInstallments); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(backup.Goals) != 0 {
+		gsRepo, err := ns.GetGoalsRepo()
+		if err != nil {
+			return nil, err
+		}
+		for _, gDTO := range backup.Goals {
+			if err = importGoal(gsRepo, gDTO, strategy, &report.Goals); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(backup.Participants) != 0 {
+		psRepo, err := ns.GetParticipantsRepo()
+		if err != nil {
+			return nil, err
+		}
+		for _, pDTO := range backup.Participants {
+			if err = importParticipant(psRepo, pDTO, strategy, &report.Participants); err != nil {
+				return nil, err
+			}
+		}
+		shRepo, err := ns.GetTransSharesRepo()
+		if err != nil {
+			return nil, err
+		}
+		for _, stDTO := range backup.SharedTransactions {
+			id, found := importedIDs[fmt.Sprintf("%s;%s;%s", stDTO.Entity, stDTO.EntityKind, stDTO.TransactionID)]
+			if !found {
+				report.SharedTransactions.Skipped++
+				continue
+			}
+			if err = importSharedTransaction(shRepo, psRepo, stDTO, id, &report.SharedTransactions); err != nil {
+				return nil, err
+			}
+		}
+		sRepo, err := ns.GetSettlementsRepo()
+		if err != nil {
+			return nil, err
+		}
+		for _, sDTO := range backup.Settlements {
+			if err = importSettlement(sRepo, psRepo, sDTO, strategy, &report.Settlements); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func importCategory(repo *repositories.CategoriesRepo, cDTO CategoryDTO, strategy ImportStrategy,
+	counters *ImportCountersDTO) error {
+	cID, err := strconv.Atoi(cDTO.ID)
+	if err != nil {
+		return fmt.Errorf("invalid category ID %q", cDTO.ID)
+	}
+	c, err := cDTO.NewCategory()
+	if err != nil {
+		return err
+	}
+	c.ID = cID
+
+	csDAO, err := repo.GetAllCategories()
+	if err != nil {
+		return err
+	}
+
+	var sameID, sameLabel *repositories.CategoryDAO
+	for i := range csDAO {
+		if csDAO[i].ID == c.ID {
+			sameID = &csDAO[i]
+		}
+		if csDAO[i].Label == c.Label {
+			sameLabel = &csDAO[i]
+		}
+	}
+
+	switch {
+	case sameID != nil && sameID.Label == c.Label:
+		counters.Skipped++
+		return nil
+	case sameLabel != nil:
+		counters.Skipped++
+		return nil
+	case sameID == nil:
+		if err = repo.AddCategory(newCategoryDAO(c)); err != nil {
+			return err
+		}
+		counters.Created++
+		return nil
+	}
+
+	switch strategy {
+	case OverwriteImportStrategy:
+		if err = UpdateCategory(repo, c); err != nil {
+			return err
+		}
+		counters.Updated++
+	case RenumberImportStrategy:
+		if _, err = AddCategory(repo, c); err != nil {
+			return err
+		}
+		counters.Renumbered++
+	default:
+		counters.Skipped++
+	}
+	return nil
+}
+
+func importPayee(repo *repositories.PayeesRepo, pDTO PayeeDTO, strategy ImportStrategy,
+	counters *ImportCountersDTO) error {
+	p, err := pDTO.NewPayee(repo.Namespace)
+	if err != nil {
+		return err
+	}
+	ps, err := GetAllPayees(repo)
+	if err != nil {
+		return err
+	}
+
+	existing, found := findPayeeByName(ps, p.Name)
+	if !found {
+		if _, err = AddPayee(repo, p); err != nil {
+			return err
+		}
+		counters.Created++
+		return nil
+	}
+	if strategy != OverwriteImportStrategy {
+		counters.Skipped++
+		return nil
+	}
+	p.ID = existing.ID
+	if err = UpdatePayee(repo, p); err != nil {
+		return err
+	}
+	counters.Updated++
+	return nil
+}
+
+func importTransactionsEntity(repo *repositories.TransactionsEntitiesRepo, tseDTO TransactionsEntityDTO,
+	strategy ImportStrategy, counters *ImportCountersDTO) error {
+	tseID, err := strconv.Atoi(tseDTO.ID)
+	if err != nil {
+		return fmt.Errorf("invalid entity ID %q", tseDTO.ID)
+	}
+	tse, err := tseDTO.NewTransactionsEntity()
+	if err != nil {
+		return err
+	}
+	tse.ID = tseID
+
+	tsesDAO, err := repo.GetAllTransactionsEntities()
+	if err != nil {
+		return err
+	}
+
+	var sameID, sameEntity *repositories.TransactionsEntityDAO
+	for i := range tsesDAO {
+		if tsesDAO[i].ID == tse.ID {
+			sameID = &tsesDAO[i]
+		}
+		if tsesDAO[i].Entity == tse.Entity && tsesDAO[i].Kind == tse.Kind {
+			sameEntity = &tsesDAO[i]
+		}
+	}
+
+	switch {
+	case sameEntity != nil:
+		if strategy != OverwriteImportStrategy {
+			counters.Skipped++
+			return nil
+		}
+		tse.ID = sameEntity.ID
+		if err = updateTransactionsEntity(repo, tse); err != nil {
+			return err
+		}
+		counters.Updated++
+		return nil
+	case sameID == nil:
+		if err = repo.AddTransactionsEntity(newTransactionsEntityDAO(tse)); err != nil {
+			return err
+		}
+		counters.Created++
+		return nil
+	}
+
+	switch strategy {
+	case OverwriteImportStrategy:
+		if err = updateTransactionsEntity(repo, tse); err != nil {
+			return err
+		}
+		counters.Updated++
+	case RenumberImportStrategy:
+		if _, err = AddTransactionsEntity(repo, tse); err != nil {
+			return err
+		}
+		counters.Renumbered++
+	default:
+		counters.Skipped++
+	}
+	return nil
+}
+
+func importTransaction(csRepo *repositories.CategoriesRepo, repo *repositories.TransactionsRepo, tDTO TransactionDTO,
+	strategy ImportStrategy, counters *ImportCountersDTO) (int, error) {
+	t, err := tDTO.NewTransaction(repo.Namespace, repo.Kind)
+	if err != nil {
+		return -1, err
+	}
+	if err = CheckTransactionFields(repo, &t); err != nil {
+		return -1, err
+	}
+
+	for _, c := range t.Categories {
+		if _, err = csRepo.CategoryDAO(c.Label); err != nil {
+			return -1, err
+		}
+	}
+
+	if tDTO.ID == "" {
+		tDAO := newTransactionDAO(t)
+		if tDAO.ID, err = transactionsNextAvailableID(repo); err != nil {
+			return -1, err
+		}
+		if err = repo.AddTransaction(tDAO); err != nil {
+			return -1, err
+		}
+		counters.Created++
+		return tDAO.ID, updateRefToTransactions(repo)
+	}
+	if t.ID, err = strconv.Atoi(tDTO.ID); err != nil {
+		return -1, fmt.Errorf("invalid transaction ID %q", tDTO.ID)
+	}
+
+	ts, err := GetAllTransactionsByRepo(repo, false)
+	if err != nil {
+		return -1, err
+	}
+	idExists := false
+	for _, et := range *ts {
+		if et.ID == t.ID {
+			idExists = true
+			break
+		}
+	}
+
+	if !idExists {
+		if err = repo.AddTransaction(newTransactionDAO(t)); err != nil {
+			return -1, err
+		}
+		if err = updateRefToTransactions(repo); err != nil {
+			return -1, err
+		}
+		counters.Created++
+		return t.ID, nil
+	}
+
+	id := t.ID
+	switch strategy {
+	case OverwriteImportStrategy:
+		if err = repo.UpdateTransaction(newTransactionDAO(t)); err != nil {
+			return -1, err
+		}
+		counters.Updated++
+	case RenumberImportStrategy:
+		tDAO := newTransactionDAO(t)
+		if tDAO.ID, err = transactionsNextAvailableID(repo); err != nil {
+			return -1, err
+		}
+		if err = repo.AddTransaction(tDAO); err != nil {
+			return -1, err
+		}
+		id = tDAO.ID
+		counters.Renumbered++
+	default:
+		counters.Skipped++
+		return -1, nil
+	}
+	return id, updateRefToTransactions(repo)
+}
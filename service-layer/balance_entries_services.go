@@ -0,0 +1,332 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+type BalanceEntryDTO struct {
+	ID         string   `json:"id,omitempty"`
+	Entity     string   `json:"entity,omitempty"`
+	EntityKind string   `json:"entity_type,omitempty"`
+	Type       string   `json:"type"`
+	Date       string   `json:"date"`
+	Amount     float32  `json:"amount"`
+	Balance    *float32 `json:"balance,omitempty"`
+	Note       string   `json:"note,omitempty"`
+}
+
+type BalanceEntriesDTO []BalanceEntryDTO
+
+func BalanceEntryTypeIsValid(beType string) bool {
+	for _, t := range models.BalanceEntriesTypes {
+		if t == beType {
+			return true
+		}
+	}
+	return false
+}
+
+func newBalanceEntry(beDAO repositories.BalanceEntryDAO) models.BalanceEntry {
+	return models.BalanceEntry{
+		ID:        beDAO.ID,
+		Entity:    beDAO.Entity,
+		Kind:      beDAO.Kind,
+		Type:      beDAO.Type,
+		EntryDate: beDAO.EntryDate,
+		Amount:    beDAO.Amount,
+		Note:      beDAO.Note,
+	}
+}
+
+func newBalanceEntryDAO(be models.BalanceEntry) repositories.BalanceEntryDAO {
+	return repositories.BalanceEntryDAO{
+		ID:        be.ID,
+		Entity:    be.Entity,
+		Kind:      be.Kind,
+		Type:      be.Type,
+		EntryDate: be.EntryDate,
+		Amount:    be.Amount,
+		Note:      be.Note,
+	}
+}
+
+func NewBalanceEntryDTO(be models.BalanceEntry) BalanceEntryDTO {
+	return BalanceEntryDTO{
+		ID:     strconv.Itoa(be.ID),
+		Type:   be.Type,
+		Date:   be.EntryDate.Format(utils.DateFormat),
+		Amount: be.Amount,
+		Note:   be.Note,
+	}
+}
+
+func NewBalanceEntriesDTO(bes models.BalanceEntries) BalanceEntriesDTO {
+	besDTO := BalanceEntriesDTO{}
+	for _, be := range bes {
+		besDTO = append(besDTO, NewBalanceEntryDTO(be))
+	}
+	return besDTO
+}
+
+// GetBalanceEntries gets the balance entries of the entity.
+func GetBalanceEntries(repo *repositories.BalanceEntriesRepo, entity, kind string) (models.BalanceEntries, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("balance entries repo wasn't initialized")
+	}
+	besDAO, err := repo.GetBalanceEntries(entity, kind)
+	if err != nil {
+		return nil, err
+	}
+	bes := models.BalanceEntries{}
+	for _, beDAO := range besDAO {
+		bes = append(bes, newBalanceEntry(beDAO))
+	}
+	sort.SliceStable(bes, func(i, j int) bool {
+		if !bes[i].EntryDate.Equal(bes[j].EntryDate) {
+			return bes[i].EntryDate.Before(bes[j].EntryDate)
+		}
+		if bes[i].Type != bes[j].Type {
+			return bes[i].Type == models.OpeningBalanceEntry
+		}
+		return bes[i].ID < bes[j].ID
+	})
+	return bes, nil
+}
+
+func getEntityBalanceEntries(ns *repositories.Namespace, entity, kind string) (models.BalanceEntries, error) {
+	repo, err := ns.GetBalanceEntriesRepo()
+	if err != nil {
+		return nil, err
+	}
+	return GetBalanceEntries(repo, entity, kind)
+}
+
+func balanceEntriesSum(bes models.BalanceEntries, until time.Time) float32 {
+	sum := float32(0.0)
+	for _, be := range bes {
+		if until.IsZero() || !be.EntryDate.After(until) {
+			sum += be.Amount
+		}
+	}
+	return sum
+}
+
+func balanceAt(tsRepo *repositories.TransactionsRepo, bes models.BalanceEntries, date time.Time) (float32, error) {
+	ts, err := GetAllTransactionsByRepo(tsRepo, false)
+	if err != nil {
+		return 0.0, err
+	}
+	balance := balanceEntriesSum(bes, date)
+	for _, t := range *ts {
+		if !t.TransactionDate.After(date) {
+			balance += balanceDelta(tsRepo.Kind, t)
+		}
+	}
+	return balance, nil
+}
+
+// AddBalanceEntry adds an opening balance or an adjustment to the entity.
+func AddBalanceEntry(repo *repositories.BalanceEntriesRepo, tsesRepo *repositories.TransactionsEntitiesRepo,
+	tsRepo *repositories.TransactionsRepo, beDTO BalanceEntryDTO) (models.BalanceEntry, error) {
+	if repo == nil {
+		return models.BalanceEntry{}, fmt.Errorf("balance entries repo wasn't initialized")
+	}
+	if tsRepo == nil {
+		return models.BalanceEntry{}, fmt.Errorf("transactions repo wasn't initialized")
+	}
+	if !BalanceEntryTypeIsValid(beDTO.Type) {
+		return models.BalanceEntry{}, fmt.Errorf("the value %q for type is not valid", beDTO.Type)
+	}
+	be := models.BalanceEntry{
+		Entity: tsRepo.Entity,
+		Kind:   tsRepo.Kind,
+		Type:   beDTO.Type,
+		Amount: beDTO.Amount,
+		Note:   strings.Trim(beDTO.Note, " "),
+	}
+	if strings.ContainsAny(be.Note, "\r\n") {
+		return be, fmt.Errorf("the note must have one line only")
+	}
+
+	ts, err := GetAllTransactionsByRepo(tsRepo, false)
+	if err != nil {
+		return be, err
+	}
+	firstDate := time.Time{}
+	for _, t := range *ts {
+		if firstDate.IsZero() || t.TransactionDate.Before(firstDate) {
+			firstDate = t.TransactionDate
+		}
+	}
+
+	switch {
+	case beDTO.Date != "":
+		if be.EntryDate, err = time.Parse(utils.DateFormat, beDTO.Date); err != nil {
+			return be, fmt.Errorf("the date %q is not valid (%s)", beDTO.Date, utils.DateFormat)
+		}
+	case be.Type == models.OpeningBalanceEntry && !firstDate.IsZero():
+		be.EntryDate = firstDate
+	default:
+		now := time.Now().UTC()
+		be.EntryDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	bes, err := GetBalanceEntries(repo, tsRepo.Entity, tsRepo.Kind)
+	if err != nil {
+		return be, err
+	}
+	switch be.Type {
+	case models.OpeningBalanceEntry:
+		if beDTO.Balance != nil {
+			return be, fmt.Errorf("the opening balance is given by its amount")
+		}
+		for _, other := range bes {
+			if other.Type == models.OpeningBalanceEntry {
+				return be, fmt.Errorf("the entity already has the opening balance %d", other.ID)
+			}
+			if other.EntryDate.Before(be.EntryDate) {
+				return be, fmt.Errorf("the opening balance can't be after the adjustment %d", other.ID)
+			}
+		}
+		if !firstDate.IsZero() && firstDate.Before(be.EntryDate) {
+			return be, fmt.Errorf("the opening balance can't be after the first transaction, on %s",
+				firstDate.Format(utils.DateFormat))
+		}
+	case models.AdjustmentBalanceEntry:
+		if beDTO.Balance != nil {
+			balance, err := balanceAt(tsRepo, bes, be.EntryDate)
+			if err != nil {
+				return be, err
+			}
+			be.Amount = *beDTO.Balance - balance
+		}
+		be.Amount = roundAmount(be.Amount)
+		if be.Amount == 0 {
+			return be, fmt.Errorf("the adjustment doesn't change the balance")
+		}
+	}
+
+	all, err := repo.GetAllBalanceEntries()
+	if err != nil {
+		return be, err
+	}
+	be.ID = 1
+	for _, other := range all {
+		if other.ID >= be.ID {
+			be.ID = other.ID + 1
+		}
+	}
+	if err = repo.AddBalanceEntry(newBalanceEntryDAO(be)); err != nil {
+		return be, err
+	}
+	return be, updateBalance(tsesRepo, tsRepo)
+}
+
+// DeleteBalanceEntry deletes the balance entry of the entity.
+func DeleteBalanceEntry(repo *repositories.BalanceEntriesRepo, tsesRepo *repositories.TransactionsEntitiesRepo,
+	tsRepo *repositories.TransactionsRepo, id int) error {
+	if repo == nil {
+		return fmt.Errorf("balance entries repo wasn't initialized")
+	}
+	if tsRepo == nil {
+		return fmt.Errorf("transactions repo wasn't initialized")
+	}
+	bes, err := GetBalanceEntries(repo, tsRepo.Entity, tsRepo.Kind)
+	if err != nil {
+		return err
+	}
+	for _, be := range bes {
+		if be.ID == id {
+			if err = repo.DeleteBalanceEntry(id); err != nil {
+				return err
+			}
+			return updateBalance(tsesRepo, tsRepo)
+		}
+	}
+	return fmt.Errorf("balance entry %d not found", id)
+}
+
+func exportBalanceEntries(ns *repositories.Namespace) (BalanceEntriesDTO, error) {
+	repo, err := ns.GetBalanceEntriesRepo()
+	if err != nil {
+		return nil, err
+	}
+	besDAO, err := repo.GetAllBalanceEntries()
+	if err != nil {
+		return nil, err
+	}
+	var besDTO BalanceEntriesDTO
+	for _, beDAO := range besDAO {
+		beDTO := NewBalanceEntryDTO(newBalanceEntry(beDAO))
+		beDTO.Entity, beDTO.EntityKind = beDAO.Entity, beDAO.Kind
+		besDTO = append(besDTO, beDTO)
+	}
+	return besDTO, nil
+}
+
+func importBalanceEntry(repo *repositories.BalanceEntriesRepo, beDTO BalanceEntryDTO, strategy ImportStrategy,
+	counters *ImportCountersDTO) error {
+	if !BalanceEntryTypeIsValid(beDTO.Type) {
+		return fmt.Errorf("the value %q for type of the balance entry %s is not valid", beDTO.Type, beDTO.ID)
+	}
+	entryDate, err := time.Parse(utils.DateFormat, beDTO.Date)
+	if err != nil {
+		return fmt.Errorf("the date %q of the balance entry %s is not valid", beDTO.Date, beDTO.ID)
+	}
+	be := models.BalanceEntry{
+		Entity:    beDTO.Entity,
+		Kind:      beDTO.EntityKind,
+		Type:      beDTO.Type,
+		EntryDate: entryDate,
+		Amount:    beDTO.Amount,
+		Note:      beDTO.Note,
+	}
+
+	bes, err := GetBalanceEntries(repo, be.Entity, be.Kind)
+	if err != nil {
+		return err
+	}
+	for _, other := range bes {
+		if other.Type == be.Type && other.EntryDate.Equal(be.EntryDate) && other.Amount == be.Amount &&
+			other.Note == be.Note {
+			counters.Skipped++
+			return nil
+		}
+	}
+	for _, other := range bes {
+		if be.Type != models.OpeningBalanceEntry || other.Type != models.OpeningBalanceEntry {
+			continue
+		}
+		if strategy != OverwriteImportStrategy {
+			counters.Skipped++
+			return nil
+		}
+		if err = repo.DeleteBalanceEntry(other.ID); err != nil {
+			return err
+		}
+		be.ID = other.ID
+		counters.Updated++
+		return repo.AddBalanceEntry(newBalanceEntryDAO(be))
+	}
+
+	all, err := repo.GetAllBalanceEntries()
+	if err != nil {
+		return err
+	}
+	be.ID = 1
+	for _, other := range all {
+		if other.ID >= be.ID {
+			be.ID = other.ID + 1
+		}
+	}
+	counters.Created++
+	return repo.AddBalanceEntry(newBalanceEntryDAO(be))
+}
@@ -0,0 +1,301 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+const (
+	DayStep   Step = "day"
+	MonthStep Step = "month"
+
+	MaxBalancePoints int = 1000
+)
+
+// Step is the distance between the points of a balance history.
+type Step string
+
+var (
+	Steps = []Step{
+		DayStep, MonthStep,
+	}
+)
+
+type BalancePointDTO struct {
+	Date    string  `json:"date"`
+	Balance float32 `json:"balance"`
+}
+
+type BalanceHistoryDTO struct {
+	Entity         string            `json:"entity,omitempty"`
+	Kind           string            `json:"type,omitempty"`
+	From           string            `json:"from"`
+	To             string            `json:"to"`
+	Step           Step              `json:"step"`
+	OpeningBalance float32           `json:"opening_balance"`
+	Points         []BalancePointDTO `json:"points"`
+	BalanceEntries BalanceEntriesDTO `json:"balance_entries,omitempty"`
+}
+
+func StepIsValid(step string) bool {
+	for _, s := range Steps {
+		if string(s) == step {
+			return true
+		}
+	}
+	return false
+}
+
+func GetTransactionsEntityByID(repo *repositories.TransactionsEntitiesRepo, id int) (models.TransactionsEntity, error) {
+	tses, err := GetAllTransactionsEntities(repo, models.RefToTransactionsEntities)
+	if err != nil {
+		return models.TransactionsEntity{}, err
+	}
+	for _, tse := range *tses {
+		if tse.ID == id {
+			return tse, nil
+		}
+	}
+	return models.TransactionsEntity{}, fmt.Errorf("entity %d not found", id)
+}
+
+// GetTransactionsEntityOfRepo gets the entity of the repository.
+func GetTransactionsEntityOfRepo(tsesRepo *repositories.TransactionsEntitiesRepo,
+	tsRepo *repositories.TransactionsRepo) (models.TransactionsEntity, error) {
+	if tsesRepo == nil {
+		return models.TransactionsEntity{}, fmt.Errorf("transactions entities repo wasn't initialized")
+	}
+	if tsRepo == nil {
+		return models.TransactionsEntity{}, fmt.Errorf("transactions repo wasn't initialized")
+	}
+	tseDAO, err := tsesRepo.GetTransactionsEntity(tsRepo.Entity, tsRepo.Kind)
+	if err != nil {
+		return models.TransactionsEntity{}, err
+	}
+	return newTransactionsEntity(tseDAO), nil
+}
+
+func sortedByDate(ts models.Transactions) models.Transactions {
+	sorted := make(models.Transactions, len(ts))
+	copy(sorted, ts)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].TransactionDate.Equal(sorted[j].TransactionDate) {
+			return sorted[i].ID < sorted[j].ID
+		}
+		return sorted[i].TransactionDate.Before(sorted[j].TransactionDate)
+	})
+	return sorted
+}
+
+func getOpeningBalance(tse models.TransactionsEntity, ts models.Transactions, bes models.BalanceEntries) float32 {
+	opening := tse.Balance - balanceEntriesSum(bes, time.Time{})
+	for _, t := range ts {
+		opening -= balanceDelta(tse.Kind, t)
+	}
+	return opening
+}
+
+// SetRunningBalances sets the balance after each transaction.
+func SetRunningBalances(ns *repositories.Namespace, tse models.TransactionsEntity, ts models.Transactions, tsDTO *TransactionsDTO) error {
+	if len(ts) != len(tsDTO.TransactionsDTO) {
+		return fmt.Errorf("the transactions don't match their DTOs")
+	}
+	bes, err := getEntityBalanceEntries(ns, tse.Entity, tse.Kind)
+	if err != nil {
+		return err
+	}
+	idxs := make([]int, len(ts))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	sort.SliceStable(idxs, func(i, j int) bool {
+		ti, tj := ts[idxs[i]], ts[idxs[j]]
+		if ti.TransactionDate.Equal(tj.TransactionDate) {
+			return ti.ID < tj.ID
+		}
+		return ti.TransactionDate.Before(tj.TransactionDate)
+	})
+
+	balance := getOpeningBalance(tse, ts, bes)
+	next := 0
+	for _, idx := range idxs {
+		for ; next < len(bes) && !bes[next].EntryDate.After(ts[idx].TransactionDate); next++ {
+			balance += bes[next].Amount
+		}
+		balance += balanceDelta(tse.Kind, ts[idx])
+		runningBalance := balance
+		tsDTO.TransactionsDTO[idx].RunningBalance = &runningBalance
+	}
+	return nil
+}
+
+func balancePointsDates(from, to time.Time, step Step) ([]time.Time, error) {
+	var dates []time.Time
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if step == DayStep {
+		for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+			if len(dates) == MaxBalancePoints {
+				return nil, fmt.Errorf("the dates span more than %d points a %s apart", MaxBalancePoints, step)
+			}
+			dates = append(dates, d)
+		}
+		return dates, nil
+	}
+	for m := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC); !m.After(to); m = m.AddDate(0, 1, 0) {
+		if len(dates) == MaxBalancePoints {
+			return nil, fmt.Errorf("the dates span more than %d points a %s apart", MaxBalancePoints, step)
+		}
+		d := m.AddDate(0, 1, -1)
+		if d.After(to) {
+			d = to
+		}
+		dates = append(dates, d)
+	}
+	return dates, nil
+}
+
+func newBalanceHistoryDTO(tses []models.TransactionsEntity, tss []models.Transactions, bess []models.BalanceEntries,
+	from, to time.Time, step Step, asNetWorth bool) (*BalanceHistoryDTO, error) {
+	if !StepIsValid(string(step)) {
+		return nil, fmt.Errorf("the value %q for step is not valid", step)
+	}
+
+	if from.IsZero() {
+		for i, ts := range tss {
+			for _, t := range ts {
+				if from.IsZero() || t.TransactionDate.Before(from) {
+					from = t.TransactionDate
+				}
+			}
+			for _, be := range bess[i] {
+				if from.IsZero() || be.EntryDate.Before(from) {
+					from = be.EntryDate
+				}
+			}
+		}
+	}
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	if from.IsZero() || from.After(to) {
+		from = to
+	}
+	dates, err := balancePointsDates(from, to, step)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &BalanceHistoryDTO{
+		From:   from.Format(utils.DateFormat),
+		To:     to.Format(utils.DateFormat),
+		Step:   step,
+		Points: []BalancePointDTO{},
+	}
+
+	value := func(entityKind string, balance float32) float32 {
+		if asNetWorth {
+			return netWorthBalance(entityKind, balance)
+		}
+		return balance
+	}
+
+	sortedTss := make([]models.Transactions, len(tss))
+	balances := make([]float32, len(tss))
+	nexts := make([]int, len(tss))
+	nextEntries := make([]int, len(tss))
+	for i, ts := range tss {
+		sortedTss[i] = sortedByDate(ts)
+		balances[i] = getOpeningBalance(tses[i], ts, bess[i])
+		history.OpeningBalance += value(tses[i].Kind, balances[i])
+		for _, be := range bess[i] {
+			if be.EntryDate.Before(from) || be.EntryDate.After(to) {
+				continue
+			}
+			beDTO := NewBalanceEntryDTO(be)
+			if asNetWorth {
+				beDTO.Entity, beDTO.EntityKind = be.Entity, be.Kind
+			}
+			history.BalanceEntries = append(history.BalanceEntries, beDTO)
+		}
+	}
+
+	for _, d := range dates {
+		point := BalancePointDTO{
+			Date: d.Format(utils.DateFormat),
+		}
+		for i := range sortedTss {
+			for ; nextEntries[i] < len(bess[i]) && !bess[i][nextEntries[i]].EntryDate.After(d); nextEntries[i]++ {
+				balances[i] += bess[i][nextEntries[i]].Amount
+			}
+			for ; nexts[i] < len(sortedTss[i]) && !sortedTss[i][nexts[i]].TransactionDate.After(d); nexts[i]++ {
+				balances[i] += balanceDelta(tses[i].Kind, sortedTss[i][nexts[i]])
+			}
+			point.Balance += value(tses[i].Kind, balances[i])
+		}
+		history.Points = append(history.Points, point)
+	}
+
+	return history, nil
+}
+
+func netWorthBalance(entityKind string, balance float32) float32 {
+	if models.AccountNatures[models.TransactionKind(entityKind)] == models.LiabilityAccountNature {
+		return -balance
+	}
+	return balance
+}
+
+// GetBalanceHistory gets the balance of the entity between the dates.
+func GetBalanceHistory(tse models.TransactionsEntity, tsRepo *repositories.TransactionsRepo, from, to time.Time,
+	step Step) (*BalanceHistoryDTO, error) {
+	ts, err := GetAllTransactionsByRepo(tsRepo, false)
+	if err != nil {
+		return nil, err
+	}
+	bes, err := getEntityBalanceEntries(tsRepo.Namespace, tse.Entity, tse.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := newBalanceHistoryDTO([]models.TransactionsEntity{tse}, []models.Transactions{*ts},
+		[]models.BalanceEntries{bes}, from, to, step, false)
+	if err != nil {
+		return nil, err
+	}
+	history.Entity = tse.Entity
+	history.Kind = tse.Kind
+	return history, nil
+}
+
+// GetNetWorthHistory gets the net worth between the dates.
+func GetNetWorthHistory(tsesRepo *repositories.TransactionsEntitiesRepo, tsRepos []*repositories.TransactionsRepo,
+	from, to time.Time, step Step) (*BalanceHistoryDTO, error) {
+	var tses []models.TransactionsEntity
+	var tss []models.Transactions
+	var bess []models.BalanceEntries
+	for _, tsRepo := range tsRepos {
+		tse, err := GetTransactionsEntityOfRepo(tsesRepo, tsRepo)
+		if err != nil {
+			return nil, err
+		}
+		ts, err := GetAllTransactionsByRepo(tsRepo, false)
+		if err != nil {
+			return nil, err
+		}
+		bes, err := getEntityBalanceEntries(tsesRepo.Namespace, tse.Entity, tse.Kind)
+		if err != nil {
+			return nil, err
+		}
+		tses = append(tses, tse)
+		tss = append(tss, *ts)
+		bess = append(bess, bes)
+	}
+
+	return newBalanceHistoryDTO(tses, tss, bess, from, to, step, true)
+}
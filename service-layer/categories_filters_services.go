@@ -0,0 +1,160 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	LabelCategoriesFilterOperator CategoriesFilterOperator = "label"
+	AndCategoriesFilterOperator   CategoriesFilterOperator = "and"
+	OrCategoriesFilterOperator    CategoriesFilterOperator = "or"
+	NotCategoriesFilterOperator   CategoriesFilterOperator = "not"
+
+	andCategoriesFilterToken   = ','
+	orCategoriesFilterToken    = '|'
+	notCategoriesFilterToken   = '!'
+	openCategoriesFilterToken  = '('
+	closeCategoriesFilterToken = ')'
+)
+
+// CategoriesFilterOperator is the operation of a node of a categories filter.
+type CategoriesFilterOperator string
+
+// CategoriesFilter is a boolean expression over the categories, e.g. "FUEL|TRIPS,!SALARY".
+type CategoriesFilter struct {
+	Operator CategoriesFilterOperator
+	Label    string
+	Operands []*CategoriesFilter
+}
+
+// categoriesFilterParser is a recursive descent parser of the categories filters:
+//
+//	and   = or { "," or }
+//	or    = not { "|" not }
+//	not   = "!" not | "(" and ")" | label
+type categoriesFilterParser struct {
+	expression []rune
+	pos        int
+}
+
+// ParseCategoriesFilter parses the expression of a categories filter.
+func ParseCategoriesFilter(expression string) (*CategoriesFilter, error) {
+	p := &categoriesFilterParser{expression: []rune(expression)}
+	f, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.expression) {
+		return nil, fmt.Errorf("the categories filter %q has an unexpected %q at position %d", expression,
+			p.expression[p.pos], p.pos+1)
+	}
+	return f, nil
+}
+
+func (p *categoriesFilterParser) skipSpaces() {
+	for p.pos < len(p.expression) && p.expression[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *categoriesFilterParser) next(token rune) bool {
+	p.skipSpaces()
+	if p.pos < len(p.expression) && p.expression[p.pos] == token {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *categoriesFilterParser) parseAnd() (*CategoriesFilter, error) {
+	return p.parseList(AndCategoriesFilterOperator, andCategoriesFilterToken, p.parseOr)
+}
+
+func (p *categoriesFilterParser) parseOr() (*CategoriesFilter, error) {
+	return p.parseList(OrCategoriesFilterOperator, orCategoriesFilterToken, p.parseNot)
+}
+
+func (p *categoriesFilterParser) parseList(operator CategoriesFilterOperator, separator rune,
+	parseOperand func() (*CategoriesFilter, error)) (*CategoriesFilter, error) {
+	f := &CategoriesFilter{Operator: operator}
+	for {
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		f.Operands = append(f.Operands, operand)
+		if !p.next(separator) {
+			break
+		}
+	}
+	if len(f.Operands) == 1 {
+		return f.Operands[0], nil
+	}
+	return f, nil
+}
+
+func (p *categoriesFilterParser) parseNot() (*CategoriesFilter, error) {
+	if p.next(notCategoriesFilterToken) {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &CategoriesFilter{Operator: NotCategoriesFilterOperator, Operands: []*CategoriesFilter{operand}}, nil
+	}
+	if p.next(openCategoriesFilterToken) {
+		f, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if !p.next(closeCategoriesFilterToken) {
+			return nil, fmt.Errorf("the categories filter %q is missing a %q", string(p.expression),
+				closeCategoriesFilterToken)
+		}
+		return f, nil
+	}
+
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.expression) && !strings.ContainsRune(",|!()", p.expression[p.pos]) {
+		p.pos++
+	}
+	label := strings.Trim(string(p.expression[start:p.pos]), " ")
+	if label == "" {
+		return nil, fmt.Errorf("the categories filter %q is missing a category at position %d",
+			string(p.expression), start+1)
+	}
+	return &CategoriesFilter{Operator: LabelCategoriesFilterOperator, Label: label}, nil
+}
+
+// Matches tells if the categories labels satisfy the filter.
+func (f *CategoriesFilter) Matches(labels []string) bool {
+	switch f.Operator {
+	case LabelCategoriesFilterOperator:
+		for _, l := range labels {
+			if strings.EqualFold(l, f.Label) {
+				return true
+			}
+		}
+		return false
+	case AndCategoriesFilterOperator:
+		for _, o := range f.Operands {
+			if !o.Matches(labels) {
+				return false
+			}
+		}
+		return true
+	case OrCategoriesFilterOperator:
+		for _, o := range f.Operands {
+			if o.Matches(labels) {
+				return true
+			}
+		}
+		return false
+	case NotCategoriesFilterOperator:
+		return !f.Operands[0].Matches(labels)
+	default:
+		return false
+	}
+}
@@ -109,6 +109,9 @@ func AddCategory(repo *repositories.CategoriesRepo, c models.Category) (int, err
 	if err != nil {
 		return -1, err
 	}
+	if err = auditCategoryChange(repo.Namespace, cDAO.ID); err != nil {
+		return -1, err
+	}
 	err = repo.AddCategory(cDAO)
 	if err != nil {
 		return -1, err
@@ -121,10 +124,14 @@ func UpdateCategory(repo *repositories.CategoriesRepo, t models.Category) error
 		return fmt.Errorf("categories repo wasn't initialized")
 	}
 	cDAO := newCategoryDAO(t)
+	if err := auditCategoryChange(repo.Namespace, cDAO.ID); err != nil {
+		return err
+	}
 	err := repo.UpdateCategory(cDAO)
 	if err != nil {
 		return err
 	}
+	invalidateSearchIndex(repo.Namespace)
 	return nil
 }
 
@@ -0,0 +1,544 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+const (
+	StatementIDFormat = "2006-01"
+
+	defaultMinimumPercentage  float32 = 5
+	defaultMinimumAmount      float32 = 25
+	statementAmountsTolerance         = 0.005
+)
+
+type CreditCardDTO struct {
+	Entity            string   `json:"entity,omitempty"`
+	EntityKind        string   `json:"entity_type,omitempty"`
+	ClosingDay        int      `json:"closing_day"`
+	DueDay            int      `json:"due_day"`
+	MinimumPercentage *float32 `json:"minimum_percentage,omitempty"`
+	MinimumAmount     *float32 `json:"minimum_amount,omitempty"`
+}
+
+type StatementPaymentDTO struct {
+	Entity        string  `json:"entity"`
+	EntityKind    string  `json:"entity_type"`
+	TransactionID string  `json:"transaction_id"`
+	Date          string  `json:"date,omitempty"`
+	Amount        float32 `json:"amount,omitempty"`
+	Linked        bool    `json:"linked"`
+}
+
+type StatementDTO struct {
+	ID              string                `json:"id"`
+	Start           string                `json:"start"`
+	ClosingDate     string                `json:"closing_date"`
+	DueDate         string                `json:"due_date"`
+	PreviousBalance float32               `json:"previous_balance"`
+	Charges         float32               `json:"charges"`
+	Credits         float32               `json:"credits"`
+	BalanceEntries  float32               `json:"balance_entries,omitempty"`
+	Balance         float32               `json:"balance"`
+	MinimumPayment  float32               `json:"minimum_payment"`
+	Paid            float32               `json:"paid"`
+	Status          string                `json:"status"`
+	Payments        []StatementPaymentDTO `json:"payments,omitempty"`
+	Transactions    []TransactionDTO      `json:"transactions,omitempty"`
+}
+
+type StatementsDTO []StatementDTO
+
+// StatementPaymentBackupDTO is a statement payment in the backups.
+type StatementPaymentBackupDTO struct {
+	CardEntity string `json:"card_entity"`
+	CardKind   string `json:"card_type"`
+	Statement  string `json:"statement"`
+	StatementPaymentDTO
+}
+
+func NewCreditCardDTO(cc models.CreditCard) CreditCardDTO {
+	return CreditCardDTO{
+		ClosingDay:        cc.ClosingDay,
+		DueDay:            cc.DueDay,
+		MinimumPercentage: &cc.MinimumPercentage,
+		MinimumAmount:     &cc.MinimumAmount,
+	}
+}
+
+// NewCreditCard checks the statement cycle of the entity.
+func (ccDTO CreditCardDTO) NewCreditCard(entity, kind string) (models.CreditCard, error) {
+	cc := models.CreditCard{
+		Entity:            entity,
+		Kind:              kind,
+		ClosingDay:        ccDTO.ClosingDay,
+		DueDay:            ccDTO.DueDay,
+		MinimumPercentage: defaultMinimumPercentage,
+		MinimumAmount:     defaultMinimumAmount,
+	}
+	if models.AccountNatures[models.TransactionKind(kind)] != models.LiabilityAccountNature {
+		return cc, fmt.Errorf("the entities of the kind %q aren't credit cards", kind)
+	}
+	if cc.ClosingDay < 1 || cc.ClosingDay > 31 {
+		return cc, fmt.Errorf("the closing_day %d isn't a day of the month", cc.ClosingDay)
+	}
+	if cc.DueDay < 1 || cc.DueDay > 31 {
+		return cc, fmt.Errorf("the due_day %d isn't a day of the month", cc.DueDay)
+	}
+	if ccDTO.MinimumPercentage != nil {
+		cc.MinimumPercentage = *ccDTO.MinimumPercentage
+	}
+	if ccDTO.MinimumAmount != nil {
+		cc.MinimumAmount = *ccDTO.MinimumAmount
+	}
+	if cc.MinimumPercentage < 0 || cc.MinimumPercentage > 100 {
+		return cc, fmt.Errorf("the minimum_percentage %.2f isn't between 0 and 100", cc.MinimumPercentage)
+	}
+	if cc.MinimumAmount < 0 {
+		return cc, fmt.Errorf("the minimum_amount %.2f is negative", cc.MinimumAmount)
+	}
+	return cc, nil
+}
+
+func newCreditCard(ccDAO repositories.CreditCardDAO) models.CreditCard {
+	return models.CreditCard{
+		Entity:            ccDAO.Entity,
+		Kind:              ccDAO.Kind,
+		ClosingDay:        ccDAO.ClosingDay,
+		DueDay:            ccDAO.DueDay,
+		MinimumPercentage: ccDAO.MinimumPercentage,
+		MinimumAmount:     ccDAO.MinimumAmount,
+	}
+}
+
+func newCreditCardDAO(cc models.CreditCard) repositories.CreditCardDAO {
+	return repositories.CreditCardDAO{
+		Entity:            cc.Entity,
+		Kind:              cc.Kind,
+		ClosingDay:        cc.ClosingDay,
+		DueDay:            cc.DueDay,
+		MinimumPercentage: cc.MinimumPercentage,
+		MinimumAmount:     cc.MinimumAmount,
+	}
+}
+
+func GetCreditCard(repo *repositories.CreditCardsRepo, entity, kind string) (models.CreditCard, error) {
+	if repo == nil {
+		return models.CreditCard{}, fmt.Errorf("credit cards repo wasn't initialized")
+	}
+	ccDAO, found, err := repo.GetCreditCard(entity, kind)
+	if err != nil {
+		return models.CreditCard{}, err
+	}
+	if !found {
+		return models.CreditCard{}, fmt.Errorf("the entity %q isn't a credit card", entity)
+	}
+	return newCreditCard(ccDAO), nil
+}
+
+func SetCreditCard(repo *repositories.CreditCardsRepo, cc models.CreditCard) error {
+	if repo == nil {
+		return fmt.Errorf("credit cards repo wasn't initialized")
+	}
+	return repo.SetCreditCard(newCreditCardDAO(cc))
+}
+
+// DeleteCreditCard drops the statement cycle of the entity.
+func DeleteCreditCard(repo *repositories.CreditCardsRepo, spRepo *repositories.StatementsPaymentsRepo,
+	cc models.CreditCard) error {
+	if repo == nil {
+		return fmt.Errorf("credit cards repo wasn't initialized")
+	}
+	if spRepo == nil {
+		return fmt.Errorf("statements payments repo wasn't initialized")
+	}
+	spsDAO, err := spRepo.GetStatementsPayments(cc.Entity, cc.Kind)
+	if err != nil {
+		return err
+	}
+	for _, spDAO := range spsDAO {
+		if err = spRepo.DeleteStatementPayment(spDAO.Entity, spDAO.Kind, spDAO.TransactionID); err != nil {
+			return err
+		}
+	}
+	return repo.DeleteCreditCard(cc.Entity, cc.Kind)
+}
+
+func dayOfMonth(year int, month time.Month, day int) time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func statementClosingDate(cc models.CreditCard, month time.Time) time.Time {
+	return dayOfMonth(month.Year(), month.Month(), cc.ClosingDay)
+}
+
+func statementDueDate(cc models.CreditCard, closingDate time.Time) time.Time {
+	due := dayOfMonth(closingDate.Year(), closingDate.Month(), cc.DueDay)
+	if !due.After(closingDate) {
+		next := closingDate.AddDate(0, 0, 1-closingDate.Day()).AddDate(0, 1, 0)
+		due = dayOfMonth(next.Year(), next.Month(), cc.DueDay)
+	}
+	return due
+}
+
+func statementMonth(cc models.CreditCard, date time.Time) time.Time {
+	month := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if date.After(statementClosingDate(cc, month)) {
+		month = month.AddDate(0, 1, 0)
+	}
+	return month
+}
+
+func minimumPayment(cc models.CreditCard, balance float32) float32 {
+	if balance <= 0 {
+		return 0
+	}
+	minimum := balance * cc.MinimumPercentage / 100
+	if minimum < cc.MinimumAmount {
+		minimum = cc.MinimumAmount
+	}
+	if minimum > balance {
+		minimum = balance
+	}
+	return roundAmount(minimum)
+}
+
+func ParseStatementID(id string) (time.Time, error) {
+	month, err := time.Parse(StatementIDFormat, id)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("the statement %q is not valid (%s)", id, StatementIDFormat)
+	}
+	return month, nil
+}
+
+// GetStatements gets the statements of the card, the latest first.
+func GetStatements(spRepo *repositories.StatementsPaymentsRepo, tsRepo *repositories.TransactionsRepo,
+	cc models.CreditCard, today time.Time) (StatementsDTO, error) {
+	if spRepo == nil {
+		return nil, fmt.Errorf("statements payments repo wasn't initialized")
+	}
+	if tsRepo == nil {
+		return nil, fmt.Errorf("transactions repo wasn't initialized")
+	}
+	ts, err := GetAllTransactionsByRepo(tsRepo, false)
+	if err != nil {
+		return nil, err
+	}
+	sorted := sortedByDate(*ts)
+	bes, err := getEntityBalanceEntries(spRepo.Namespace, tsRepo.Entity, tsRepo.Kind)
+	if err != nil {
+		return nil, err
+	}
+	spsDAO, err := spRepo.GetStatementsPayments(cc.Entity, cc.Kind)
+	if err != nil {
+		return nil, err
+	}
+	linked := make(map[string]bool)
+	for _, spDAO := range spsDAO {
+		linked[fmt.Sprintf("%s;%s;%d", spDAO.Entity, spDAO.Kind, spDAO.TransactionID)] = true
+	}
+
+	first, last := today, today
+	for _, t := range sorted {
+		if t.TransactionDate.Before(first) {
+			first = t.TransactionDate
+		}
+		if t.TransactionDate.After(last) {
+			last = t.TransactionDate
+		}
+	}
+	for _, be := range bes {
+		if be.EntryDate.Before(first) {
+			first = be.EntryDate
+		}
+	}
+
+	sDTOs := StatementsDTO{}
+	balance := float32(0.0)
+	next, nextEntry := 0, 0
+	for month := statementMonth(cc, first); !month.After(statementMonth(cc, last)); month = month.AddDate(0, 1, 0) {
+		closingDate := statementClosingDate(cc, month)
+		sDTO := StatementDTO{
+			ID:              month.Format(StatementIDFormat),
+			Start:           statementClosingDate(cc, month.AddDate(0, -1, 0)).AddDate(0, 0, 1).Format(utils.DateFormat),
+			ClosingDate:     closingDate.Format(utils.DateFormat),
+			DueDate:         statementDueDate(cc, closingDate).Format(utils.DateFormat),
+			PreviousBalance: roundAmount(balance),
+		}
+		for ; nextEntry < len(bes) && !bes[nextEntry].EntryDate.After(closingDate); nextEntry++ {
+			sDTO.BalanceEntries += bes[nextEntry].Amount
+		}
+		for ; next < len(sorted) && !sorted[next].TransactionDate.After(closingDate); next++ {
+			if delta := balanceDelta(tsRepo.Kind, sorted[next]); delta > 0 {
+				sDTO.Charges += delta
+			} else {
+				sDTO.Credits -= delta
+			}
+		}
+		balance += sDTO.BalanceEntries + sDTO.Charges - sDTO.Credits
+		sDTO.Charges, sDTO.Credits = roundAmount(sDTO.Charges), roundAmount(sDTO.Credits)
+		sDTO.BalanceEntries, sDTO.Balance = roundAmount(sDTO.BalanceEntries), roundAmount(balance)
+		sDTO.MinimumPayment = minimumPayment(cc, sDTO.Balance)
+		sDTOs = append(sDTOs, sDTO)
+	}
+
+	for i := range sDTOs {
+		closingDate, _ := time.Parse(utils.DateFormat, sDTOs[i].ClosingDate)
+		dueDate, _ := time.Parse(utils.DateFormat, sDTOs[i].DueDate)
+		for _, spDAO := range spsDAO {
+			if spDAO.Statement != sDTOs[i].ID {
+				continue
+			}
+			spDTO := StatementPaymentDTO{
+				Entity:        spDAO.Entity,
+				EntityKind:    spDAO.Kind,
+				TransactionID: strconv.Itoa(spDAO.TransactionID),
+				Linked:        true,
+			}
+			if pRepo, err := spRepo.Namespace.GetTransRepo(spDAO.Kind, spDAO.Entity); err == nil {
+				if t, err := GetTransactionByID(pRepo, spDAO.TransactionID); err == nil {
+					spDTO.Date, spDTO.Amount = t.TransactionDate.Format(utils.DateFormat), t.Amount
+				}
+			}
+			sDTOs[i].Paid += spDTO.Amount
+			sDTOs[i].Payments = append(sDTOs[i].Payments, spDTO)
+		}
+		for _, t := range sorted {
+			if !t.TransactionDate.After(closingDate) || t.TransactionDate.After(dueDate) ||
+				balanceDelta(tsRepo.Kind, t) >= 0 || linked[fmt.Sprintf("%s;%s;%d", cc.Entity, cc.Kind, t.ID)] {
+				continue
+			}
+			sDTOs[i].Paid += t.Amount
+			sDTOs[i].Payments = append(sDTOs[i].Payments, StatementPaymentDTO{
+				Entity:        cc.Entity,
+				EntityKind:    cc.Kind,
+				TransactionID: strconv.Itoa(t.ID),
+				Date:          t.TransactionDate.Format(utils.DateFormat),
+				Amount:        t.Amount,
+			})
+		}
+		sDTOs[i].Paid = roundAmount(sDTOs[i].Paid)
+
+		switch {
+		case !closingDate.Before(today):
+			sDTOs[i].Status = models.OpenStatementStatus
+		case sDTOs[i].Paid >= sDTOs[i].Balance-statementAmountsTolerance:
+			sDTOs[i].Status = models.PaidStatementStatus
+		case sDTOs[i].Paid >= sDTOs[i].MinimumPayment-statementAmountsTolerance:
+			sDTOs[i].Status = models.MinimumPaidStatementStatus
+		case today.After(dueDate):
+			sDTOs[i].Status = models.OverdueStatementStatus
+		default:
+			sDTOs[i].Status = models.UnpaidStatementStatus
+		}
+	}
+
+	sort.SliceStable(sDTOs, func(i, j int) bool {
+		return sDTOs[i].ID > sDTOs[j].ID
+	})
+	return sDTOs, nil
+}
+
+// GetStatement gets the statement of the card with the transactions of its cycle.
+func GetStatement(spRepo *repositories.StatementsPaymentsRepo, tsRepo *repositories.TransactionsRepo,
+	cc models.CreditCard, id string, today time.Time) (*StatementDTO, error) {
+	sDTOs, err := GetStatements(spRepo, tsRepo, cc, today)
+	if err != nil {
+		return nil, err
+	}
+	for _, sDTO := range sDTOs {
+		if sDTO.ID != id {
+			continue
+		}
+		start, _ := time.Parse(utils.DateFormat, sDTO.Start)
+		closingDate, _ := time.Parse(utils.DateFormat, sDTO.ClosingDate)
+		ts, err := GetAllTransactionsByRepo(tsRepo, false)
+		if err != nil {
+			return nil, err
+		}
+		sDTO.Transactions = []TransactionDTO{}
+		for _, t := range sortedByDate(*ts) {
+			if t.TransactionDate.Before(start) || t.TransactionDate.After(closingDate) {
+				continue
+			}
+			tDTO, err := newTransactionDTO(spRepo.Namespace, t)
+			if err != nil {
+				return nil, err
+			}
+			sDTO.Transactions = append(sDTO.Transactions, tDTO)
+		}
+		return &sDTO, nil
+	}
+	return nil, fmt.Errorf("statement %s not found", id)
+}
+
+// LinkStatementPayment links the transaction as a payment of the statement.
+func LinkStatementPayment(spRepo *repositories.StatementsPaymentsRepo, cc models.CreditCard, statementID string,
+	spDTO StatementPaymentDTO) (StatementPaymentDTO, error) {
+	if spRepo == nil {
+		return spDTO, fmt.Errorf("statements payments repo wasn't initialized")
+	}
+	if _, err := ParseStatementID(statementID); err != nil {
+		return spDTO, err
+	}
+	tID, err := strconv.Atoi(spDTO.TransactionID)
+	if err != nil {
+		return spDTO, fmt.Errorf("the transaction_id %q is not valid", spDTO.TransactionID)
+	}
+	tsRepo, err := spRepo.Namespace.GetTransRepo(spDTO.EntityKind, spDTO.Entity)
+	if err != nil {
+		return spDTO, err
+	}
+	t, err := GetTransactionByID(tsRepo, tID)
+	if err != nil {
+		return spDTO, err
+	}
+	if spDTO.Entity == cc.Entity && spDTO.EntityKind == cc.Kind {
+		if balanceDelta(cc.Kind, t) >= 0 {
+			return spDTO, fmt.Errorf("the transaction %d isn't a credit of the card", tID)
+		}
+	} else if t.Kind != models.DebitKindTransaction {
+		return spDTO, fmt.Errorf("the transaction %d isn't a debit", tID)
+	}
+
+	if err = spRepo.AddStatementPayment(repositories.StatementPaymentDAO{
+		CardEntity:    cc.Entity,
+		CardKind:      cc.Kind,
+		Statement:     statementID,
+		Entity:        spDTO.Entity,
+		Kind:          spDTO.EntityKind,
+		TransactionID: tID,
+	}); err != nil {
+		return spDTO, err
+	}
+	spDTO.Date, spDTO.Amount, spDTO.Linked = t.TransactionDate.Format(utils.DateFormat), t.Amount, true
+	return spDTO, nil
+}
+
+// UnlinkStatementPayment unlinks the transaction from the statement of the card.
+func UnlinkStatementPayment(spRepo *repositories.StatementsPaymentsRepo, cc models.CreditCard, statementID,
+	entity, kind string, tID int) error {
+	if spRepo == nil {
+		return fmt.Errorf("statements payments repo wasn't initialized")
+	}
+	spsDAO, err := spRepo.GetStatementsPayments(cc.Entity, cc.Kind)
+	if err != nil {
+		return err
+	}
+	for _, spDAO := range spsDAO {
+		if spDAO.Statement == statementID && spDAO.Entity == entity && spDAO.Kind == kind &&
+			spDAO.TransactionID == tID {
+			return spRepo.DeleteStatementPayment(entity, kind, tID)
+		}
+	}
+	return fmt.Errorf("the transaction %d doesn't pay the statement %s", tID, statementID)
+}
+
+func exportCreditCards(ns *repositories.Namespace) ([]CreditCardDTO, error) {
+	repo, err := ns.GetCreditCardsRepo()
+	if err != nil {
+		return nil, err
+	}
+	ccsDAO, err := repo.GetAllCreditCards()
+	if err != nil {
+		return nil, err
+	}
+	var ccsDTO []CreditCardDTO
+	for _, ccDAO := range ccsDAO {
+		ccDTO := NewCreditCardDTO(newCreditCard(ccDAO))
+		ccDTO.Entity, ccDTO.EntityKind = ccDAO.Entity, ccDAO.Kind
+		ccsDTO = append(ccsDTO, ccDTO)
+	}
+	return ccsDTO, nil
+}
+
+func importCreditCard(repo *repositories.CreditCardsRepo, ccDTO CreditCardDTO, strategy ImportStrategy,
+	counters *ImportCountersDTO) error {
+	cc, err := ccDTO.NewCreditCard(ccDTO.Entity, ccDTO.EntityKind)
+	if err != nil {
+		return err
+	}
+	_, found, err := repo.GetCreditCard(cc.Entity, cc.Kind)
+	if err != nil {
+		return err
+	}
+	if found && strategy != OverwriteImportStrategy {
+		counters.Skipped++
+		return nil
+	}
+	if err = SetCreditCard(repo, cc); err != nil {
+		return err
+	}
+	if found {
+		counters.Updated++
+	} else {
+		counters.Created++
+	}
+	return nil
+}
+
+func exportStatementsPayments(ns *repositories.Namespace) ([]StatementPaymentBackupDTO, error) {
+	repo, err := ns.GetStatementsPaymentsRepo()
+	if err != nil {
+		return nil, err
+	}
+	spsDAO, err := repo.GetAllStatementsPayments()
+	if err != nil {
+		return nil, err
+	}
+	var spsDTO []StatementPaymentBackupDTO
+	for _, spDAO := range spsDAO {
+		spsDTO = append(spsDTO, StatementPaymentBackupDTO{
+			CardEntity: spDAO.CardEntity,
+			CardKind:   spDAO.CardKind,
+			Statement:  spDAO.Statement,
+			StatementPaymentDTO: StatementPaymentDTO{
+				Entity:        spDAO.Entity,
+				EntityKind:    spDAO.Kind,
+				TransactionID: strconv.Itoa(spDAO.TransactionID),
+				Linked:        true,
+			},
+		})
+	}
+	return spsDTO, nil
+}
+
+func importStatementPayment(repo *repositories.StatementsPaymentsRepo, spDTO StatementPaymentBackupDTO, tID int,
+	counters *ImportCountersDTO) error {
+	if _, err := ParseStatementID(spDTO.Statement); err != nil {
+		return err
+	}
+	spsDAO, err := repo.GetAllStatementsPayments()
+	if err != nil {
+		return err
+	}
+	for _, spDAO := range spsDAO {
+		if spDAO.Entity == spDTO.Entity && spDAO.Kind == spDTO.EntityKind && spDAO.TransactionID == tID {
+			counters.Skipped++
+			return nil
+		}
+	}
+	if err = repo.AddStatementPayment(repositories.StatementPaymentDAO{
+		CardEntity:    spDTO.CardEntity,
+		CardKind:      spDTO.CardKind,
+		Statement:     spDTO.Statement,
+		Entity:        spDTO.Entity,
+		Kind:          spDTO.EntityKind,
+		TransactionID: tID,
+	}); err != nil {
+		return err
+	}
+	counters.Created++
+	return nil
+}
@@ -0,0 +1,442 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+const (
+	EqualFieldOperator          FieldOperator = "="
+	NotEqualFieldOperator       FieldOperator = "!="
+	GreaterFieldOperator        FieldOperator = ">"
+	GreaterOrEqualFieldOperator FieldOperator = ">="
+	LessFieldOperator           FieldOperator = "<"
+	LessOrEqualFieldOperator    FieldOperator = "<="
+)
+
+// FieldOperator compares the value of a custom field.
+type FieldOperator string
+
+var (
+	customFieldNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+	FieldOperators = []FieldOperator{
+		GreaterOrEqualFieldOperator, LessOrEqualFieldOperator, NotEqualFieldOperator, EqualFieldOperator,
+		GreaterFieldOperator, LessFieldOperator,
+	}
+)
+
+type CustomFieldDTO struct {
+	ID         string   `json:"id"`
+	Entity     string   `json:"entity,omitempty"`
+	EntityKind string   `json:"entity_type,omitempty"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Options    []string `json:"options,omitempty"`
+	Required   bool     `json:"required"`
+}
+
+type CustomFieldsDTO []CustomFieldDTO
+
+// FieldCondition is a condition over a custom field, e.g. "vat>=10".
+type FieldCondition struct {
+	Name     string
+	Operator FieldOperator
+	Value    string
+}
+
+func NewCustomFieldDTO(cf models.CustomField) CustomFieldDTO {
+	return CustomFieldDTO{
+		ID:         strconv.Itoa(cf.ID),
+		Entity:     cf.Entity,
+		EntityKind: cf.Kind,
+		Name:       cf.Name,
+		Type:       string(cf.Type),
+		Options:    cf.Options,
+		Required:   cf.Required,
+	}
+}
+
+func NewCustomFieldsDTO(cfs models.CustomFields) CustomFieldsDTO {
+	cfsDTO := CustomFieldsDTO{}
+	for _, cf := range cfs {
+		cfsDTO = append(cfsDTO, NewCustomFieldDTO(cf))
+	}
+	return cfsDTO
+}
+
+func newCustomField(cfDAO repositories.CustomFieldDAO) models.CustomField {
+	return models.CustomField{
+		ID:       cfDAO.ID,
+		Entity:   cfDAO.Entity,
+		Kind:     cfDAO.Kind,
+		Name:     cfDAO.Name,
+		Type:     models.CustomFieldType(cfDAO.Type),
+		Options:  cfDAO.Options,
+		Required: cfDAO.Required,
+	}
+}
+
+func newCustomFieldDAO(cf models.CustomField) repositories.CustomFieldDAO {
+	return repositories.CustomFieldDAO{
+		ID:       cf.ID,
+		Entity:   cf.Entity,
+		Kind:     cf.Kind,
+		Name:     cf.Name,
+		Type:     string(cf.Type),
+		Options:  cf.Options,
+		Required: cf.Required,
+	}
+}
+
+func CustomFieldTypeIsValid(t string) bool {
+	for _, cft := range models.CustomFieldTypes {
+		if string(cft) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// NewCustomField validates the DTO of a custom field.
+func (cfDTO CustomFieldDTO) NewCustomField(entity, kind string) (models.CustomField, error) {
+	cf := models.CustomField{
+		Entity:   entity,
+		Kind:     kind,
+		Name:     strings.Trim(cfDTO.Name, " "),
+		Type:     models.CustomFieldType(cfDTO.Type),
+		Options:  trimmedValues(cfDTO.Options),
+		Required: cfDTO.Required,
+	}
+	if !customFieldNameRegexp.MatchString(cf.Name) {
+		return cf, fmt.Errorf("the name %q is not valid because it must be lowercase letters, digits and '_', "+
+			"starting with a letter", cf.Name)
+	}
+	if !CustomFieldTypeIsValid(cfDTO.Type) {
+		return cf, fmt.Errorf("the value %q for type is not valid", cfDTO.Type)
+	}
+	if cf.Type == models.EnumCustomFieldType && len(cf.Options) == 0 {
+		return cf, fmt.Errorf("the field %q of type %s must have options", cf.Name, cf.Type)
+	}
+	if cf.Type != models.EnumCustomFieldType && len(cf.Options) != 0 {
+		return cf, fmt.Errorf("the field %q of type %s can't have options", cf.Name, cf.Type)
+	}
+	return cf, nil
+}
+
+// GetCustomFields gets the custom fields of the entity and kind.
+func GetCustomFields(repo *repositories.CustomFieldsRepo, entity, kind string) (models.CustomFields, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("custom fields repo wasn't initialized")
+	}
+	cfsDAO, err := repo.GetAllCustomFields()
+	if err != nil {
+		return nil, err
+	}
+	cfs := models.CustomFields{}
+	for _, cfDAO := range cfsDAO {
+		if cfDAO.Entity == entity && cfDAO.Kind == kind {
+			cfs = append(cfs, newCustomField(cfDAO))
+		}
+	}
+	return cfs, nil
+}
+
+func GetCustomField(repo *repositories.CustomFieldsRepo, entity, kind, name string) (models.CustomField, error) {
+	cfs, err := GetCustomFields(repo, entity, kind)
+	if err != nil {
+		return models.CustomField{}, err
+	}
+	for _, cf := range cfs {
+		if cf.Name == name {
+			return cf, nil
+		}
+	}
+	return models.CustomField{}, fmt.Errorf("custom field %q not found", name)
+}
+
+// AddCustomField adds the custom field.
+func AddCustomField(repo *repositories.CustomFieldsRepo, cf models.CustomField) (int, error) {
+	if repo == nil {
+		return -1, fmt.Errorf("custom fields repo wasn't initialized")
+	}
+	cfsDAO, err := repo.GetAllCustomFields()
+	if err != nil {
+		return -1, err
+	}
+	cf.ID = 1
+	for _, other := range cfsDAO {
+		if other.Entity == cf.Entity && other.Kind == cf.Kind && other.Name == cf.Name {
+			return -1, fmt.Errorf("the field %q already exists", cf.Name)
+		}
+		if other.ID >= cf.ID {
+			cf.ID = other.ID + 1
+		}
+	}
+	if err = repo.AddCustomField(newCustomFieldDAO(cf)); err != nil {
+		return -1, err
+	}
+	return cf.ID, nil
+}
+
+// DeleteCustomField deletes the custom field and its values.
+func DeleteCustomField(repo *repositories.CustomFieldsRepo, tsRepo *repositories.TransactionsRepo, name string) error {
+	if tsRepo == nil {
+		return fmt.Errorf("transactions repo wasn't initialized")
+	}
+	cf, err := GetCustomField(repo, tsRepo.Entity, tsRepo.Kind, name)
+	if err != nil {
+		return err
+	}
+	tfsRepo, err := repo.Namespace.GetTransFieldsRepo()
+	if err != nil {
+		return err
+	}
+	ts, err := GetAllTransactionsByRepo(tsRepo, false)
+	if err != nil {
+		return err
+	}
+	for _, t := range *ts {
+		for _, fv := range t.Fields {
+			if fv.Name != cf.Name {
+				continue
+			}
+			if err = auditTransactionChange(tsRepo, t.ID); err != nil {
+				return err
+			}
+		}
+	}
+	if err = repo.DeleteCustomField(cf.ID); err != nil {
+		return err
+	}
+	if err = tfsRepo.DeleteFieldValues(tsRepo.Entity, tsRepo.Kind, cf.Name); err != nil {
+		return err
+	}
+	return updateRefToTransactions(tsRepo)
+}
+
+func fieldValueString(name string, v any) (string, error) {
+	switch value := v.(type) {
+	case string:
+		return strings.Trim(value, " "), nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("the value of the field %q must be a string or a number", name)
+	}
+}
+
+func newFieldValue(cf models.CustomField, value string) (models.FieldValue, error) {
+	fv := models.FieldValue{
+		Name: cf.Name,
+		Type: cf.Type,
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return fv, fmt.Errorf("the value of the field %q must be a single line", cf.Name)
+	}
+	switch cf.Type {
+	case models.NumberCustomFieldType:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fv, fmt.Errorf("the value %q of the field %q is not a number", value, cf.Name)
+		}
+		fv.Value = strconv.FormatFloat(n, 'f', -1, 64)
+	case models.DateCustomFieldType:
+		d, err := time.Parse(utils.DateFormat, value)
+		if err != nil {
+			return fv, fmt.Errorf("the value %q of the field %q is not a date (%s)", value, cf.Name, utils.DateFormat)
+		}
+		fv.Value = d.Format(utils.DateFormat)
+	case models.EnumCustomFieldType:
+		for _, o := range cf.Options {
+			if strings.EqualFold(o, value) {
+				fv.Value = o
+				return fv, nil
+			}
+		}
+		return fv, fmt.Errorf("the value %q of the field %q is not one of %s", value, cf.Name,
+			strings.Join(cf.Options, ", "))
+	default:
+		fv.Value = value
+	}
+	return fv, nil
+}
+
+// CheckTransactionFields validates the custom fields values of the transaction.
+func CheckTransactionFields(tsRepo *repositories.TransactionsRepo, t *models.Transaction) error {
+	if tsRepo == nil {
+		return fmt.Errorf("transactions repo wasn't initialized")
+	}
+	repo, err := tsRepo.Namespace.GetCustomFieldsRepo()
+	if err != nil {
+		return err
+	}
+	cfs, err := GetCustomFields(repo, tsRepo.Entity, tsRepo.Kind)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]string)
+	for _, fv := range t.Fields {
+		values[fv.Name] = fv.Value
+	}
+	fields := models.FieldValues{}
+	for _, cf := range cfs {
+		value, found := values[cf.Name]
+		delete(values, cf.Name)
+		if !found || value == "" {
+			if cf.Required {
+				return fmt.Errorf("the field %q is required", cf.Name)
+			}
+			continue
+		}
+		fv, err := newFieldValue(cf, value)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, fv)
+	}
+	for name := range values {
+		return fmt.Errorf("the field %q doesn't exist in the entity %q", name, tsRepo.Entity)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+	t.Fields = fields
+	return nil
+}
+
+// NewFieldsDTO maps the names of the custom fields to their values.
+func NewFieldsDTO(fvs models.FieldValues) map[string]any {
+	if len(fvs) == 0 {
+		return nil
+	}
+	fieldsDTO := make(map[string]any)
+	for _, fv := range fvs {
+		fieldsDTO[fv.Name] = fv.Value
+		if fv.Type == models.NumberCustomFieldType {
+			if n, err := strconv.ParseFloat(fv.Value, 64); err == nil {
+				fieldsDTO[fv.Name] = n
+			}
+		}
+	}
+	return fieldsDTO
+}
+
+// ParseFieldCondition parses a condition over a custom field.
+func ParseFieldCondition(expression string) (FieldCondition, error) {
+	fc := FieldCondition{}
+	idx, operator := -1, FieldOperator("")
+	for _, o := range FieldOperators {
+		if i := strings.Index(expression, string(o)); i != -1 && (idx == -1 || i < idx) {
+			idx, operator = i, o
+		}
+	}
+	if idx == -1 {
+		return fc, fmt.Errorf("the field condition %q has no operator (=, !=, >, >=, <, <=)", expression)
+	}
+	fc.Name = strings.Trim(expression[:idx], " ")
+	fc.Operator = operator
+	fc.Value = strings.Trim(expression[idx+len(operator):], " ")
+	if !customFieldNameRegexp.MatchString(fc.Name) {
+		return fc, fmt.Errorf("the field condition %q has no valid field name", expression)
+	}
+	return fc, nil
+}
+
+// Matches tells if the transaction satisfies the condition.
+func (fc FieldCondition) Matches(t models.Transaction) bool {
+	for _, fv := range t.Fields {
+		if fv.Name != fc.Name {
+			continue
+		}
+		c := 0
+		switch fv.Type {
+		case models.NumberCustomFieldType:
+			a, errA := strconv.ParseFloat(fv.Value, 64)
+			b, errB := strconv.ParseFloat(fc.Value, 64)
+			if errA != nil || errB != nil {
+				return false
+			}
+			if a < b {
+				c = -1
+			} else if a > b {
+				c = 1
+			}
+		case models.DateCustomFieldType:
+			a, errA := time.Parse(utils.DateFormat, fv.Value)
+			b, errB := time.Parse(utils.DateFormat, fc.Value)
+			if errA != nil || errB != nil {
+				return false
+			}
+			c = a.Compare(b)
+		default:
+			c = strings.Compare(utils.FoldText(fv.Value), utils.FoldText(fc.Value))
+		}
+		switch fc.Operator {
+		case EqualFieldOperator:
+			return c == 0
+		case NotEqualFieldOperator:
+			return c != 0
+		case GreaterFieldOperator:
+			return c > 0
+		case GreaterOrEqualFieldOperator:
+			return c >= 0
+		case LessFieldOperator:
+			return c < 0
+		case LessOrEqualFieldOperator:
+			return c <= 0
+		}
+	}
+	return false
+}
+
+func importCustomField(repo *repositories.CustomFieldsRepo, cfDTO CustomFieldDTO, strategy ImportStrategy,
+	counters *ImportCountersDTO) error {
+	cf, err := cfDTO.NewCustomField(cfDTO.Entity, cfDTO.EntityKind)
+	if err != nil {
+		return err
+	}
+	existing, err := GetCustomField(repo, cf.Entity, cf.Kind, cf.Name)
+	if err != nil {
+		if _, err = AddCustomField(repo, cf); err != nil {
+			return err
+		}
+		counters.Created++
+		return nil
+	}
+	if strategy != OverwriteImportStrategy {
+		counters.Skipped++
+		return nil
+	}
+	cf.ID = existing.ID
+	if err = repo.UpdateCustomField(newCustomFieldDAO(cf)); err != nil {
+		return err
+	}
+	counters.Updated++
+	return nil
+}
+
+func exportCustomFields(ns *repositories.Namespace) (CustomFieldsDTO, error) {
+	repo, err := ns.GetCustomFieldsRepo()
+	if err != nil {
+		return nil, err
+	}
+	cfsDAO, err := repo.GetAllCustomFields()
+	if err != nil {
+		return nil, err
+	}
+	cfs := models.CustomFields{}
+	for _, cfDAO := range cfsDAO {
+		cfs = append(cfs, newCustomField(cfDAO))
+	}
+	return NewCustomFieldsDTO(cfs), nil
+}
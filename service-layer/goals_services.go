@@ -0,0 +1,381 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+const (
+	DefaultGoalProjectionMonths int = 3
+	MaxGoalProjectionMonths     int = 120
+
+	contributionMonthFormat string = "2006-01"
+)
+
+type GoalDTO struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Target     float32          `json:"target"`
+	StartDate  string           `json:"start_date,omitempty"`
+	TargetDate string           `json:"target_date"`
+	Type       string           `json:"type"`
+	Entity     string           `json:"entity,omitempty"`
+	EntityKind string           `json:"entity_type,omitempty"`
+	Label      string           `json:"label,omitempty"`
+	Progress   *GoalProgressDTO `json:"progress,omitempty"`
+}
+
+type GoalsDTO []GoalDTO
+
+type MonthContributionDTO struct {
+	Month  string  `json:"month"`
+	Amount float32 `json:"amount"`
+}
+
+type GoalProgressDTO struct {
+	Saved           float32                `json:"saved"`
+	Remaining       float32                `json:"remaining"`
+	Percentage      float32                `json:"percentage"`
+	Completed       bool                   `json:"completed"`
+	MonthsLeft      int                    `json:"months_left"`
+	RequiredMonthly float32                `json:"required_monthly"`
+	AverageMonthly  float32                `json:"average_monthly"`
+	Contributions   []MonthContributionDTO `json:"contributions"`
+	ProjectedDate   string                 `json:"projected_date,omitempty"`
+	OnTrack         bool                   `json:"on_track"`
+}
+
+func GoalTypeIsValid(gType string) bool {
+	for _, t := range models.GoalsTypes {
+		if t == gType {
+			return true
+		}
+	}
+	return false
+}
+
+func NewGoalDTO(g models.Goal) GoalDTO {
+	return GoalDTO{
+		ID:         strconv.Itoa(g.ID),
+		Name:       g.Name,
+		Target:     g.Target,
+		StartDate:  g.StartDate.Format(utils.DateFormat),
+		TargetDate: g.TargetDate.Format(utils.DateFormat),
+		Type:       g.Type,
+		Entity:     g.Entity,
+		EntityKind: g.Kind,
+		Label:      g.Label,
+	}
+}
+
+// NewGoal validates the DTO of a goal.
+func (gDTO GoalDTO) NewGoal(ns *repositories.Namespace, today time.Time) (models.Goal, error) {
+	g := models.Goal{
+		Name:   strings.Trim(gDTO.Name, " "),
+		Target: roundAmount(gDTO.Target),
+		Type:   gDTO.Type,
+	}
+	if g.Name == "" {
+		return g, fmt.Errorf("the name of the goal is mandatory")
+	}
+	if g.Target <= 0 {
+		return g, fmt.Errorf("the target %.2f is not valid because it must be positive", gDTO.Target)
+	}
+
+	var err error
+	g.StartDate = today
+	if startDate := strings.Trim(gDTO.StartDate, " "); startDate != "" {
+		if g.StartDate, err = time.Parse(utils.DateFormat, startDate); err != nil {
+			return g, fmt.Errorf("the start_date %q is not valid (%s)", gDTO.StartDate, utils.DateFormat)
+		}
+	}
+	if g.TargetDate, err = time.Parse(utils.DateFormat, strings.Trim(gDTO.TargetDate, " ")); err != nil {
+		return g, fmt.Errorf("the target_date %q is not valid (%s)", gDTO.TargetDate, utils.DateFormat)
+	}
+	if !g.TargetDate.After(g.StartDate) {
+		return g, fmt.Errorf("the target_date %s isn't after the start_date %s", g.TargetDate.Format(utils.DateFormat),
+			g.StartDate.Format(utils.DateFormat))
+	}
+
+	switch g.Type {
+	case models.EntityGoalType:
+		g.Entity, g.Kind = gDTO.Entity, gDTO.EntityKind
+		if _, err = ns.GetTransRepo(g.Kind, g.Entity); err != nil {
+			return g, err
+		}
+	case models.TagGoalType:
+		if g.Label, err = NormalizeTag(gDTO.Label); err != nil {
+			return g, err
+		}
+	case models.CategoryGoalType:
+		g.Label = strings.Trim(gDTO.Label, " ")
+		csRepo, err := ns.GetCategoriesRepo()
+		if err != nil {
+			return g, err
+		}
+		if _, err = csRepo.CategoryDAO(g.Label); err != nil {
+			return g, err
+		}
+	default:
+		return g, fmt.Errorf("the value %q for type is not valid", gDTO.Type)
+	}
+	return g, nil
+}
+
+func newGoal(gDAO repositories.GoalDAO) models.Goal {
+	return models.Goal{
+		ID:         gDAO.ID,
+		Name:       gDAO.Name,
+		Target:     gDAO.Target,
+		StartDate:  gDAO.StartDate,
+		TargetDate: gDAO.TargetDate,
+		Type:       gDAO.Type,
+		Entity:     gDAO.Entity,
+		Kind:       gDAO.Kind,
+		Label:      gDAO.Label,
+	}
+}
+
+func newGoalDAO(g models.Goal) repositories.GoalDAO {
+	return repositories.GoalDAO{
+		ID:         g.ID,
+		Name:       g.Name,
+		Target:     g.Target,
+		StartDate:  g.StartDate,
+		TargetDate: g.TargetDate,
+		Type:       g.Type,
+		Entity:     g.Entity,
+		Kind:       g.Kind,
+		Label:      g.Label,
+	}
+}
+
+// GetAllGoals gets the goals sorted by target date.
+func GetAllGoals(repo *repositories.GoalsRepo) (models.Goals, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("goals repo wasn't initialized")
+	}
+	gsDAO, err := repo.GetAllGoals()
+	if err != nil {
+		return nil, err
+	}
+	gs := models.Goals{}
+	for _, gDAO := range gsDAO {
+		gs = append(gs, newGoal(gDAO))
+	}
+	sort.SliceStable(gs, func(i, j int) bool {
+		if !gs[i].TargetDate.Equal(gs[j].TargetDate) {
+			return gs[i].TargetDate.Before(gs[j].TargetDate)
+		}
+		return gs[i].ID < gs[j].ID
+	})
+	return gs, nil
+}
+
+func GetGoalByID(repo *repositories.GoalsRepo, id int) (models.Goal, error) {
+	gs, err := GetAllGoals(repo)
+	if err != nil {
+		return models.Goal{}, err
+	}
+	for _, g := range gs {
+		if g.ID == id {
+			return g, nil
+		}
+	}
+	return models.Goal{}, fmt.Errorf("goal %d not found", id)
+}
+
+func AddGoal(repo *repositories.GoalsRepo, g models.Goal) (int, error) {
+	gs, err := GetAllGoals(repo)
+	if err != nil {
+		return -1, err
+	}
+	g.ID = 1
+	for _, other := range gs {
+		if other.ID >= g.ID {
+			g.ID = other.ID + 1
+		}
+	}
+	if err = repo.AddGoal(newGoalDAO(g)); err != nil {
+		return -1, err
+	}
+	return g.ID, nil
+}
+
+func UpdateGoal(repo *repositories.GoalsRepo, g models.Goal) error {
+	if repo == nil {
+		return fmt.Errorf("goals repo wasn't initialized")
+	}
+	return repo.UpdateGoal(newGoalDAO(g))
+}
+
+func DeleteGoal(repo *repositories.GoalsRepo, id int) error {
+	if repo == nil {
+		return fmt.Errorf("goals repo wasn't initialized")
+	}
+	return repo.DeleteGoal(id)
+}
+
+func goalContribution(g models.Goal, t models.Transaction) float32 {
+	contribution := float32(0.0)
+	switch g.Type {
+	case models.TagGoalType:
+		for _, tag := range t.Tags {
+			if tag == g.Label {
+				contribution -= signedAmount(t)
+			}
+		}
+	case models.CategoryGoalType:
+		for _, ca := range categoriesAmounts(t) {
+			if ca.label == g.Label {
+				contribution -= ca.amount
+			}
+		}
+	}
+	return contribution
+}
+
+func goalSaved(g models.Goal, etss []EntityTransactions, bes models.BalanceEntries, date time.Time) float32 {
+	if date.Before(g.StartDate) {
+		return 0
+	}
+	if g.Type == models.EntityGoalType {
+		return goalEntityBalance(g, etss, bes, date) - goalEntityBalance(g, etss, bes, g.StartDate.AddDate(0, 0, -1))
+	}
+	saved := float32(0.0)
+	for _, ets := range etss {
+		for _, t := range ets.Transactions {
+			if !t.TransactionDate.After(date) && !t.TransactionDate.Before(g.StartDate) {
+				saved += goalContribution(g, t)
+			}
+		}
+	}
+	return saved
+}
+
+func goalEntityBalance(g models.Goal, etss []EntityTransactions, bes models.BalanceEntries, date time.Time) float32 {
+	balance := balanceEntriesSum(bes, date)
+	for _, ets := range etss {
+		if ets.Entity != g.Entity || ets.Kind != g.Kind {
+			continue
+		}
+		for _, t := range ets.Transactions {
+			if !t.TransactionDate.After(date) {
+				balance += balanceDelta(ets.Kind, t)
+			}
+		}
+	}
+	return netWorthBalance(g.Kind, balance)
+}
+
+// GetGoalProgress gets the progress of the goal at the date.
+func GetGoalProgress(ns *repositories.Namespace, g models.Goal, etss []EntityTransactions, today time.Time, months int) (*GoalProgressDTO, error) {
+	var bes models.BalanceEntries
+	if g.Type == models.EntityGoalType {
+		var err error
+		if bes, err = getEntityBalanceEntries(ns, g.Entity, g.Kind); err != nil {
+			return nil, err
+		}
+	}
+
+	p := &GoalProgressDTO{
+		Saved:         roundAmount(goalSaved(g, etss, bes, today)),
+		Contributions: []MonthContributionDTO{},
+	}
+	p.Remaining = roundAmount(g.Target - p.Saved)
+	if p.Remaining < 0 {
+		p.Remaining = 0
+	}
+	p.Percentage = roundAmount(p.Saved / g.Target * 100)
+	p.Completed = p.Remaining == 0
+
+	thisMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if !g.TargetDate.Before(thisMonth) {
+		p.MonthsLeft = (g.TargetDate.Year()-today.Year())*12 + int(g.TargetDate.Month()-today.Month()) + 1
+		p.RequiredMonthly = roundAmount(p.Remaining / float32(p.MonthsLeft))
+	}
+
+	total := float32(0.0)
+	for m := months; m >= 1; m-- {
+		start := thisMonth.AddDate(0, -m, 0)
+		contribution := goalSaved(g, etss, bes, start.AddDate(0, 1, -1)) - goalSaved(g, etss, bes, start.AddDate(0, 0, -1))
+		total += contribution
+		p.Contributions = append(p.Contributions, MonthContributionDTO{
+			Month:  start.Format(contributionMonthFormat),
+			Amount: roundAmount(contribution),
+		})
+	}
+	p.AverageMonthly = roundAmount(total / float32(months))
+
+	switch {
+	case p.Completed:
+		p.OnTrack = true
+	case p.AverageMonthly > 0:
+		monthsToGo := int(math.Ceil(float64(p.Remaining / p.AverageMonthly)))
+		month := thisMonth.AddDate(0, monthsToGo-1, 0)
+		projected := dayOfMonth(month.Year(), month.Month(), 31)
+		p.ProjectedDate = projected.Format(utils.DateFormat)
+		p.OnTrack = !projected.After(dayOfMonth(g.TargetDate.Year(), g.TargetDate.Month(), 31))
+	}
+	return p, nil
+}
+
+func exportGoals(ns *repositories.Namespace) (GoalsDTO, error) {
+	repo, err := ns.GetGoalsRepo()
+	if err != nil {
+		return nil, err
+	}
+	gs, err := GetAllGoals(repo)
+	if err != nil {
+		return nil, err
+	}
+	var gsDTO GoalsDTO
+	for _, g := range gs {
+		gsDTO = append(gsDTO, NewGoalDTO(g))
+	}
+	return gsDTO, nil
+}
+
+func importGoal(repo *repositories.GoalsRepo, gDTO GoalDTO, strategy ImportStrategy,
+	counters *ImportCountersDTO) error {
+	gID, err := strconv.Atoi(gDTO.ID)
+	if err != nil {
+		return fmt.Errorf("invalid goal ID %q", gDTO.ID)
+	}
+	g, err := gDTO.NewGoal(repo.Namespace, time.Now().UTC().Truncate(24*time.Hour))
+	if err != nil {
+		return err
+	}
+	g.ID = gID
+
+	if _, err = GetGoalByID(repo, gID); err != nil {
+		if err = repo.AddGoal(newGoalDAO(g)); err != nil {
+			return err
+		}
+		counters.Created++
+		return nil
+	}
+	switch strategy {
+	case OverwriteImportStrategy:
+		if err = UpdateGoal(repo, g); err != nil {
+			return err
+		}
+		counters.Updated++
+	case RenumberImportStrategy:
+		if _, err = AddGoal(repo, g); err != nil {
+			return err
+		}
+		counters.Renumbered++
+	default:
+		counters.Skipped++
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+func testDate(t *testing.T, date string) time.Time {
+	d, err := time.Parse(utils.DateFormat, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestGetGoalProgressOfEntitySinceStartDate(t *testing.T) {
+	ns := addTestUser(t, "goal-saver")
+	backup := BackupDTO{
+		Version: BackupVersion,
+		BalanceEntries: BalanceEntriesDTO{
+			{Entity: "test", EntityKind: "debit_bank_account", Type: models.OpeningBalanceEntry,
+				Date: "01/01/2024", Amount: 500},
+		},
+	}
+	if _, err := ImportBackup(ns, backup, SkipImportStrategy); err != nil {
+		t.Fatal(err)
+	}
+
+	transaction := func(date, kind string, amount float32) models.Transaction {
+		return models.Transaction{TransactionDate: testDate(t, date), Kind: kind, Amount: amount}
+	}
+	etss := []EntityTransactions{
+		{Entity: "test", Kind: "debit_bank_account", Transactions: models.Transactions{
+			transaction("10/01/2024", models.CreditKindTransaction, 1000),
+			transaction("20/02/2024", models.DebitKindTransaction, 300),
+			transaction("10/03/2024", models.CreditKindTransaction, 200),
+			transaction("10/04/2024", models.CreditKindTransaction, 200),
+			transaction("10/05/2024", models.CreditKindTransaction, 200),
+			transaction("05/06/2024", models.DebitKindTransaction, 50),
+		}},
+		{Entity: "test2", Kind: "debit_credit_bank_account", Transactions: models.Transactions{
+			transaction("10/03/2024", models.DebitKindTransaction, 70),
+		}},
+	}
+	g := models.Goal{
+		Name:       "Emergency fund",
+		Target:     1000,
+		StartDate:  testDate(t, "01/03/2024"),
+		TargetDate: testDate(t, "31/12/2024"),
+		Type:       models.EntityGoalType,
+		Entity:     "test",
+		Kind:       "debit_bank_account",
+	}
+
+	p, err := GetGoalProgress(ns, g, etss, testDate(t, "15/06/2024"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Saved != 550 {
+		t.Errorf("saved %.2f instead of 550.00", p.Saved)
+	}
+	if p.Remaining != 450 {
+		t.Errorf("remaining %.2f instead of 450.00", p.Remaining)
+	}
+	if p.AverageMonthly != 200 {
+		t.Errorf("average monthly %.2f instead of 200.00", p.AverageMonthly)
+	}
+
+	p, err = GetGoalProgress(ns, g, etss, testDate(t, "15/04/2024"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Saved != 400 {
+		t.Errorf("saved %.2f instead of 400.00", p.Saved)
+	}
+	for _, c := range p.Contributions {
+		if c.Month != "2024-03" && c.Amount != 0 {
+			t.Errorf("contribution %.2f in %s, before the start date", c.Amount, c.Month)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+)
+
+type GrantDTO struct {
+	User string `json:"user"`
+	Role string `json:"role"`
+}
+
+type GrantsDTO []GrantDTO
+
+func NewGrantsDTO(usersRepo *repositories.UsersRepo, gs models.Grants) (GrantsDTO, error) {
+	gsDTO := GrantsDTO{}
+	for _, g := range gs {
+		u, err := GetUserByID(usersRepo, g.UserID)
+		if err != nil {
+			return GrantsDTO{}, err
+		}
+		gsDTO = append(gsDTO, GrantDTO{
+			User: u.Name,
+			Role: g.Role,
+		})
+	}
+	return gsDTO, nil
+}
+
+// NewGrant gets the role to grant on the entity.
+func (gDTO GrantDTO) NewGrant(usersRepo *repositories.UsersRepo, owner models.User,
+	tse models.TransactionsEntity) (models.Grant, error) {
+	g := models.Grant{
+		OwnerID: owner.ID,
+		Entity:  tse.Entity,
+		Kind:    tse.Kind,
+		Role:    strings.Trim(gDTO.Role, " "),
+	}
+	if !slices.Contains(models.Roles, g.Role) {
+		return g, fmt.Errorf("the role %q is not valid because it must be one of %v", g.Role, models.Roles)
+	}
+	u, err := GetUserByName(usersRepo, strings.Trim(gDTO.User, " "))
+	if err != nil {
+		return g, err
+	}
+	if u.ID == owner.ID {
+		return g, fmt.Errorf("the user %q owns the entity already", u.Name)
+	}
+	g.UserID = u.ID
+	return g, nil
+}
+
+func newGrant(gDAO repositories.GrantDAO) models.Grant {
+	return models.Grant{
+		OwnerID: gDAO.OwnerID,
+		Entity:  gDAO.Entity,
+		Kind:    gDAO.Kind,
+		UserID:  gDAO.UserID,
+		Role:    gDAO.Role,
+	}
+}
+
+func newGrantDAO(g models.Grant) repositories.GrantDAO {
+	return repositories.GrantDAO{
+		OwnerID: g.OwnerID,
+		Entity:  g.Entity,
+		Kind:    g.Kind,
+		UserID:  g.UserID,
+		Role:    g.Role,
+	}
+}
+
+func getAllGrants(repo *repositories.GrantsRepo) (models.Grants, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("grants repo wasn't initialized")
+	}
+	gsDAO, err := repo.GetAllGrants()
+	if err != nil {
+		return nil, err
+	}
+	gs := models.Grants{}
+	for _, gDAO := range gsDAO {
+		gs = append(gs, newGrant(gDAO))
+	}
+	return gs, nil
+}
+
+// GetEntityGrants gets the roles granted to other users on the entity of the owner.
+func GetEntityGrants(repo *repositories.GrantsRepo, ownerID int, entity, kind string) (models.Grants, error) {
+	gs, err := getAllGrants(repo)
+	if err != nil {
+		return nil, err
+	}
+	entityGs := models.Grants{}
+	for _, g := range gs {
+		if g.OwnerID == ownerID && g.Entity == entity && g.Kind == kind {
+			entityGs = append(entityGs, g)
+		}
+	}
+	return entityGs, nil
+}
+
+// GetUserGrants gets the roles granted to the user on the entities of the owner.
+func GetUserGrants(repo *repositories.GrantsRepo, ownerID, uID int) (models.Grants, error) {
+	gs, err := getAllGrants(repo)
+	if err != nil {
+		return nil, err
+	}
+	userGs := models.Grants{}
+	for _, g := range gs {
+		if g.OwnerID == ownerID && g.UserID == uID {
+			userGs = append(userGs, g)
+		}
+	}
+	return userGs, nil
+}
+
+func SetGrant(repo *repositories.GrantsRepo, g models.Grant) error {
+	if repo == nil {
+		return fmt.Errorf("grants repo wasn't initialized")
+	}
+	return repo.SetGrant(newGrantDAO(g))
+}
+
+func DeleteGrant(repo *repositories.GrantsRepo, ownerID int, entity, kind string, uID int) error {
+	if repo == nil {
+		return fmt.Errorf("grants repo wasn't initialized")
+	}
+	return repo.DeleteGrant(ownerID, entity, kind, uID)
+}
+
+// EntityRole is the role of the user on the entity of the owner.
+func EntityRole(repo *repositories.GrantsRepo, ownerID, uID int, entity, kind string) (string, error) {
+	if uID == ownerID {
+		return models.OwnerRole, nil
+	}
+	gs, err := GetUserGrants(repo, ownerID, uID)
+	if err != nil {
+		return "", err
+	}
+	for _, g := range gs {
+		if g.Entity == entity && g.Kind == kind {
+			return g.Role, nil
+		}
+	}
+	return "", nil
+}
+
+// RoleGrants tells if the role grants another one.
+func RoleGrants(role, other string) bool {
+	idx := slices.Index(models.Roles, role)
+	return idx != -1 && idx >= slices.Index(models.Roles, other)
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+const (
+	multipartOverhead int64 = 1 << 20
+)
+
+// TransactionAttachmentsHandlerFunc /transactions/:transaction_id/attachments[/:attachment_id]
+func TransactionAttachmentsHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || len(parts) > 4 || parts[2] != "attachments" {
+		writeResponseWithError(w, http.StatusNotFound, notFound)
+		return
+	}
+	tID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+		return
+	}
+	aID := 0
+	if len(parts) == 4 {
+		if aID, err = strconv.Atoi(parts[3]); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+	}
+
+	entityProvided := r.URL.Query().Get("entity")
+	typeProvided := r.URL.Query().Get("type")
+	if r.Method != http.MethodOptions && (!entityIsValid(entityProvided) || !kindIsValid(typeProvided)) {
+		writeResponseWithError(w, http.StatusBadRequest, badRequest)
+		return
+	}
+
+	switch {
+
+	case r.Method == http.MethodGet && aID == 0:
+		repo, err := ns.GetTransRepo(typeProvided, entityProvided)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if _, err = services.GetTransactionByID(repo, tID); err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+		asDTO, err := services.GetTransactionAttachments(repo, tID)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(asDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, asDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodGet:
+		repo, err := ns.GetTransRepo(typeProvided, entityProvided)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		aDTO, content, err := services.GetTransactionAttachment(repo, tID, aID)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", aDTO.ContentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment",
+			map[string]string{"filename": aDTO.FileName}))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusOK)
+		if _, err = w.Write(content); err != nil {
+			logDetailedError(err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, aDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodPost && aID == 0:
+		r.Body = http.MaxBytesReader(w, r.Body, int64(services.MaxAttachmentSize)+multipartOverhead)
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				writeResponseWithDetailedError(w, http.StatusRequestEntityTooLarge, requestEntityTooLarge,
+					fmt.Errorf("the attachment is larger than the max of %d bytes", services.MaxAttachmentSize))
+				return
+			}
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		defer file.Close()
+		content, err := io.ReadAll(io.LimitReader(file, int64(services.MaxAttachmentSize)+1))
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		if len(content) > services.MaxAttachmentSize {
+			writeResponseWithDetailedError(w, http.StatusRequestEntityTooLarge, requestEntityTooLarge,
+				fmt.Errorf("the attachment is larger than the max of %d bytes", services.MaxAttachmentSize))
+			return
+		}
+		if len(content) == 0 {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, fmt.Errorf("the attachment is empty"))
+			return
+		}
+		if _, err = services.AttachmentContentType(content); err != nil {
+			writeResponseWithDetailedError(w, http.StatusUnsupportedMediaType, unsupportedMediaType, err)
+			return
+		}
+
+		repo, err := ns.GetTransRepo(typeProvided, entityProvided)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if _, err = services.GetTransactionByID(repo, tID); err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+		aDTO, err := services.AddTransactionAttachment(repo, tID, header.Filename, content)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err = json.NewEncoder(w).Encode(aDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, created, aDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodDelete && aID != 0:
+		repo, err := ns.GetTransRepo(typeProvided, entityProvided)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if _, _, err = services.GetTransactionAttachment(repo, tID, aID); err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+		if err = services.DeleteTransactionAttachment(repo, tID, aID); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if aID == 0 {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+		} else {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, DELETE")
+		}
+		w.WriteHeader(http.StatusNoContent)
+		if err := logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+)
+
+var (
+	requestIDRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+)
+
+func newRequestID(r *http.Request) (string, error) {
+	if requestID := r.Header.Get(RequestIDHeader); requestIDRegexp.MatchString(requestID) {
+		return requestID, nil
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+func audited(r *http.Request) bool {
+	switch strings.Split(strings.Trim(r.URL.Path, "/"), "/")[0] {
+	case "users", "tokens", "auth":
+		return false
+	}
+	return changesState(r)
+}
+
+func serveAudited(next http.Handler, w http.ResponseWriter, r *http.Request, auth authentication) {
+	ns := requestNamespace(r)
+	services.StartAuditingChanges(ns)
+	next.ServeHTTP(w, r)
+
+	before, after, err := services.TakeAuditedChanges(ns)
+	if err != nil {
+		logDetailedError(err)
+		return
+	}
+	repo, err := ns.GetAuditRepo()
+	if err != nil {
+		logDetailedError(err)
+		return
+	}
+	request := fmt.Sprintf("%s %s", r.Method, strings.ReplaceAll(r.URL.EscapedPath(), ";", "%3B"))
+	if err = services.AddAuditEntries(repo, before, after, auth.user.Name, auth.requestID, request,
+		time.Now().UTC().Truncate(time.Second)); err != nil {
+		logDetailedError(err)
+	}
+}
+
+// AuditHandlerFunc /audit?entity=&from=&to=
+func AuditHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	switch r.Method {
+
+	case http.MethodGet:
+		entityProvided := r.URL.Query().Get("entity")
+		if entityProvided != "" && !entityIsValid(entityProvided) {
+			writeResponseWithError(w, http.StatusBadRequest, badRequest)
+			return
+		}
+		from, err := dateQueryParam(r, "from")
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		to, err := dateQueryParam(r, "to")
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		repo, err := ns.GetAuditRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		es, err := services.GetAuditEntries(repo, entityProvided, from, to)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		esDTO := services.NewAuditEntriesDTO(es)
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(esDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, esDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
+
+// TransactionHistoryHandlerFunc /transactions/:transaction_id/history?entity=&type=
+func TransactionHistoryHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	switch r.Method {
+
+	case http.MethodGet:
+		tID, err := strconv.Atoi(strings.Split(strings.Trim(r.URL.Path, "/"), "/")[1])
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		entityProvided := r.URL.Query().Get("entity")
+		typeProvided := r.URL.Query().Get("type")
+		if !entityIsValid(entityProvided) || !kindIsValid(typeProvided) {
+			writeResponseWithError(w, http.StatusBadRequest, badRequest)
+			return
+		}
+
+		repo, err := ns.GetAuditRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		es, err := services.GetTransactionHistory(repo, entityProvided, typeProvided, tID)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if len(es) == 0 {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound,
+				fmt.Errorf("the transaction %d has no history", tID))
+			return
+		}
+
+		esDTO := services.NewAuditEntriesDTO(es)
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(esDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, esDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
@@ -0,0 +1,470 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+const (
+	SessionCookie = "daily_expenses_session"
+	CSRFHeader    = "X-CSRF-Token"
+)
+
+type authContextKey struct{}
+
+type authentication struct {
+	user      models.User
+	owner     models.User
+	token     models.Token
+	session   models.Session
+	scopes    []string
+	namespace *repositories.Namespace
+	requestID string
+}
+
+var (
+	AllowedOrigin  string
+	SecureCookie   = true
+	CookieSameSite = http.SameSiteLaxMode
+)
+
+type corsResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *corsResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader && AllowedOrigin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", AllowedOrigin)
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		w.Header().Add("Vary", "Origin")
+	}
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *corsResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *corsResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func requestAuthentication(r *http.Request) authentication {
+	auth, _ := r.Context().Value(authContextKey{}).(authentication)
+	return auth
+}
+
+func requestNamespace(r *http.Request) *repositories.Namespace {
+	return requestAuthentication(r).namespace
+}
+
+func usesGlobalDBFiles(r *http.Request) bool {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch parts[0] {
+	case "users", "tokens", "auth":
+		return true
+	case "entities":
+		return len(parts) > 2 && parts[2] == "grants"
+	}
+	return false
+}
+
+func requiredScope(r *http.Request) string {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch parts[0] {
+	case "users", "tokens":
+		return models.AdminScope
+	case "auth":
+		return models.ReadScope
+	}
+	if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+		return models.ReadScope
+	}
+	switch parts[0] {
+	case "categories", "entities":
+		if len(parts) <= 2 {
+			return models.AdminScope
+		}
+	case "import":
+		return models.AdminScope
+	}
+	return models.WriteScope
+}
+
+func bearerToken(r *http.Request) string {
+	scheme, token, found := strings.Cut(strings.Trim(r.Header.Get("Authorization"), " "), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.Trim(token, " ")
+}
+
+func changesState(r *http.Request) bool {
+	return r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions
+}
+
+func authenticate(r *http.Request, token string, now time.Time) (authentication, int, string, error) {
+	usersRepo, err := repositories.GetUsersRepo()
+	if err != nil {
+		return authentication{}, http.StatusInternalServerError, internalServerError, err
+	}
+
+	if token != "" {
+		tsRepo, err := repositories.GetTokensRepo()
+		if err != nil {
+			return authentication{}, http.StatusInternalServerError, internalServerError, err
+		}
+		t, u, err := services.AuthenticateToken(tsRepo, usersRepo, token)
+		if err != nil {
+			return authentication{}, http.StatusUnauthorized, unauthorized, err
+		}
+		return authentication{
+			user:   u,
+			token:  t,
+			scopes: t.Scopes,
+		}, http.StatusOK, ok, nil
+	}
+
+	cookie, err := r.Cookie(SessionCookie)
+	if err != nil {
+		return authentication{}, http.StatusUnauthorized, unauthorized,
+			fmt.Errorf("the request has no bearer token nor session")
+	}
+	ssRepo, err := repositories.GetSessionsRepo()
+	if err != nil {
+		return authentication{}, http.StatusInternalServerError, internalServerError, err
+	}
+	s, u, err := services.AuthenticateSession(ssRepo, usersRepo, cookie.Value, now)
+	if err != nil {
+		return authentication{}, http.StatusUnauthorized, unauthorized, err
+	}
+	if changesState(r) {
+		if err = services.CheckCSRFToken(s, r.Header.Get(CSRFHeader)); err != nil {
+			return authentication{}, http.StatusForbidden, forbidden, err
+		}
+	}
+	return authentication{
+		user:    u,
+		session: s,
+		scopes:  s.Scopes,
+	}, http.StatusOK, ok, nil
+}
+
+// WithAuthentication authenticates the requests.
+func WithAuthentication(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w = &corsResponseWriter{ResponseWriter: w}
+		requestID, err := newRequestID(r)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		token := bearerToken(r)
+		if r.Method == http.MethodOptions && token == "" {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE")
+			w.Header().Set("Access-Control-Allow-Headers", fmt.Sprintf("Authorization, Content-Type, %s, %s", CSRFHeader,
+				RequestIDHeader))
+			w.Header().Set("Access-Control-Expose-Headers", RequestIDHeader)
+			w.WriteHeader(http.StatusNoContent)
+			if err := logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+				logDetailedError(err)
+			}
+			return
+		}
+
+		if r.URL.Path == "/auth/login" {
+			repositories.GlobalDBFilesMu.Lock()
+			defer repositories.GlobalDBFilesMu.Unlock()
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		auth, code, status, err := authenticateOwner(r, token)
+		if err != nil {
+			if code == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			writeResponseWithDetailedError(w, code, status, err)
+			return
+		}
+
+		// the namespace is locked before GlobalDBFilesMu
+		auth.namespace = repositories.UserNamespace(auth.owner.Name)
+		auth.namespace.Lock()
+		defer auth.namespace.Unlock()
+		repositories.GlobalDBFilesMu.Lock()
+		code, status, err = checkRoles(r, auth)
+		if usesGlobalDBFiles(r) {
+			defer repositories.GlobalDBFilesMu.Unlock()
+		} else {
+			repositories.GlobalDBFilesMu.Unlock()
+		}
+		if err != nil {
+			writeResponseWithDetailedError(w, code, status, err)
+			return
+		}
+		auth.requestID = requestID
+		r = r.WithContext(context.WithValue(r.Context(), authContextKey{}, auth))
+		if audited(r) {
+			serveAudited(next, w, r, auth)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func authenticateOwner(r *http.Request, token string) (authentication, int, string, error) {
+	repositories.GlobalDBFilesMu.Lock()
+	defer repositories.GlobalDBFilesMu.Unlock()
+
+	auth, code, status, err := authenticate(r, token, time.Now().UTC())
+	if err != nil {
+		return authentication{}, code, status, err
+	}
+	if scope := requiredScope(r); !services.ScopesGrant(auth.scopes, scope) {
+		return authentication{}, http.StatusForbidden, forbidden,
+			fmt.Errorf("the user %q isn't granted the scope %q", auth.user.Name, scope)
+	}
+	if r.URL.Path == "/users" && !auth.user.ServerAdmin {
+		return authentication{}, http.StatusForbidden, forbidden,
+			fmt.Errorf("the user %q isn't a server admin", auth.user.Name)
+	}
+	if auth.owner, code, status, err = namespaceOwner(r, auth.user); err != nil {
+		return authentication{}, code, status, err
+	}
+	return auth, http.StatusOK, ok, nil
+}
+
+func setSessionCookie(w http.ResponseWriter, sessionID string, expiresAt time.Time) {
+	cookie := &http.Cookie{
+		Name:     SessionCookie,
+		Value:    sessionID,
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		Secure:   SecureCookie,
+		SameSite: CookieSameSite,
+	}
+	if sessionID == "" {
+		cookie.MaxAge = -1
+	}
+	http.SetCookie(w, cookie)
+}
+
+// LoginHandlerFunc /auth/login
+func LoginHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+
+	case http.MethodPost:
+		lDTO := services.LoginDTO{}
+		if err := json.NewDecoder(r.Body).Decode(&lDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		credentialsRepo, err := repositories.GetCredentialsRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		ssRepo, err := repositories.GetSessionsRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		usersRepo, err := repositories.GetUsersRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		now := time.Now().UTC().Truncate(time.Second)
+
+		if err = services.CheckLoginIsNotLocked(credentialsRepo, usersRepo, lDTO.User, now); err != nil {
+			w.Header().Set("Retry-After", strconv.Itoa(int(services.LockoutDuration.Seconds())))
+			writeResponseWithDetailedError(w, http.StatusTooManyRequests, tooManyRequests, err)
+			return
+		}
+		u, s, sessionID, err := services.Login(credentialsRepo, ssRepo, usersRepo, lDTO, now)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusUnauthorized, unauthorized, err)
+			return
+		}
+		sDTO := services.NewSessionDTO(u, s)
+
+		setSessionCookie(w, sessionID, s.ExpiresAt)
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(sDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, sDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
+
+// LogoutHandlerFunc /auth/logout
+func LogoutHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+
+	case http.MethodPost:
+		auth := requestAuthentication(r)
+		if auth.session.Hash == "" {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest,
+				fmt.Errorf("the request isn't of a session"))
+			return
+		}
+
+		ssRepo, err := repositories.GetSessionsRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = services.Logout(ssRepo, auth.session); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		setSessionCookie(w, "", time.Unix(0, 0))
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
+
+// MeHandlerFunc /auth/me
+func MeHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+
+	case http.MethodGet:
+		auth := requestAuthentication(r)
+		sDTO := services.SessionDTO{
+			User:   auth.user.Name,
+			Scopes: auth.scopes,
+		}
+		if auth.session.Hash != "" {
+			sDTO = services.NewSessionDTO(auth.user, auth.session)
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(sDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err := logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, sDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
+
+// PasswordHandlerFunc /auth/password
+func PasswordHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+
+	case http.MethodPut:
+		pDTO := services.PasswordDTO{}
+		if err := json.NewDecoder(r.Body).Decode(&pDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		credentialsRepo, err := repositories.GetCredentialsRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		ssRepo, err := repositories.GetSessionsRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		auth := requestAuthentication(r)
+
+		u := auth.user
+		reset := pDTO.User != "" && pDTO.User != u.Name
+		if !reset && u.ServerAdmin {
+			hasPassword, err := services.HasPassword(credentialsRepo, u.ID)
+			if err != nil {
+				writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+				return
+			}
+			reset = !hasPassword
+		}
+		if reset || len(pDTO.Scopes) > 0 {
+			if !u.ServerAdmin || !services.ScopesGrant(auth.scopes, models.AdminScope) {
+				writeResponseWithDetailedError(w, http.StatusForbidden, forbidden,
+					fmt.Errorf("only a server admin resets the passwords of other users and sets the scopes"))
+				return
+			}
+		}
+		if reset {
+			if pDTO.User != "" && pDTO.User != u.Name {
+				usersRepo, err := repositories.GetUsersRepo()
+				if err != nil {
+					writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+					return
+				}
+				if u, err = services.GetUserByName(usersRepo, pDTO.User); err != nil {
+					writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+					return
+				}
+			}
+			err = services.ResetPassword(credentialsRepo, ssRepo, u.ID, pDTO.NewPassword, pDTO.Scopes)
+		} else {
+			err = services.ChangePassword(credentialsRepo, ssRepo, u.ID, pDTO.CurrentPassword, pDTO.NewPassword,
+				pDTO.Scopes)
+		}
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		if u.ID == auth.user.ID && auth.session.Hash != "" {
+			setSessionCookie(w, "", time.Unix(0, 0))
+		}
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+// ExportHandlerFunc /export
+func ExportHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	switch r.Method {
+
+	case http.MethodGet:
+		tsesRepo, err := ns.GetTransEntRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		csRepo, err := ns.GetCategoriesRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		repos, err := ns.GetAllRepos()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		backup, err := services.ExportBackup(tsesRepo, csRepo, *repos)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", `attachment; filename="daily-expenses-backup.json"`)
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(backup); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, backup); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
+
+// ImportHandlerFunc /import?strategy=skip|overwrite|renumber
+func ImportHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+
+	case http.MethodPost:
+		strategyProvided := r.URL.Query().Get("strategy")
+		if strategyProvided == "" {
+			strategyProvided = string(services.SkipImportStrategy)
+		}
+		if !services.ImportStrategyIsValid(strategyProvided) {
+			writeResponseWithError(w, http.StatusBadRequest, badRequest)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, int64(services.MaxBackupSize))
+		backup := services.BackupDTO{}
+		if err := json.NewDecoder(r.Body).Decode(&backup); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				writeResponseWithDetailedError(w, http.StatusRequestEntityTooLarge, requestEntityTooLarge,
+					fmt.Errorf("the backup is larger than the max of %d bytes", services.MaxBackupSize))
+				return
+			}
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		report, err := services.ImportBackup(requestNamespace(r), backup, services.ImportStrategy(strategyProvided))
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(report); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, report); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
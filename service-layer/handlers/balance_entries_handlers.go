@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+// balanceEntriesHandlerFunc /entities/:entity_id/balance-entries[/:balance_entry_id]
+func balanceEntriesHandlerFunc(w http.ResponseWriter, r *http.Request, tse models.TransactionsEntity,
+	pathParts []string) {
+	ns := requestNamespace(r)
+	if len(pathParts) > 1 {
+		writeResponseWithError(w, http.StatusNotFound, notFound)
+		return
+	}
+	var err error
+	beID := 0
+	if len(pathParts) == 1 {
+		if beID, err = strconv.Atoi(pathParts[0]); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+	}
+
+	repo, err := ns.GetBalanceEntriesRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+
+	switch {
+
+	case r.Method == http.MethodGet && beID == 0:
+		bes, err := services.GetBalanceEntries(repo, tse.Entity, tse.Kind)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		besDTO := services.NewBalanceEntriesDTO(bes)
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(besDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, besDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodPost && beID == 0:
+		nbeDTO := services.BalanceEntryDTO{}
+		if err = json.NewDecoder(r.Body).Decode(&nbeDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		tsesRepo, err := ns.GetTransEntRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		tsRepo, err := ns.GetTransRepo(tse.Kind, tse.Entity)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		nbe, err := services.AddBalanceEntry(repo, tsesRepo, tsRepo, nbeDTO)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		nbeDTO = services.NewBalanceEntryDTO(nbe)
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err = json.NewEncoder(w).Encode(nbeDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, created, nbeDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodDelete && beID != 0:
+		tsesRepo, err := ns.GetTransEntRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		tsRepo, err := ns.GetTransRepo(tse.Kind, tse.Entity)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		if err = services.DeleteBalanceEntry(repo, tsesRepo, tsRepo, beID); err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if beID == 0 {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+		} else {
+			w.Header().Set("Access-Control-Allow-Methods", "DELETE")
+		}
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
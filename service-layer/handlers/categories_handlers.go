@@ -3,7 +3,6 @@ package handlers
 import (
 	"encoding/json"
 	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
-	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
 	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
 	"net/http"
 	"strconv"
@@ -12,10 +11,11 @@ import (
 
 // CategoriesHandlerFunc /categories
 func CategoriesHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
 	switch r.Method {
 
 	case http.MethodGet:
-		repo, err := repositories.GetCategoriesRepo()
+		repo, err := ns.GetCategoriesRepo()
 		if err != nil {
 			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 			return
@@ -52,7 +52,7 @@ func CategoriesHandlerFunc(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		repo, err := repositories.GetCategoriesRepo()
+		repo, err := ns.GetCategoriesRepo()
 		if err != nil {
 			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 			return
@@ -86,6 +86,7 @@ func CategoriesHandlerFunc(w http.ResponseWriter, r *http.Request) {
 
 // UpdateCategoryHandlerFunc /categories/:category_id
 func UpdateCategoryHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
 	switch r.Method {
 
 	case http.MethodPut:
@@ -112,7 +113,7 @@ func UpdateCategoryHandlerFunc(w http.ResponseWriter, r *http.Request) {
 
 		c.ID = cID
 
-		repo, err := repositories.GetCategoriesRepo()
+		repo, err := ns.GetCategoriesRepo()
 		if err != nil {
 			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 			return
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+const (
+	statementPaymentsResource = "payments"
+)
+
+// creditCardHandlerFunc /entities/:entity_id/card
+func creditCardHandlerFunc(w http.ResponseWriter, r *http.Request, tse models.TransactionsEntity, pathParts []string) {
+	ns := requestNamespace(r)
+	if len(pathParts) != 0 {
+		writeResponseWithError(w, http.StatusNotFound, notFound)
+		return
+	}
+
+	repo, err := ns.GetCreditCardsRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+
+	switch r.Method {
+
+	case http.MethodGet:
+		cc, err := services.GetCreditCard(repo, tse.Entity, tse.Kind)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+
+		ccDTO := services.NewCreditCardDTO(cc)
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(ccDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, ccDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodPut:
+		ccDTO := services.CreditCardDTO{}
+		if err = json.NewDecoder(r.Body).Decode(&ccDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		cc, err := ccDTO.NewCreditCard(tse.Entity, tse.Kind)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		if err = services.SetCreditCard(repo, cc); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		ccDTO = services.NewCreditCardDTO(cc)
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(ccDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, ccDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodDelete:
+		cc, err := services.GetCreditCard(repo, tse.Entity, tse.Kind)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+		spRepo, err := ns.GetStatementsPaymentsRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = services.DeleteCreditCard(repo, spRepo, cc); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT, DELETE")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
+
+// statementsHandlerFunc /entities/:entity_id/statements[/:statement_id[/payments[/:transaction_id]]]
+func statementsHandlerFunc(w http.ResponseWriter, r *http.Request, tse models.TransactionsEntity, pathParts []string) {
+	ns := requestNamespace(r)
+	if len(pathParts) > 3 || len(pathParts) > 1 && pathParts[1] != statementPaymentsResource {
+		writeResponseWithError(w, http.StatusNotFound, notFound)
+		return
+	}
+	statementID := ""
+	if len(pathParts) > 0 {
+		statementID = pathParts[0]
+		if _, err := services.ParseStatementID(statementID); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+	}
+	payments := len(pathParts) > 1
+	tID := 0
+	if len(pathParts) == 3 {
+		var err error
+		if tID, err = strconv.Atoi(pathParts[2]); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+	}
+
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		switch {
+		case tID != 0:
+			w.Header().Set("Access-Control-Allow-Methods", "DELETE")
+		case payments:
+			w.Header().Set("Access-Control-Allow-Methods", "POST")
+		default:
+			w.Header().Set("Access-Control-Allow-Methods", "GET")
+		}
+		w.WriteHeader(http.StatusNoContent)
+		if err := logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+		return
+	}
+
+	ccsRepo, err := ns.GetCreditCardsRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+	cc, err := services.GetCreditCard(ccsRepo, tse.Entity, tse.Kind)
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+		return
+	}
+	spRepo, err := ns.GetStatementsPaymentsRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+	tsRepo, err := ns.GetTransRepo(tse.Kind, tse.Entity)
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	switch {
+
+	case r.Method == http.MethodGet && statementID == "":
+		sDTOs, err := services.GetStatements(spRepo, tsRepo, cc, today)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(sDTOs); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, sDTOs); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodGet && !payments:
+		sDTO, err := services.GetStatement(spRepo, tsRepo, cc, statementID, today)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(sDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, sDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodPost && payments && tID == 0:
+		if _, err = services.GetStatement(spRepo, tsRepo, cc, statementID, today); err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+		spDTO := services.StatementPaymentDTO{}
+		if err = json.NewDecoder(r.Body).Decode(&spDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		if !entityIsValid(spDTO.Entity) || !kindIsValid(spDTO.EntityKind) {
+			writeResponseWithError(w, http.StatusBadRequest, badRequest)
+			return
+		}
+
+		spDTO, err = services.LinkStatementPayment(spRepo, cc, statementID, spDTO)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err = json.NewEncoder(w).Encode(spDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, created, spDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodDelete && tID != 0:
+		entityProvided := r.URL.Query().Get("entity")
+		typeProvided := r.URL.Query().Get("type")
+		if !entityIsValid(entityProvided) || !kindIsValid(typeProvided) {
+			writeResponseWithError(w, http.StatusBadRequest, badRequest)
+			return
+		}
+
+		if err = services.UnlinkStatementPayment(spRepo, cc, statementID, entityProvided, typeProvided,
+			tID); err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
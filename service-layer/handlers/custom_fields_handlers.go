@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+// customFieldsHandlerFunc /entities/:entity_id/fields[/:name]
+func customFieldsHandlerFunc(w http.ResponseWriter, r *http.Request, tse models.TransactionsEntity, pathParts []string) {
+	ns := requestNamespace(r)
+	if len(pathParts) > 1 {
+		writeResponseWithError(w, http.StatusNotFound, notFound)
+		return
+	}
+	name := ""
+	if len(pathParts) == 1 {
+		name = pathParts[0]
+	}
+
+	repo, err := ns.GetCustomFieldsRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+
+	switch {
+
+	case r.Method == http.MethodGet && name == "":
+		cfs, err := services.GetCustomFields(repo, tse.Entity, tse.Kind)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		cfsDTO := services.NewCustomFieldsDTO(cfs)
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(cfsDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, cfsDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodGet:
+		cf, err := services.GetCustomField(repo, tse.Entity, tse.Kind, name)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+
+		cfDTO := services.NewCustomFieldDTO(cf)
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(cfDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, cfDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodPost && name == "":
+		ncfDTO := services.CustomFieldDTO{}
+		if err = json.NewDecoder(r.Body).Decode(&ncfDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		ncf, err := ncfDTO.NewCustomField(tse.Entity, tse.Kind)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		if _, err = services.GetCustomField(repo, tse.Entity, tse.Kind, ncf.Name); err == nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest,
+				fmt.Errorf("the field %q already exists", ncf.Name))
+			return
+		}
+
+		newID, err := services.AddCustomField(repo, ncf)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		ncf.ID = newID
+		ncfDTO = services.NewCustomFieldDTO(ncf)
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err = json.NewEncoder(w).Encode(ncfDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, created, ncfDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodDelete && name != "":
+		if _, err = services.GetCustomField(repo, tse.Entity, tse.Kind, name); err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+		tsRepo, err := ns.GetTransRepo(tse.Kind, tse.Entity)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = services.DeleteCustomField(repo, tsRepo, name); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if name == "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+		} else {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, DELETE")
+		}
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+func monthsQueryParam(r *http.Request) (int, error) {
+	value := strings.Trim(r.URL.Query().Get("months"), " ")
+	if value == "" {
+		return services.DefaultGoalProjectionMonths, nil
+	}
+	months, err := strconv.Atoi(value)
+	if err != nil || months < 1 || months > services.MaxGoalProjectionMonths {
+		return 0, fmt.Errorf("the value %q for months is not valid", value)
+	}
+	return months, nil
+}
+
+func newGoalWithProgress(ns *repositories.Namespace, g models.Goal, today time.Time, months int) (services.GoalDTO,
+	error) {
+	gDTO := services.NewGoalDTO(g)
+	repos, err := ns.GetAllRepos()
+	if err != nil {
+		return gDTO, err
+	}
+	etss, err := services.GetEntitiesTransactions(*repos, nil)
+	if err != nil {
+		return gDTO, err
+	}
+	gDTO.Progress, err = services.GetGoalProgress(ns, g, etss, today, months)
+	return gDTO, err
+}
+
+// GoalsHandlerFunc /goals?months=
+func GoalsHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	repo, err := ns.GetGoalsRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	switch r.Method {
+
+	case http.MethodGet:
+		months, err := monthsQueryParam(r)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		gs, err := services.GetAllGoals(repo)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		gsDTO := services.GoalsDTO{}
+		for _, g := range gs {
+			gDTO, err := newGoalWithProgress(ns, g, today, months)
+			if err != nil {
+				writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+				return
+			}
+			gsDTO = append(gsDTO, gDTO)
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(gsDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, gsDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodPost:
+		ngDTO := services.GoalDTO{}
+		if err = json.NewDecoder(r.Body).Decode(&ngDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		ng, err := ngDTO.NewGoal(ns, today)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		if ng.ID, err = services.AddGoal(repo, ng); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		ngDTO, err = newGoalWithProgress(ns, ng, today, services.DefaultGoalProjectionMonths)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err = json.NewEncoder(w).Encode(ngDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, created, ngDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
+
+// GoalHandlerFunc /goals/:goal_id?months=
+func GoalHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	gIDStr := strings.Split(r.URL.Path, "/goals/")[1]
+	gID, err := strconv.Atoi(gIDStr)
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+		return
+	}
+
+	repo, err := ns.GetGoalsRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	var g models.Goal
+	if r.Method != http.MethodOptions {
+		if g, err = services.GetGoalByID(repo, gID); err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+	}
+
+	switch r.Method {
+
+	case http.MethodGet:
+		months, err := monthsQueryParam(r)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		gDTO, err := newGoalWithProgress(ns, g, today, months)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(gDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, gDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodPut:
+		gDTO := services.GoalDTO{}
+		if err = json.NewDecoder(r.Body).Decode(&gDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		if strings.Trim(gDTO.StartDate, " ") == "" {
+			gDTO.StartDate = services.NewGoalDTO(g).StartDate
+		}
+		ug, err := gDTO.NewGoal(ns, today)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		ug.ID = gID
+
+		if err = services.UpdateGoal(repo, ug); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		gDTO, err = newGoalWithProgress(ns, ug, today, services.DefaultGoalProjectionMonths)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(gDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, gDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodDelete:
+		if err = services.DeleteGoal(repo, gID); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT, DELETE")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+func namespaceOwner(r *http.Request, user models.User) (models.User, int, string, error) {
+	ownerName := r.URL.Query().Get("owner")
+	if ownerName == "" || ownerName == user.Name {
+		return user, http.StatusOK, ok, nil
+	}
+	noRoleErr := fmt.Errorf("the user %q has no role on the entities of the user %q", user.Name, ownerName)
+
+	usersRepo, err := repositories.GetUsersRepo()
+	if err != nil {
+		return models.User{}, http.StatusInternalServerError, internalServerError, err
+	}
+	owner, err := services.GetUserByName(usersRepo, ownerName)
+	if err != nil {
+		return models.User{}, http.StatusForbidden, forbidden, noRoleErr
+	}
+	grantsRepo, err := repositories.GetGrantsRepo()
+	if err != nil {
+		return models.User{}, http.StatusInternalServerError, internalServerError, err
+	}
+	gs, err := services.GetUserGrants(grantsRepo, owner.ID, user.ID)
+	if err != nil {
+		return models.User{}, http.StatusInternalServerError, internalServerError, err
+	}
+	if len(gs) == 0 {
+		return models.User{}, http.StatusForbidden, forbidden, noRoleErr
+	}
+	return owner, http.StatusOK, ok, nil
+}
+
+func checkEntityRole(auth authentication, entity, kind, role string) (int, string, error) {
+	grantsRepo, err := repositories.GetGrantsRepo()
+	if err != nil {
+		return http.StatusInternalServerError, internalServerError, err
+	}
+	userRole, err := services.EntityRole(grantsRepo, auth.owner.ID, auth.user.ID, entity, kind)
+	if err != nil {
+		return http.StatusInternalServerError, internalServerError, err
+	}
+	if !services.RoleGrants(userRole, role) {
+		return http.StatusForbidden, forbidden,
+			fmt.Errorf("the user %q isn't granted the role %q on the entity %q of type %q", auth.user.Name, role,
+				entity, kind)
+	}
+	return http.StatusOK, ok, nil
+}
+
+func checkRoles(r *http.Request, auth authentication) (int, string, error) {
+	if auth.owner.ID == auth.user.ID {
+		return http.StatusOK, ok, nil
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	read := !changesState(r)
+	role := models.EditorRole
+	if read {
+		role = models.ViewerRole
+	}
+
+	switch parts[0] {
+	case "transactions":
+		entity, kind := r.URL.Query().Get("entity"), r.URL.Query().Get("type")
+		if entity != "" && kind != "" {
+			return checkEntityRole(auth, entity, kind, role)
+		}
+		if read && (len(parts) == 1 || parts[1] == "categories" || parts[1] == "types") {
+			return http.StatusOK, ok, nil
+		}
+		return http.StatusForbidden, forbidden,
+			fmt.Errorf("the entity and the type of the transactions of another user must be provided")
+
+	case "entities":
+		if len(parts) == 1 {
+			if read {
+				return http.StatusOK, ok, nil
+			}
+			return http.StatusForbidden, forbidden,
+				fmt.Errorf("only the user %q adds entities to their data", auth.owner.Name)
+		}
+		tseID, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return http.StatusBadRequest, badRequest, err
+		}
+		tsesRepo, err := auth.namespace.GetTransEntRepo()
+		if err != nil {
+			return http.StatusInternalServerError, internalServerError, err
+		}
+		tse, err := services.GetTransactionsEntityByID(tsesRepo, tseID)
+		if err != nil {
+			return http.StatusNotFound, notFound, err
+		}
+		if len(parts) == 2 || parts[2] == "grants" || !read && (parts[2] == "fields" || parts[2] == "card") {
+			role = models.OwnerRole
+		}
+		return checkEntityRole(auth, tse.Entity, tse.Kind, role)
+
+	case "categories":
+		if read {
+			return http.StatusOK, ok, nil
+		}
+		grantsRepo, err := repositories.GetGrantsRepo()
+		if err != nil {
+			return http.StatusInternalServerError, internalServerError, err
+		}
+		gs, err := services.GetUserGrants(grantsRepo, auth.owner.ID, auth.user.ID)
+		if err != nil {
+			return http.StatusInternalServerError, internalServerError, err
+		}
+		for _, g := range gs {
+			if g.Role == models.OwnerRole {
+				return http.StatusOK, ok, nil
+			}
+		}
+		return http.StatusForbidden, forbidden,
+			fmt.Errorf("the user %q isn't granted the role %q on any entity of the user %q", auth.user.Name,
+				models.OwnerRole, auth.owner.Name)
+	}
+
+	return http.StatusForbidden, forbidden,
+		fmt.Errorf("only the transactions, the entities and the categories of another user are shared")
+}
+
+func entityIsVisible(r *http.Request, entity, kind string) (bool, error) {
+	auth := requestAuthentication(r)
+	if auth.owner.ID == auth.user.ID {
+		return true, nil
+	}
+	grantsRepo, err := repositories.GetGrantsRepo()
+	if err != nil {
+		return false, err
+	}
+	role, err := services.EntityRole(grantsRepo, auth.owner.ID, auth.user.ID, entity, kind)
+	if err != nil {
+		return false, err
+	}
+	return role != "", nil
+}
+
+func visibleRepos(r *http.Request, repos []*repositories.TransactionsRepo) ([]*repositories.TransactionsRepo, error) {
+	var visible []*repositories.TransactionsRepo
+	for _, repo := range repos {
+		isVisible, err := entityIsVisible(r, repo.Entity, repo.Kind)
+		if err != nil {
+			return nil, err
+		}
+		if isVisible {
+			visible = append(visible, repo)
+		}
+	}
+	return visible, nil
+}
+
+func visibleTransactionsEntities(r *http.Request, tses models.TransactionsEntities) (models.TransactionsEntities,
+	error) {
+	visible := models.TransactionsEntities{}
+	for _, tse := range tses {
+		isVisible, err := entityIsVisible(r, tse.Entity, tse.Kind)
+		if err != nil {
+			return nil, err
+		}
+		if isVisible {
+			visible = append(visible, tse)
+		}
+	}
+	return visible, nil
+}
+
+// grantsHandlerFunc /entities/:entity_id/grants[/:user]
+func grantsHandlerFunc(w http.ResponseWriter, r *http.Request, tse models.TransactionsEntity, pathParts []string) {
+	repo, err := repositories.GetGrantsRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+	usersRepo, err := repositories.GetUsersRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+	owner := requestAuthentication(r).owner
+
+	if len(pathParts) > 0 {
+		switch r.Method {
+
+		case http.MethodDelete:
+			u, err := services.GetUserByName(usersRepo, pathParts[0])
+			if err != nil {
+				writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+				return
+			}
+			if err = services.DeleteGrant(repo, owner.ID, tse.Entity, tse.Kind, u.ID); err != nil {
+				writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			w.WriteHeader(http.StatusNoContent)
+			if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+				logDetailedError(err)
+				return
+			}
+
+		case http.MethodOptions:
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			w.Header().Set("Access-Control-Allow-Methods", "DELETE")
+			w.WriteHeader(http.StatusNoContent)
+			if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+				logDetailedError(err)
+				return
+			}
+
+		default:
+			writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+		}
+		return
+	}
+
+	switch r.Method {
+
+	case http.MethodGet:
+		gs, err := services.GetEntityGrants(repo, owner.ID, tse.Entity, tse.Kind)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		gsDTO, err := services.NewGrantsDTO(usersRepo, gs)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(gsDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, gsDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodPut:
+		gDTO := services.GrantDTO{}
+		if err = json.NewDecoder(r.Body).Decode(&gDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		g, err := gDTO.NewGrant(usersRepo, owner, tse)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		if err = services.SetGrant(repo, g); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		gDTO = services.GrantDTO{
+			User: strings.Trim(gDTO.User, " "),
+			Role: g.Role,
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(gDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, gDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+var (
+	journalsExtensions = map[services.JournalFormat]string{
+		services.LedgerJournalFormat:    "ledger",
+		services.HledgerJournalFormat:   "journal",
+		services.BeancountJournalFormat: "beancount",
+	}
+)
+
+// ExportJournalHandlerFunc /export/:format
+func ExportJournalHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	switch r.Method {
+
+	case http.MethodGet:
+		formatProvided := strings.Split(r.URL.Path, "/export/")[1]
+		if !services.JournalFormatIsValid(formatProvided) {
+			writeResponseWithError(w, http.StatusBadRequest, badRequest)
+			return
+		}
+
+		tsesRepo, err := ns.GetTransEntRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		csRepo, err := ns.GetCategoriesRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		repos, err := ns.GetAllRepos()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		format := services.JournalFormat(formatProvided)
+		journal, err := services.ExportJournal(tsesRepo, csRepo, *repos, format)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="daily-expenses.%s"`,
+			journalsExtensions[format]))
+		w.WriteHeader(http.StatusOK)
+		if _, err = io.WriteString(w, journal); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, journal); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
+
+// ImportBeancountHandlerFunc /import/beancount?strategy=skip|overwrite|renumber
+func ImportBeancountHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	switch r.Method {
+
+	case http.MethodPost:
+		strategyProvided := r.URL.Query().Get("strategy")
+		if strategyProvided == "" {
+			strategyProvided = string(services.SkipImportStrategy)
+		}
+		if !services.ImportStrategyIsValid(strategyProvided) {
+			writeResponseWithError(w, http.StatusBadRequest, badRequest)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, int64(services.MaxJournalSize))
+		journal, err := io.ReadAll(r.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				writeResponseWithDetailedError(w, http.StatusRequestEntityTooLarge, requestEntityTooLarge,
+					fmt.Errorf("the journal is larger than the max of %d bytes", services.MaxJournalSize))
+				return
+			}
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		csRepo, err := ns.GetCategoriesRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		psRepo, err := ns.GetPayeesRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		repos, err := ns.GetAllRepos()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		backup, err := services.ImportBeancount(csRepo, psRepo, *repos, string(journal))
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		report, err := services.ImportBackup(ns, *backup, services.ImportStrategy(strategyProvided))
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(report); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, report); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+func recordDueLoansInstallments(ns *repositories.Namespace) error {
+	lsRepo, err := ns.GetLoansRepo()
+	if err != nil {
+		return err
+	}
+	liRepo, err := ns.GetLoansInstallmentsRepo()
+	if err != nil {
+		return err
+	}
+	_, err = services.RecordDueInstallments(lsRepo, liRepo, time.Now().UTC().Truncate(24*time.Hour))
+	return err
+}
+
+// LoansHandlerFunc /loans
+func LoansHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	lsRepo, err := ns.GetLoansRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+	liRepo, err := ns.GetLoansInstallmentsRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+
+	switch r.Method {
+
+	case http.MethodGet:
+		lsDTO, err := services.GetLoans(lsRepo, liRepo, time.Now().UTC().Truncate(24*time.Hour))
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(lsDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, lsDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodPost:
+		nlDTO := services.LoanDTO{}
+		if err = json.NewDecoder(r.Body).Decode(&nlDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		nl, err := nlDTO.NewLoan(ns)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		newID, err := services.AddLoan(lsRepo, nl)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = recordDueLoansInstallments(ns); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		nl, err = services.GetLoanByID(lsRepo, newID)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		nlDTO, err = services.GetLoanDetails(liRepo, nl, time.Now().UTC().Truncate(24*time.Hour))
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err = json.NewEncoder(w).Encode(nlDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, created, nlDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
+
+// LoanHandlerFunc /loans/:loan_id
+func LoanHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	lIDStr := strings.Split(r.URL.Path, "/loans/")[1]
+	lID, err := strconv.Atoi(lIDStr)
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+		return
+	}
+
+	lsRepo, err := ns.GetLoansRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+	liRepo, err := ns.GetLoansInstallmentsRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+
+	if r.Method != http.MethodOptions {
+		if _, err = services.GetLoanByID(lsRepo, lID); err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+	}
+
+	switch r.Method {
+
+	case http.MethodGet:
+		l, err := services.GetLoanByID(lsRepo, lID)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		lDTO, err := services.GetLoanDetails(liRepo, l, time.Now().UTC().Truncate(24*time.Hour))
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(lDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, lDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodDelete:
+		if err = services.DeleteLoan(lsRepo, liRepo, lID); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, DELETE")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
+
+// LoansInterestReportHandlerFunc /reports/loans?year=
+func LoansInterestReportHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	switch r.Method {
+
+	case http.MethodGet:
+		year := 0
+		if yearProvided := strings.Trim(r.URL.Query().Get("year"), " "); yearProvided != "" {
+			var err error
+			if year, err = strconv.Atoi(yearProvided); err != nil || year <= 0 {
+				writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest,
+					fmt.Errorf("the value %q for year is not valid", yearProvided))
+				return
+			}
+		}
+
+		lsRepo, err := ns.GetLoansRepo()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		report, err := services.GetLoansInterestReport(lsRepo, year, time.Now().UTC().Truncate(24*time.Hour))
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(report); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, report); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
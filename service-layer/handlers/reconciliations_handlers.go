@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+const (
+	finishReconciliationAction = "finish"
+)
+
+// TransactionStatusHandlerFunc /transactions/:transaction_id/status
+func TransactionStatusHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 3 || parts[2] != "status" {
+		writeResponseWithError(w, http.StatusNotFound, notFound)
+		return
+	}
+	tID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+		return
+	}
+
+	switch r.Method {
+
+	case http.MethodPut:
+		entityProvided := r.URL.Query().Get("entity")
+		typeProvided := r.URL.Query().Get("type")
+		if !entityIsValid(entityProvided) || !kindIsValid(typeProvided) {
+			writeResponseWithError(w, http.StatusBadRequest, badRequest)
+			return
+		}
+
+		sDTO := services.TransactionStatusDTO{}
+		if err = json.NewDecoder(r.Body).Decode(&sDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		repo, err := ns.GetTransRepo(typeProvided, entityProvided)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		existing, err := services.GetTransactionByID(repo, tID)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+		if existing.Status == models.ReconciledTransactionStatus && !sDTO.Unlock {
+			writeResponseWithDetailedError(w, http.StatusConflict, conflict,
+				fmt.Errorf("the transaction %d is reconciled and locked, unless unlocked", tID))
+			return
+		}
+
+		t, err := services.SetTransactionStatus(repo, tID, sDTO)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		sDTO = services.TransactionStatusDTO{Status: t.Status}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(sDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, sDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "PUT")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
+
+// reconciliationsHandlerFunc /entities/:entity_id/reconciliations[/:reconciliation_id[/finish]]
+func reconciliationsHandlerFunc(w http.ResponseWriter, r *http.Request, tse models.TransactionsEntity,
+	pathParts []string) {
+	ns := requestNamespace(r)
+	if len(pathParts) > 2 || len(pathParts) == 2 && pathParts[1] != finishReconciliationAction {
+		writeResponseWithError(w, http.StatusNotFound, notFound)
+		return
+	}
+	var err error
+	rID := 0
+	if len(pathParts) > 0 {
+		if rID, err = strconv.Atoi(pathParts[0]); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+	}
+	finish := len(pathParts) == 2
+
+	repo, err := ns.GetReconciliationsRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+	tsRepo, err := ns.GetTransRepo(tse.Kind, tse.Entity)
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+	rec := models.Reconciliation{}
+	if rID != 0 && r.Method != http.MethodOptions {
+		if rec, err = services.GetReconciliationByID(repo, tsRepo, rID); err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+	}
+
+	switch {
+
+	case r.Method == http.MethodGet && rID == 0:
+		rs, err := services.GetReconciliations(repo, tsRepo)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		rsDTO := services.ReconciliationsDTO{}
+		for _, rec := range rs {
+			rsDTO = append(rsDTO, services.NewReconciliationDTOFrom(rec))
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(rsDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, rsDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodGet && !finish:
+		rDTO, err := services.GetReconciliationDetails(tsRepo, rec)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(rDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, rDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodPost && rID == 0:
+		nrDTO := services.NewReconciliationDTO{}
+		if err = json.NewDecoder(r.Body).Decode(&nrDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		rec, err = services.StartReconciliation(repo, tsRepo, nrDTO)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		rDTO, err := services.GetReconciliationDetails(tsRepo, rec)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err = json.NewEncoder(w).Encode(rDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, created, rDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodPost && finish:
+		if rec.Status != models.OpenReconciliationStatus {
+			writeResponseWithDetailedError(w, http.StatusConflict, conflict,
+				fmt.Errorf("the reconciliation %d isn't open", rID))
+			return
+		}
+		if rec, err = services.FinishReconciliation(repo, tsRepo, rec); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		rDTO := services.NewReconciliationDTOFrom(rec)
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(rDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, rDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodDelete && rID != 0 && !finish:
+		if rec.Status != models.OpenReconciliationStatus {
+			writeResponseWithDetailedError(w, http.StatusConflict, conflict,
+				fmt.Errorf("the reconciliation %d isn't open", rID))
+			return
+		}
+		if err = services.CancelReconciliation(repo, rec); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case r.Method == http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		switch {
+		case rID == 0:
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+		case finish:
+			w.Header().Set("Access-Control-Allow-Methods", "POST")
+		default:
+			w.Header().Set("Access-Control-Allow-Methods", "GET, DELETE")
+		}
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
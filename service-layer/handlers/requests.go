@@ -5,6 +5,17 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+var (
+	transactionsQueryParams = []string{
+		"from", "to", "min_amount", "max_amount", "q", "kind", "status", "categories", "tags", "field", "sort", "limit", "cursor",
+	}
 )
 
 func debugRequest(r *http.Request) {
@@ -27,3 +38,28 @@ func debugRequest(r *http.Request) {
 	fmt.Printf("Body: %s\n", bodyBytes)
 	os.Exit(0)
 }
+
+func dateQueryParam(r *http.Request, name string) (time.Time, error) {
+	value := strings.Trim(r.URL.Query().Get(name), " ")
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(utils.DateFormat, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("the value %q for %s is not a valid date", value, name)
+	}
+	return date, nil
+}
+
+func amountQueryParam(r *http.Request, name string) (*float32, error) {
+	value := strings.Trim(r.URL.Query().Get(name), " ")
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		return nil, fmt.Errorf("the value %q for %s is not a valid amount", value, name)
+	}
+	a := float32(amount)
+	return &a, nil
+}
@@ -1,19 +1,32 @@
 package handlers
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"net/http"
 	"reflect"
+	"strings"
+
+	"github.com/h-abranches-dev/daily-expenses-be/xlsx"
 )
 
 const (
-	ok                  = "200 OK"
-	created             = "201 Created"
-	noContent           = "204 No Content"
-	badRequest          = "404 Bad Request"
-	methodNotAllowed    = "405 Method Not Allowed"
-	internalServerError = "500 Internal Server Error"
+	ok                    = "200 OK"
+	created               = "201 Created"
+	noContent             = "204 No Content"
+	badRequest            = "404 Bad Request"
+	unauthorized          = "401 Unauthorized"
+	forbidden             = "403 Forbidden"
+	notFound              = "404 Not Found"
+	methodNotAllowed      = "405 Method Not Allowed"
+	conflict              = "409 Conflict"
+	requestEntityTooLarge = "413 Request Entity Too Large"
+	unsupportedMediaType  = "415 Unsupported Media Type"
+	tooManyRequests       = "429 Too Many Requests"
+	internalServerError   = "500 Internal Server Error"
+
+	xlsxFormat = "xlsx"
 )
 
 func logResponse(reqMethod, reqPath, reqQuery, respStatus string, resp any) error {
@@ -50,3 +63,28 @@ func writeResponseWithDetailedError(w http.ResponseWriter, respStatusCode int, r
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	http.Error(w, err.Error(), respStatusCode)
 }
+
+func spreadsheetRequested(r *http.Request) bool {
+	return r.URL.Query().Get("format") == xlsxFormat || strings.Contains(r.Header.Get("Accept"), xlsx.ContentType)
+}
+
+func writeWorkbookResponse(w http.ResponseWriter, r *http.Request, fileName string, wb *xlsx.Workbook) {
+	buf := new(bytes.Buffer)
+	if err := wb.Write(buf); err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", xlsx.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, fileName, xlsxFormat))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		logDetailedError(err)
+		return
+	}
+	if err := logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, fileName); err != nil {
+		logDetailedError(err)
+		return
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+// SearchHandlerFunc /search?q=&limit=
+func SearchHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	switch r.Method {
+
+	case http.MethodGet:
+		queryProvided := strings.Trim(r.URL.Query().Get("q"), " ")
+		if queryProvided == "" {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, fmt.Errorf("the q parameter is mandatory"))
+			return
+		}
+		limit := services.DefaultSearchLimit
+		if limitProvided := strings.Trim(r.URL.Query().Get("limit"), " "); limitProvided != "" {
+			var err error
+			if limit, err = strconv.Atoi(limitProvided); err != nil || limit <= 0 || limit > services.MaxQueryLimit {
+				writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest,
+					fmt.Errorf("the value %q for limit is not valid", limitProvided))
+				return
+			}
+		}
+
+		repos, err := ns.GetAllRepos()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		results, err := services.SearchTransactions(ns, *repos, queryProvided, limit)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(results); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, results); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
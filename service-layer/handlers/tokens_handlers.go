@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+// TokensHandlerFunc /tokens
+func TokensHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	repo, err := repositories.GetTokensRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+	usersRepo, err := repositories.GetUsersRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+
+	switch r.Method {
+
+	case http.MethodGet:
+		ts, err := services.GetAllTokens(repo)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if u := requestAuthentication(r).user; !u.ServerAdmin {
+			ts = slices.DeleteFunc(ts, func(t models.Token) bool { return t.UserID != u.ID })
+		}
+		tsDTO, err := services.NewTokensDTO(usersRepo, ts)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(tsDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, tsDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodPost:
+		ntDTO := services.TokenDTO{}
+		if err = json.NewDecoder(r.Body).Decode(&ntDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		u := requestAuthentication(r).user
+		nt, err := ntDTO.NewToken(usersRepo, u, time.Now().UTC().Truncate(time.Second))
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		if nt.UserID != u.ID && !u.ServerAdmin {
+			writeResponseWithDetailedError(w, http.StatusForbidden, forbidden,
+				fmt.Errorf("the user %q can't create tokens of other users", u.Name))
+			return
+		}
+
+		nt, token, err := services.AddToken(repo, nt)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		ntDTO, err = services.NewTokenDTO(usersRepo, nt)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		ntDTO.Token = token
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err = json.NewEncoder(w).Encode(ntDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		ntDTO.Token = ""
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, created, ntDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
+
+// TokenHandlerFunc /tokens/:token_id
+func TokenHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	tIDStr := strings.Split(r.URL.Path, "/tokens/")[1]
+	tID, err := strconv.Atoi(tIDStr)
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+		return
+	}
+
+	repo, err := repositories.GetTokensRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+
+	if r.Method != http.MethodOptions {
+		t, err := services.GetTokenByID(repo, tID)
+		if u := requestAuthentication(r).user; err == nil && t.UserID != u.ID && !u.ServerAdmin {
+			err = fmt.Errorf("token %d not found", tID)
+		}
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+	}
+
+	switch r.Method {
+
+	case http.MethodDelete:
+		if err = services.DeleteToken(repo, tID); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "DELETE")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
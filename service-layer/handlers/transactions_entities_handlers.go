@@ -3,18 +3,19 @@ package handlers
 import (
 	"encoding/json"
 	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
-	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
 	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 // TransactionsEntitiesHandlerFunc /entities
 func TransactionsEntitiesHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
 	switch r.Method {
 
 	case http.MethodGet:
-		repo, err := repositories.GetTransEntRepo()
+		repo, err := ns.GetTransEntRepo()
 		if err != nil {
 			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 			return
@@ -24,8 +25,13 @@ func TransactionsEntitiesHandlerFunc(w http.ResponseWriter, r *http.Request) {
 			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 			return
 		}
+		visibleTses, err := visibleTransactionsEntities(r, *tses)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
 
-		tsesDTO := services.NewTransactionsEntitiesDTO(*tses)
+		tsesDTO := services.NewTransactionsEntitiesDTO(visibleTses)
 
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Content-Type", "application/json")
@@ -52,7 +58,7 @@ func TransactionsEntitiesHandlerFunc(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		repo, err := repositories.GetTransEntRepo()
+		repo, err := ns.GetTransEntRepo()
 		if err != nil {
 			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 			return
@@ -64,6 +70,23 @@ func TransactionsEntitiesHandlerFunc(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if tsRepo, err := ns.GetTransRepo(ntse.Kind, ntse.Entity); err == nil && ntse.Balance != 0 {
+			besRepo, err := ns.GetBalanceEntriesRepo()
+			if err != nil {
+				writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+				return
+			}
+			obeDTO := services.BalanceEntryDTO{
+				Type:   models.OpeningBalanceEntry,
+				Date:   ntseDTO.OpeningDate,
+				Amount: ntse.Balance,
+			}
+			if _, err = services.AddBalanceEntry(besRepo, repo, tsRepo, obeDTO); err != nil {
+				writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+				return
+			}
+		}
+
 		ntseDTO.ID = strconv.Itoa(newID)
 
 		w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -82,3 +105,102 @@ func TransactionsEntitiesHandlerFunc(w http.ResponseWriter, r *http.Request) {
 		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
 	}
 }
+
+// TransactionsEntityHandlerFunc /entities/:entity_id/...
+func TransactionsEntityHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	pathParts := strings.Split(strings.Trim(strings.Split(r.URL.Path, "/entities/")[1], "/"), "/")
+	tseID, err := strconv.Atoi(pathParts[0])
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+		return
+	}
+
+	tsesRepo, err := ns.GetTransEntRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+	tse, err := services.GetTransactionsEntityByID(tsesRepo, tseID)
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+		return
+	}
+
+	resource := ""
+	if len(pathParts) > 1 {
+		resource = pathParts[1]
+	}
+	switch resource {
+	case "balance-history":
+		balanceHistoryHandlerFunc(w, r, tse)
+	case "fields":
+		customFieldsHandlerFunc(w, r, tse, pathParts[2:])
+	case "reconciliations":
+		reconciliationsHandlerFunc(w, r, tse, pathParts[2:])
+	case "balance-entries":
+		balanceEntriesHandlerFunc(w, r, tse, pathParts[2:])
+	case "card":
+		creditCardHandlerFunc(w, r, tse, pathParts[2:])
+	case "statements":
+		statementsHandlerFunc(w, r, tse, pathParts[2:])
+	case "grants":
+		grantsHandlerFunc(w, r, tse, pathParts[2:])
+	default:
+		writeResponseWithError(w, http.StatusNotFound, notFound)
+	}
+}
+
+// balanceHistoryHandlerFunc /entities/:entity_id/balance-history?from=&to=&step=day|month
+func balanceHistoryHandlerFunc(w http.ResponseWriter, r *http.Request, tse models.TransactionsEntity) {
+	ns := requestNamespace(r)
+	switch r.Method {
+
+	case http.MethodGet:
+		stepProvided := r.URL.Query().Get("step")
+		if stepProvided == "" {
+			stepProvided = string(services.DayStep)
+		}
+		if !services.StepIsValid(stepProvided) {
+			writeResponseWithError(w, http.StatusBadRequest, badRequest)
+			return
+		}
+		from, err := dateQueryParam(r, "from")
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		to, err := dateQueryParam(r, "to")
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		tsRepo, err := ns.GetTransRepo(tse.Kind, tse.Entity)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		history, err := services.GetBalanceHistory(tse, tsRepo, from, to, services.Step(stepProvided))
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(history); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, history); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
 	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
 	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
@@ -12,33 +13,54 @@ import (
 
 // TransactionHandlerFunc /transactions
 func TransactionHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
 	switch r.Method {
 
 	case http.MethodGet:
 		entityProvided := r.URL.Query().Get("entity")
 		typeProvided := r.URL.Query().Get("type")
-		categoriesProvided := r.URL.Query().Get("categories")
 
 		if entityProvided != "" && !entityIsValid(entityProvided) || typeProvided != "" && !kindIsValid(typeProvided) {
 			writeResponseWithError(w, http.StatusBadRequest, badRequest)
 			return
 		}
 
-		if entityProvided == "" && typeProvided == "" && categoriesProvided != "" {
-			categoriesProvidedSlc := strings.Split(categoriesProvided, ",")
+		if spreadsheetRequested(r) {
+			q, err := newTransactionsQuery(r)
+			if err != nil {
+				writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+				return
+			}
+
+			etss, err := queriedEntitiesTransactions(r, entityProvided, typeProvided)
+			if err != nil {
+				writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+				return
+			}
+
+			if etss, err = services.QueryEntitiesTransactions(etss, q); err != nil {
+				writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+				return
+			}
 
-			repos, err := repositories.GetAllRepos()
+			writeWorkbookResponse(w, r, "transactions", services.NewTransactionsWorkbook(etss))
+			return
+		}
+
+		if entityProvided == "" || typeProvided == "" || transactionsQueryRequested(r) {
+			q, err := newTransactionsQuery(r)
 			if err != nil {
-				writeResponseWithError(w, http.StatusInternalServerError, internalServerError)
+				writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
 				return
 			}
-			allTransactions, err := services.GetAllTransactions(*repos, false)
+
+			etss, err := queriedEntitiesTransactions(r, entityProvided, typeProvided)
 			if err != nil {
 				writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 				return
 			}
 
-			transactionsFilteredByCategories, err := services.GetTransactionsFilteredByCategories(allTransactions, categoriesProvidedSlc)
+			tsDTO, err := services.QueryTransactions(ns, etss, q)
 			if err != nil {
 				writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 				return
@@ -47,11 +69,11 @@ func TransactionHandlerFunc(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
-			if err = json.NewEncoder(w).Encode(transactionsFilteredByCategories); err != nil {
+			if err = json.NewEncoder(w).Encode(tsDTO); err != nil {
 				writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 				return
 			}
-			if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, transactionsFilteredByCategories); err != nil {
+			if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, tsDTO); err != nil {
 				logDetailedError(err)
 				return
 			}
@@ -59,7 +81,7 @@ func TransactionHandlerFunc(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		repo, err := repositories.GetTransRepo(typeProvided, entityProvided)
+		repo, err := ns.GetTransRepo(typeProvided, entityProvided)
 		if err != nil {
 			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 			return
@@ -71,12 +93,29 @@ func TransactionHandlerFunc(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		tsDTO, err := services.NewTransactionsDTO(*ts)
+		tsDTO, err := services.NewTransactionsDTO(ns, *ts)
 		if err != nil {
 			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 			return
 		}
 
+		if r.URL.Query().Get("running_balance") == "true" {
+			tsesRepo, err := ns.GetTransEntRepo()
+			if err != nil {
+				writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+				return
+			}
+			tse, err := services.GetTransactionsEntityOfRepo(tsesRepo, repo)
+			if err != nil {
+				writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+				return
+			}
+			if err = services.SetRunningBalances(ns, tse, *ts, &tsDTO); err != nil {
+				writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+				return
+			}
+		}
+
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -104,17 +143,33 @@ func TransactionHandlerFunc(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		nt, err := ntDTO.NewTransaction()
+		nt, err := ntDTO.NewTransaction(ns, typeProvided)
 		if err != nil {
-			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		if err = services.CheckTransactionCategories(ns, nt); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		if err = services.CheckTransactionStatus(nt); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
 			return
 		}
 
-		repo, err := repositories.GetTransRepo(typeProvided, entityProvided)
+		repo, err := ns.GetTransRepo(typeProvided, entityProvided)
 		if err != nil {
 			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 			return
 		}
+		if err = services.CheckTransactionFields(repo, &nt); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		if err = normalizeTransactionDTO(ns, &ntDTO, nt); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
 
 		newID, err := services.AddTransaction(repo, nt)
 		if err != nil {
@@ -165,6 +220,20 @@ func TransactionsTypesHandlerFunc(w http.ResponseWriter, r *http.Request) {
 
 // UpdateTransactionHandlerFunc /transactions/:transaction_id
 func UpdateTransactionHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
+	if pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/"); len(pathParts) > 2 {
+		if pathParts[2] == "status" {
+			TransactionStatusHandlerFunc(w, r)
+		} else if pathParts[2] == "share" {
+			TransactionShareHandlerFunc(w, r)
+		} else if pathParts[2] == "history" {
+			TransactionHistoryHandlerFunc(w, r)
+		} else {
+			TransactionAttachmentsHandlerFunc(w, r)
+		}
+		return
+	}
+
 	switch r.Method {
 
 	case http.MethodPut:
@@ -190,19 +259,48 @@ func UpdateTransactionHandlerFunc(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		t, err := tDTO.NewTransaction()
+		t, err := tDTO.NewTransaction(ns, typeProvided)
 		if err != nil {
 			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
 			return
 		}
+		if err = services.CheckTransactionCategories(ns, t); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		if err = services.CheckTransactionStatus(t); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
 
 		t.ID = tID
 
-		repo, err := repositories.GetTransRepo(typeProvided, entityProvided)
+		repo, err := ns.GetTransRepo(typeProvided, entityProvided)
 		if err != nil {
 			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 			return
 		}
+		existing, err := services.GetTransactionByID(repo, tID)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+		if existing.Status == models.ReconciledTransactionStatus {
+			writeResponseWithDetailedError(w, http.StatusConflict, conflict,
+				fmt.Errorf("the transaction %d is reconciled and locked", tID))
+			return
+		}
+		if t.Status == "" {
+			t.Status = existing.Status
+		}
+		if err = services.CheckTransactionFields(repo, &t); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		if err = normalizeTransactionDTO(ns, &tDTO, t); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
 
 		err = services.UpdateTransaction(repo, t)
 		if err != nil {
@@ -223,9 +321,52 @@ func UpdateTransactionHandlerFunc(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 
+	case http.MethodDelete:
+		tID, err := strconv.Atoi(strings.Split(r.URL.Path, "/transactions/")[1])
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		entityProvided := r.URL.Query().Get("entity")
+		typeProvided := r.URL.Query().Get("type")
+		if !entityIsValid(entityProvided) || !kindIsValid(typeProvided) {
+			writeResponseWithError(w, http.StatusBadRequest, badRequest)
+			return
+		}
+
+		repo, err := ns.GetTransRepo(typeProvided, entityProvided)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		existing, err := services.GetTransactionByID(repo, tID)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusNotFound, notFound, err)
+			return
+		}
+		if existing.Status == models.ReconciledTransactionStatus {
+			writeResponseWithDetailedError(w, http.StatusConflict, conflict,
+				fmt.Errorf("the transaction %d is reconciled and locked", tID))
+			return
+		}
+
+		if err = services.DeleteTransaction(repo, tID); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+		return
+
 	case http.MethodOptions:
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "PUT")
+		w.Header().Set("Access-Control-Allow-Methods", "PUT, DELETE")
 		w.WriteHeader(http.StatusNoContent)
 		if err := logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
 			logDetailedError(err)
@@ -241,16 +382,23 @@ func UpdateTransactionHandlerFunc(w http.ResponseWriter, r *http.Request) {
 
 // TransactionsCategoriesHandlerFunc /transactions/categories
 func TransactionsCategoriesHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	ns := requestNamespace(r)
 	switch r.Method {
 
 	case http.MethodGet:
-		repos, err := repositories.GetAllRepos()
+		repos, err := ns.GetAllRepos()
 		if err != nil {
 			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 			return
 		}
 
-		allTransactions, err := services.GetAllTransactions(*repos, false)
+		visible, err := visibleRepos(r, *repos)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		allTransactions, err := services.GetAllTransactions(visible, false)
 		if err != nil {
 			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
 			return
@@ -258,6 +406,11 @@ func TransactionsCategoriesHandlerFunc(w http.ResponseWriter, r *http.Request) {
 
 		m := services.GetCategoriesLabelsInTransactionsMap(allTransactions)
 
+		if spreadsheetRequested(r) {
+			writeWorkbookResponse(w, r, "categories", services.NewCategoriesCountsWorkbook(m))
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		if err = json.NewEncoder(w).Encode(m); err != nil {
@@ -274,6 +427,99 @@ func TransactionsCategoriesHandlerFunc(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func normalizeTransactionDTO(ns *repositories.Namespace, tDTO *services.TransactionDTO, t models.Transaction) error {
+	var err error
+	tDTO.Kind, tDTO.Amount, tDTO.Tags, tDTO.Memo = t.Kind, t.Amount, t.Tags, t.Memo
+	if tDTO.Status = t.Status; tDTO.Status == "" {
+		tDTO.Status = models.PendingTransactionStatus
+	}
+	tDTO.Fields = services.NewFieldsDTO(t.Fields)
+	if tDTO.Payee, err = services.PayeeName(ns, t); err != nil {
+		return err
+	}
+	if len(t.Splits) != 0 || len(tDTO.Categories) != len(t.Categories) {
+		tDTO.Categories = nil
+		for _, c := range t.Categories {
+			tDTO.Categories = append(tDTO.Categories, c.Label)
+		}
+	}
+	return nil
+}
+
+func queriedEntitiesTransactions(r *http.Request, entityProvided, typeProvided string) ([]services.EntityTransactions, error) {
+	ns := requestNamespace(r)
+	repos, err := ns.GetAllRepos()
+	if err != nil {
+		return nil, err
+	}
+	var queriedRepos []*repositories.TransactionsRepo
+	for _, repo := range *repos {
+		if (entityProvided == "" || repo.Entity == entityProvided) && (typeProvided == "" || repo.Kind == typeProvided) {
+			queriedRepos = append(queriedRepos, repo)
+		}
+	}
+	if queriedRepos, err = visibleRepos(r, queriedRepos); err != nil {
+		return nil, err
+	}
+
+	return services.GetEntitiesTransactions(queriedRepos, nil)
+}
+
+func transactionsQueryRequested(r *http.Request) bool {
+	for _, name := range transactionsQueryParams {
+		if r.URL.Query().Has(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func newTransactionsQuery(r *http.Request) (services.TransactionsQuery, error) {
+	var err error
+	q := services.TransactionsQuery{
+		Text:   r.URL.Query().Get("q"),
+		Kind:   r.URL.Query().Get("kind"),
+		Status: r.URL.Query().Get("status"),
+		Sort:   r.URL.Query().Get("sort"),
+		Cursor: r.URL.Query().Get("cursor"),
+	}
+	if categoriesProvided := r.URL.Query().Get("categories"); categoriesProvided != "" {
+		if q.Categories, err = services.ParseCategoriesFilter(categoriesProvided); err != nil {
+			return q, err
+		}
+	}
+	if tagsProvided := r.URL.Query().Get("tags"); tagsProvided != "" {
+		if q.Tags, err = services.ParseTagsFilter(tagsProvided); err != nil {
+			return q, err
+		}
+	}
+	for _, fieldProvided := range r.URL.Query()["field"] {
+		fc, err := services.ParseFieldCondition(fieldProvided)
+		if err != nil {
+			return q, err
+		}
+		q.Fields = append(q.Fields, fc)
+	}
+	if q.From, err = dateQueryParam(r, "from"); err != nil {
+		return q, err
+	}
+	if q.To, err = dateQueryParam(r, "to"); err != nil {
+		return q, err
+	}
+	if q.MinAmount, err = amountQueryParam(r, "min_amount"); err != nil {
+		return q, err
+	}
+	if q.MaxAmount, err = amountQueryParam(r, "max_amount"); err != nil {
+		return q, err
+	}
+	if limit := strings.Trim(r.URL.Query().Get("limit"), " "); limit != "" {
+		if q.Limit, err = strconv.Atoi(limit); err != nil || q.Limit <= 0 {
+			return q, fmt.Errorf("the value %q for limit is not valid", limit)
+		}
+	}
+	return q, q.Validate()
+}
+
 func entityIsValid(entityProvided string) bool {
 	for _, entity := range models.TransactionEntities {
 		if string(entity) == entityProvided {
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	services "github.com/h-abranches-dev/daily-expenses-be/service-layer"
+)
+
+// UsersHandlerFunc /users
+func UsersHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	repo, err := repositories.GetUsersRepo()
+	if err != nil {
+		writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+		return
+	}
+
+	switch r.Method {
+
+	case http.MethodGet:
+		us, err := services.GetAllUsers(repo)
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+
+		usDTO := services.NewUsersDTO(us)
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(usDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, ok, usDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodPost:
+		nuDTO := services.UserDTO{}
+		if err = json.NewDecoder(r.Body).Decode(&nuDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+
+		nu, err := nuDTO.NewUser()
+		if err != nil {
+			writeResponseWithDetailedError(w, http.StatusBadRequest, badRequest, err)
+			return
+		}
+		if _, err = services.GetUserByName(repo, nu.Name); err == nil {
+			writeResponseWithDetailedError(w, http.StatusConflict, conflict,
+				fmt.Errorf("the user %q already exists", nu.Name))
+			return
+		}
+
+		if nu.ID, err = services.AddUser(repo, nu); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if nuDTO.Password != "" {
+			credentialsRepo, err := repositories.GetCredentialsRepo()
+			if err != nil {
+				writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+				return
+			}
+			if err = services.SetPassword(credentialsRepo, nu.ID, nuDTO.Password, nuDTO.Scopes); err != nil {
+				writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+				return
+			}
+		}
+		nuDTO = services.NewUserDTO(nu)
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err = json.NewEncoder(w).Encode(nuDTO); err != nil {
+			writeResponseWithDetailedError(w, http.StatusInternalServerError, internalServerError, err)
+			return
+		}
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, created, nuDTO); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+		w.WriteHeader(http.StatusNoContent)
+		if err = logResponse(r.Method, r.URL.Path, r.URL.RawQuery, noContent, ""); err != nil {
+			logDetailedError(err)
+			return
+		}
+
+	default:
+		writeResponseWithError(w, http.StatusMethodNotAllowed, methodNotAllowed)
+	}
+}
@@ -0,0 +1,636 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+const (
+	LedgerJournalFormat    JournalFormat = "ledger"
+	HledgerJournalFormat   JournalFormat = "hledger"
+	BeancountJournalFormat JournalFormat = "beancount"
+
+	JournalCommodity string = "EUR"
+	MaxJournalSize   int    = 64 << 20
+
+	assetsAccountsRoot           = "Assets"
+	liabilitiesAccountsRoot      = "Liabilities"
+	expensesAccountsRoot         = "Expenses"
+	incomeAccountsRoot           = "Income"
+	openingBalancesAccount       = "Equity:Opening-Balances"
+	uncategorizedAccountName     = "Uncategorized"
+	beancountDateFormat          = "2006-01-02"
+	ledgerDateFormat             = "2006/01/02"
+	journalAmountsEqualTolerance = 0.005
+	journalFieldPrefix           = "field-"
+)
+
+// JournalFormat is a plain-text accounting file format.
+type JournalFormat string
+
+var (
+	JournalFormats = []JournalFormat{
+		LedgerJournalFormat, HledgerJournalFormat, BeancountJournalFormat,
+	}
+
+	beancountTransactionRegexp = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\s+[*!]\s+"((?:[^"\\]|\\.)*)"\s*$`)
+	beancountMetadataRegexp    = regexp.MustCompile(`^\s+([a-z][a-zA-Z0-9_-]*):\s+"((?:[^"\\]|\\.)*)"\s*$`)
+	beancountPostingRegexp     = regexp.MustCompile(`^\s+([A-Z][^\s]*)\s+(-?\d+(?:\.\d+)?)\s+([A-Z][A-Z0-9'._-]*)\s*$`)
+)
+
+type journalTransaction struct {
+	entity      string
+	kind        string
+	transaction models.Transaction
+}
+
+func JournalFormatIsValid(format string) bool {
+	for _, f := range JournalFormats {
+		if string(f) == format {
+			return true
+		}
+	}
+	return false
+}
+
+func journalAccountName(label string) string {
+	words := strings.FieldsFunc(label, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return uncategorizedAccountName
+	}
+	for i, w := range words {
+		rs := []rune(strings.ToLower(w))
+		rs[0] = unicode.ToUpper(rs[0])
+		words[i] = string(rs)
+	}
+	return strings.Join(words, "-")
+}
+
+func categoriesJournalAccountNames(labels []string) map[string]string {
+	sorted := append([]string{""}, labels...)
+	sort.Strings(sorted)
+	names := make(map[string]string)
+	taken := make(map[string]bool)
+	for _, label := range sorted {
+		if _, found := names[label]; found {
+			continue
+		}
+		name := journalAccountName(label)
+		for n := 2; taken[name]; n++ {
+			name = fmt.Sprintf("%s-%d", journalAccountName(label), n)
+		}
+		taken[name] = true
+		names[label] = name
+	}
+	return names
+}
+
+func entityJournalAccount(entity, kind string) string {
+	root := assetsAccountsRoot
+	if models.TransactionKind(kind) == models.DebitCreditBankAccountKind {
+		root = liabilitiesAccountsRoot
+	}
+	return fmt.Sprintf("%s:%s", root, journalAccountName(entity))
+}
+
+func journalAmount(t models.Transaction) float32 {
+	return signedAmount(t)
+}
+
+func journalQuote(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`)
+}
+
+func journalUnquote(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, `\"`, `"`), `\\`, `\`)
+}
+
+func categoriesJournalAccounts(jts []journalTransaction, labels []string) map[string]string {
+	sums := make(map[string]float32)
+	for _, jt := range jts {
+		for _, ca := range categoriesJournalAmounts(jt.transaction) {
+			sums[ca.label] += ca.amount
+		}
+	}
+	for label := range sums {
+		labels = append(labels, label)
+	}
+	names := categoriesJournalAccountNames(labels)
+	accounts := make(map[string]string)
+	for label, sum := range sums {
+		root := expensesAccountsRoot
+		if sum > 0 {
+			root = incomeAccountsRoot
+		}
+		accounts[label] = fmt.Sprintf("%s:%s", root, names[label])
+	}
+	return accounts
+}
+
+func categoryJournalLabel(t models.Transaction) string {
+	if len(t.Categories) == 0 {
+		return ""
+	}
+	return t.Categories[0].Label
+}
+
+func categoriesJournalAmounts(t models.Transaction) []categoryAmount {
+	if len(t.Splits) != 0 {
+		return categoriesAmounts(t)
+	}
+	return []categoryAmount{{label: categoryJournalLabel(t), amount: journalAmount(t)}}
+}
+
+func journalAccountCategory(names map[string]string, account string) string {
+	accountName := account[strings.LastIndex(account, ":")+1:]
+	for label, name := range names {
+		if label != "" && name == accountName {
+			return label
+		}
+	}
+	return ""
+}
+
+func journalAccountLabel(names map[string]string, account string) string {
+	if label := journalAccountCategory(names, account); label != "" {
+		return label
+	}
+	accountName := account[strings.LastIndex(account, ":")+1:]
+	if accountName == uncategorizedAccountName {
+		return ""
+	}
+	return strings.ToUpper(accountName)
+}
+
+func categoriesLabels(cs models.Categories) []string {
+	labels := make([]string, 0, len(cs))
+	for _, c := range cs {
+		labels = append(labels, c.Label)
+	}
+	return labels
+}
+
+func formatJournalAmount(amount float32) string {
+	return fmt.Sprintf("%.2f %s", amount, JournalCommodity)
+}
+
+func ExportJournal(tsesRepo *repositories.TransactionsEntitiesRepo, csRepo *repositories.CategoriesRepo,
+	tsRepos []*repositories.TransactionsRepo, format JournalFormat) (string, error) {
+	if tsesRepo == nil {
+		return "", fmt.Errorf("transactions entities repo wasn't initialized")
+	}
+	cs, err := GetAllCategories(csRepo, models.RefToCategories)
+	if err != nil {
+		return "", err
+	}
+
+	dateFormat := beancountDateFormat
+	if format == LedgerJournalFormat {
+		dateFormat = ledgerDateFormat
+	}
+
+	var jts []journalTransaction
+	balances := make(map[string]float32)
+	lastDates := make(map[string]time.Time)
+	storedBalances := make(map[string]float32)
+	balanceEntries := make(map[string]models.BalanceEntries)
+	openDate := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, tsRepo := range tsRepos {
+		ts, err := GetAllTransactionsByRepo(tsRepo, false)
+		if err != nil {
+			return "", err
+		}
+		account := entityJournalAccount(tsRepo.Entity, tsRepo.Kind)
+		for _, t := range *ts {
+			jts = append(jts, journalTransaction{entity: tsRepo.Entity, kind: tsRepo.Kind, transaction: t})
+			balances[account] += journalAmount(t)
+			if t.TransactionDate.After(lastDates[account]) {
+				lastDates[account] = t.TransactionDate
+			}
+			if t.TransactionDate.Before(openDate) {
+				openDate = t.TransactionDate
+			}
+		}
+		if balanceEntries[account], err = getEntityBalanceEntries(tsesRepo.Namespace, tsRepo.Entity, tsRepo.Kind); err != nil {
+			return "", err
+		}
+		for _, be := range balanceEntries[account] {
+			balances[account] += netWorthBalance(tsRepo.Kind, be.Amount)
+			if be.EntryDate.After(lastDates[account]) {
+				lastDates[account] = be.EntryDate
+			}
+			if be.EntryDate.Before(openDate) {
+				openDate = be.EntryDate
+			}
+		}
+		tseDAO, err := tsesRepo.GetTransactionsEntity(tsRepo.Entity, tsRepo.Kind)
+		if err != nil {
+			return "", err
+		}
+		storedBalances[account] = netWorthBalance(tsRepo.Kind, tseDAO.Balance)
+	}
+	sort.SliceStable(jts, func(i, j int) bool {
+		return jts[i].transaction.TransactionDate.Before(jts[j].transaction.TransactionDate)
+	})
+
+	categoriesAccounts := categoriesJournalAccounts(jts, categoriesLabels(*cs))
+
+	var entitiesAccounts []string
+	for _, tsRepo := range tsRepos {
+		entitiesAccounts = append(entitiesAccounts, entityJournalAccount(tsRepo.Entity, tsRepo.Kind))
+	}
+	var otherAccounts []string
+	for _, a := range categoriesAccounts {
+		otherAccounts = append(otherAccounts, a)
+	}
+	sort.Strings(otherAccounts)
+	otherAccounts = append(otherAccounts, openingBalancesAccount)
+
+	var sb strings.Builder
+	if format == BeancountJournalFormat {
+		sb.WriteString(fmt.Sprintf("option \"title\" \"Daily Expenses\"\noption \"operating_currency\" \"%s\"\n\n",
+			JournalCommodity))
+		for _, a := range append(entitiesAccounts, otherAccounts...) {
+			sb.WriteString(fmt.Sprintf("%s open %s %s\n", openDate.Format(dateFormat), a, JournalCommodity))
+		}
+	} else {
+		sb.WriteString(fmt.Sprintf("commodity %s\n\n", JournalCommodity))
+		for _, a := range append(entitiesAccounts, otherAccounts...) {
+			sb.WriteString(fmt.Sprintf("account %s\n", a))
+		}
+	}
+	sb.WriteString("\n")
+
+	for i, account := range entitiesAccounts {
+		opening := storedBalances[account] - balances[account]
+		if math.Abs(float64(opening)) >= journalAmountsEqualTolerance {
+			writeJournalTransactionHeader(&sb, format, openDate.Format(dateFormat), "Opening balance")
+			writeJournalPosting(&sb, account, formatJournalAmount(opening))
+			writeJournalPosting(&sb, openingBalancesAccount, formatJournalAmount(-opening))
+			sb.WriteString("\n")
+		}
+		for _, be := range balanceEntries[account] {
+			description := "Opening balance"
+			if be.Type == models.AdjustmentBalanceEntry {
+				description = "Balance adjustment"
+			}
+			if be.Note != "" {
+				description += ": " + be.Note
+			}
+			amount := netWorthBalance(tsRepos[i].Kind, be.Amount)
+			writeJournalTransactionHeader(&sb, format, be.EntryDate.Format(dateFormat), description)
+			writeJournalPosting(&sb, account, formatJournalAmount(amount))
+			writeJournalPosting(&sb, openingBalancesAccount, formatJournalAmount(-amount))
+			sb.WriteString("\n")
+		}
+	}
+
+	for _, jt := range jts {
+		t := jt.transaction
+		writeJournalTransactionHeader(&sb, format, t.TransactionDate.Format(dateFormat), t.Transaction)
+		metadata := [][2]string{
+			{"id", strconv.Itoa(t.ID)},
+			{"entity", jt.entity},
+			{"type", jt.kind},
+			{"categories", strings.Join(transactionCategoriesLabels(t), "#")},
+		}
+		if t.Kind != "" {
+			metadata = append(metadata, [2]string{"kind", t.Kind})
+		}
+		payee, err := PayeeName(tsesRepo.Namespace, t)
+		if err != nil {
+			return "", err
+		}
+		if payee != "" {
+			metadata = append(metadata, [2]string{"payee", payee})
+		}
+		if len(t.Tags) != 0 {
+			metadata = append(metadata, [2]string{"tags", strings.Join(t.Tags, "#")})
+		}
+		if t.Memo != "" {
+			metadata = append(metadata, [2]string{"memo", t.Memo})
+		}
+		for _, fv := range t.Fields {
+			metadata = append(metadata, [2]string{journalFieldPrefix + fv.Name, fv.Value})
+		}
+		if t.Status != "" && t.Status != models.PendingTransactionStatus {
+			metadata = append(metadata, [2]string{"status", t.Status})
+		}
+		for _, m := range metadata {
+			if format == BeancountJournalFormat {
+				sb.WriteString(fmt.Sprintf("  %s: \"%s\"\n", m[0], journalQuote(m[1])))
+			} else {
+				sb.WriteString(fmt.Sprintf("    ; %s: %s\n", m[0], m[1]))
+			}
+		}
+		amount := journalAmount(t)
+		writeJournalPosting(&sb, entityJournalAccount(jt.entity, jt.kind), formatJournalAmount(amount))
+		for _, ca := range categoriesJournalAmounts(t) {
+			writeJournalPosting(&sb, categoriesAccounts[ca.label], formatJournalAmount(-ca.amount))
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, account := range entitiesAccounts {
+		assertionDate := openDate
+		if !lastDates[account].IsZero() {
+			assertionDate = lastDates[account]
+		}
+		assertionDate = assertionDate.AddDate(0, 0, 1)
+		if format == BeancountJournalFormat {
+			sb.WriteString(fmt.Sprintf("%s balance %s %s\n", assertionDate.Format(dateFormat), account,
+				formatJournalAmount(storedBalances[account])))
+			continue
+		}
+		writeJournalTransactionHeader(&sb, format, assertionDate.Format(dateFormat), "Balance assertion")
+		writeJournalPosting(&sb, account, fmt.Sprintf("%s = %s", formatJournalAmount(0),
+			formatJournalAmount(storedBalances[account])))
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
+
+func writeJournalTransactionHeader(sb *strings.Builder, format JournalFormat, date, description string) {
+	if format == BeancountJournalFormat {
+		sb.WriteString(fmt.Sprintf("%s * \"%s\"\n", date, journalQuote(description)))
+		return
+	}
+	sb.WriteString(fmt.Sprintf("%s * %s\n", date, description))
+}
+
+func writeJournalPosting(sb *strings.Builder, account, amount string) {
+	sb.WriteString(fmt.Sprintf("  %-40s %s\n", account, amount))
+}
+
+// ImportBeancount reads a beancount journal into a backup.
+func ImportBeancount(csRepo *repositories.CategoriesRepo, psRepo *repositories.PayeesRepo,
+	tsRepos []*repositories.TransactionsRepo, journal string) (*BackupDTO, error) {
+	if csRepo == nil {
+		return nil, fmt.Errorf("categories repo wasn't initialized")
+	}
+	csDAO, err := csRepo.GetAllCategories()
+	if err != nil {
+		return nil, err
+	}
+	ps, err := GetAllPayees(psRepo)
+	if err != nil {
+		return nil, err
+	}
+	labels := categoriesLabels(newCategories(csDAO))
+	for _, line := range strings.Split(journal, "\n") {
+		if m := beancountMetadataRegexp.FindStringSubmatch(line); m != nil && m[1] == "categories" && m[2] != "" {
+			labels = append(labels, strings.Split(journalUnquote(m[2]), "#")...)
+		}
+	}
+	names := categoriesJournalAccountNames(labels)
+
+	backup := &BackupDTO{
+		Version:      BackupVersion,
+		Transactions: []EntityTransactionsDTO{},
+	}
+	etsIdx := make(map[string]int)
+	kinds := make(map[string]string)
+	for _, tsRepo := range tsRepos {
+		kinds[entityJournalAccount(tsRepo.Entity, tsRepo.Kind)] = tsRepo.Kind
+		etsIdx[entityJournalAccount(tsRepo.Entity, tsRepo.Kind)] = len(backup.Transactions)
+		backup.Transactions = append(backup.Transactions, EntityTransactionsDTO{
+			Entity:       tsRepo.Entity,
+			Kind:         tsRepo.Kind,
+			Transactions: []TransactionDTO{},
+		})
+	}
+
+	var date, description string
+	var metadata map[string]string
+	var postings [][2]string
+	lineNumber := 0
+	flush := func() error {
+		defer func() {
+			date, description, metadata, postings = "", "", nil, nil
+		}()
+		if date == "" {
+			return nil
+		}
+		var entityAccount, otherAccount, amount string
+		var otherPostings [][2]string
+		for _, p := range postings {
+			if p[0] == openingBalancesAccount {
+				return importJournalBalanceEntry(backup, etsIdx, kinds, postings, date, description, lineNumber)
+			}
+		}
+		for _, p := range postings {
+			if _, found := etsIdx[p[0]]; found && entityAccount == "" {
+				entityAccount, amount = p[0], p[1]
+				continue
+			}
+			otherAccount = p[0]
+			otherPostings = append(otherPostings, p)
+		}
+		if metadata["entity"] != "" || metadata["type"] != "" {
+			entityAccount = entityJournalAccount(metadata["entity"], metadata["type"])
+		}
+		idx, found := etsIdx[entityAccount]
+		if !found || amount == "" {
+			return fmt.Errorf("the transaction before line %d doesn't post to a supported entity", lineNumber)
+		}
+		tAmount, err := strconv.ParseFloat(amount, 32)
+		if err != nil {
+			return err
+		}
+		tKind, tPositiveAmount := normalizeLegacyAmount(metadata["kind"], float32(tAmount))
+		tDate, err := time.Parse(beancountDateFormat, date)
+		if err != nil {
+			return err
+		}
+
+		var categories []string
+		if cls, found := metadata["categories"]; found {
+			if cls != "" {
+				categories = strings.Split(cls, "#")
+			}
+		} else if otherAccount != "" {
+			if label := journalAccountLabel(names, otherAccount); label != "" {
+				categories = append(categories, label)
+			}
+		}
+		if categories == nil {
+			categories = []string{}
+		}
+
+		var tags []string
+		if metadata["tags"] != "" {
+			tags = strings.Split(metadata["tags"], "#")
+		}
+		var fields map[string]any
+		for key, value := range metadata {
+			if name, found := strings.CutPrefix(key, journalFieldPrefix); found {
+				if fields == nil {
+					fields = make(map[string]any)
+				}
+				fields[name] = value
+			}
+		}
+
+		var splits []SplitDTO
+		if len(otherPostings) > 1 {
+			for _, p := range otherPostings {
+				label := journalAccountLabel(names, p[0])
+				if label == "" {
+					return fmt.Errorf("the account %q before line %d doesn't match any category", p[0], lineNumber)
+				}
+				spAmount, err := strconv.ParseFloat(p[1], 32)
+				if err != nil {
+					return err
+				}
+				splits = append(splits, SplitDTO{Category: label, Amount: float32(math.Abs(spAmount))})
+			}
+		}
+
+		backup.Transactions[idx].Transactions = append(backup.Transactions[idx].Transactions, TransactionDTO{
+			ID:              metadata["id"],
+			TransactionDate: tDate.Format(utils.DateFormat),
+			Transaction:     description,
+			Categories:      categories,
+			Kind:            tKind,
+			Amount:          tPositiveAmount,
+			Splits:          splits,
+			Payee:           metadata["payee"],
+			Tags:            tags,
+			Memo:            metadata["memo"],
+			Fields:          fields,
+			Status:          metadata["status"],
+		})
+		return nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(journal))
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if m := beancountTransactionRegexp.FindStringSubmatch(line); m != nil {
+			if err = flush(); err != nil {
+				return nil, err
+			}
+			date, description, metadata = m[1], journalUnquote(m[2]), make(map[string]string)
+			continue
+		}
+		if date != "" {
+			if m := beancountMetadataRegexp.FindStringSubmatch(line); m != nil {
+				metadata[m[1]] = journalUnquote(m[2])
+				continue
+			}
+			if m := beancountPostingRegexp.FindStringSubmatch(line); m != nil {
+				if m[3] != JournalCommodity {
+					return nil, fmt.Errorf("the commodity %q at line %d is not supported", m[3], lineNumber)
+				}
+				postings = append(postings, [2]string{m[1], m[2]})
+				continue
+			}
+		}
+		if strings.TrimSpace(line) == "" || !unicode.IsSpace([]rune(line)[0]) {
+			if err = flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err = flush(); err != nil {
+		return nil, err
+	}
+
+	cID := 0
+	existing := make(map[string]bool)
+	for _, cDAO := range csDAO {
+		existing[cDAO.Label] = true
+		cID = max(cID, cDAO.ID)
+	}
+	for _, ets := range backup.Transactions {
+		for _, tDTO := range ets.Transactions {
+			if _, found := findPayeeByName(ps, tDTO.Payee); tDTO.Payee != "" && !found {
+				pDTO := PayeeDTO{ID: "0", Name: tDTO.Payee, Aliases: []string{}, Patterns: []string{}}
+				backup.Payees = append(backup.Payees, pDTO)
+				ps = append(ps, models.Payee{Name: tDTO.Payee})
+			}
+			tLabels := tDTO.Categories
+			for _, sp := range tDTO.Splits {
+				tLabels = append(tLabels, sp.Category)
+			}
+			for _, label := range tLabels {
+				if !existing[label] {
+					existing[label] = true
+					cID++
+					backup.Categories = append(backup.Categories, CategoryDTO{ID: strconv.Itoa(cID), Label: label})
+				}
+			}
+		}
+	}
+
+	return backup, nil
+}
+
+func importJournalBalanceEntry(backup *BackupDTO, etsIdx map[string]int, kinds map[string]string,
+	postings [][2]string, date, description string, lineNumber int) error {
+	for _, p := range postings {
+		idx, found := etsIdx[p[0]]
+		if !found {
+			continue
+		}
+		amount, err := strconv.ParseFloat(p[1], 32)
+		if err != nil {
+			return err
+		}
+		beDate, err := time.Parse(beancountDateFormat, date)
+		if err != nil {
+			return err
+		}
+		beType, note, _ := strings.Cut(description, ": ")
+		if beType == "Balance adjustment" {
+			beType = models.AdjustmentBalanceEntry
+		} else {
+			beType = models.OpeningBalanceEntry
+		}
+		beDTO := BalanceEntryDTO{
+			Entity:     backup.Transactions[idx].Entity,
+			EntityKind: backup.Transactions[idx].Kind,
+			Type:       beType,
+			Date:       beDate.Format(utils.DateFormat),
+			Amount:     netWorthBalance(kinds[p[0]], float32(amount)),
+			Note:       note,
+		}
+		if beType == models.OpeningBalanceEntry {
+			for i, other := range backup.BalanceEntries {
+				if other.Type == beType && other.Entity == beDTO.Entity && other.EntityKind == beDTO.EntityKind {
+					backup.BalanceEntries[i].Amount += beDTO.Amount
+					return nil
+				}
+			}
+		}
+		backup.BalanceEntries = append(backup.BalanceEntries, beDTO)
+		return nil
+	}
+	return fmt.Errorf("the opening balance before line %d doesn't post to a supported entity", lineNumber)
+}
+
+func transactionCategoriesLabels(t models.Transaction) []string {
+	var categoriesLabels []string
+	for _, c := range t.Categories {
+		categoriesLabels = append(categoriesLabels, c.Label)
+	}
+	return categoriesLabels
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"testing"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+)
+
+func exportBeancount(t *testing.T, ns *repositories.Namespace) string {
+	tsesRepo, err := ns.GetTransEntRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	csRepo, err := ns.GetCategoriesRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repos, err := ns.GetAllRepos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal, err := ExportJournal(tsesRepo, csRepo, *repos, BeancountJournalFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return journal
+}
+
+func TestBeancountRoundTrip(t *testing.T) {
+	exporter := addTestUser(t, "journal-exporter")
+	adjustment := float32(1400)
+	backup := BackupDTO{
+		Version: BackupVersion,
+		Categories: CategoriesDTO{
+			{ID: "6", Label: "RESTAURANTS"},
+		},
+		Payees: PayeesDTO{
+			{ID: "1", Name: "Tasca", Aliases: []string{}, Patterns: []string{}},
+		},
+		BalanceEntries: BalanceEntriesDTO{
+			{Entity: "test", EntityKind: "debit_bank_account", Type: models.OpeningBalanceEntry,
+				Date: "01/01/2024", Amount: 500},
+			{Entity: "test", EntityKind: "debit_bank_account", Type: models.AdjustmentBalanceEntry,
+				Date: "28/02/2024", Amount: 20, Balance: &adjustment, Note: "bank fees"},
+			{Entity: "test2", EntityKind: "debit_credit_bank_account", Type: models.OpeningBalanceEntry,
+				Date: "01/01/2024", Amount: 150},
+		},
+		Transactions: []EntityTransactionsDTO{
+			{Entity: "test", Kind: "debit_bank_account", Transactions: []TransactionDTO{
+				{ID: "1", TransactionDate: "05/01/2024", Transaction: "Salary", Categories: []string{"SALARY"},
+					Kind: models.CreditKindTransaction, Amount: 1000},
+				{ID: "2", TransactionDate: "10/01/2024", Transaction: "Dinner", Categories: []string{"RESTAURANTS"},
+					Kind: models.DebitKindTransaction, Amount: 42.5, Payee: "Tasca", Tags: []string{"friends"}},
+				{ID: "3", TransactionDate: "12/01/2024", Transaction: "Trip", Kind: models.DebitKindTransaction,
+					Amount: 80, Splits: []SplitDTO{{Category: "FUEL", Amount: 50}, {Category: "RESTAURANTS", Amount: 30}}},
+				{ID: "4", TransactionDate: "15/01/2024", Transaction: "Cash", Categories: []string{},
+					Kind: models.DebitKindTransaction, Amount: 20},
+			}},
+			{Entity: "test2", Kind: "debit_credit_bank_account", Transactions: []TransactionDTO{
+				{ID: "1", TransactionDate: "20/01/2024", Transaction: "Fuel", Categories: []string{"FUEL"},
+					Kind: models.DebitKindTransaction, Amount: 60},
+			}},
+		},
+	}
+	if _, err := ImportBackup(exporter, backup, SkipImportStrategy); err != nil {
+		t.Fatal(err)
+	}
+	exported := exportBeancount(t, exporter)
+
+	importer := addTestUser(t, "journal-importer")
+	csRepo, err := importer.GetCategoriesRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	psRepo, err := importer.GetPayeesRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repos, err := importer.GetAllRepos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := ImportBeancount(csRepo, psRepo, *repos, exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ImportBackup(importer, *imported, SkipImportStrategy); err != nil {
+		t.Fatal(err)
+	}
+
+	if reexported := exportBeancount(t, importer); reexported != exported {
+		t.Errorf("the journal imported into a new namespace is exported as\n%s\ninstead of\n%s", reexported, exported)
+	}
+}
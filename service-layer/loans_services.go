@@ -0,0 +1,531 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+const (
+	maxLoanTerm int = 600
+)
+
+type LoanDTO struct {
+	ID                   string           `json:"id"`
+	Name                 string           `json:"name"`
+	Principal            float32          `json:"principal"`
+	AnnualRate           float32          `json:"annual_rate"`
+	Term                 int              `json:"term"`
+	StartDate            string           `json:"start_date"`
+	Entity               string           `json:"entity"`
+	EntityKind           string           `json:"entity_type"`
+	Category             string           `json:"category,omitempty"`
+	Recorded             int              `json:"recorded"`
+	Payment              float32          `json:"payment"`
+	OutstandingPrincipal *float32         `json:"outstanding_principal,omitempty"`
+	InterestPaid         *float32         `json:"interest_paid,omitempty"`
+	NextDueDate          string           `json:"next_due_date,omitempty"`
+	Installments         []InstallmentDTO `json:"installments,omitempty"`
+}
+
+type LoansDTO []LoanDTO
+
+type InstallmentDTO struct {
+	Number        int     `json:"number"`
+	DueDate       string  `json:"due_date"`
+	Payment       float32 `json:"payment"`
+	Principal     float32 `json:"principal"`
+	Interest      float32 `json:"interest"`
+	Balance       float32 `json:"balance"`
+	Paid          bool    `json:"paid"`
+	TransactionID string  `json:"transaction_id,omitempty"`
+}
+
+type LoanInterestDTO struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Interest  float32 `json:"interest"`
+	Principal float32 `json:"principal"`
+}
+
+type YearInterestDTO struct {
+	Year      int               `json:"year"`
+	Interest  float32           `json:"interest"`
+	Principal float32           `json:"principal"`
+	Loans     []LoanInterestDTO `json:"loans"`
+}
+
+type LoansInterestReportDTO struct {
+	Years []YearInterestDTO `json:"years"`
+}
+
+// LoanInstallmentBackupDTO is a recorded installment in the backups.
+type LoanInstallmentBackupDTO struct {
+	LoanID        string `json:"loan_id"`
+	Number        int    `json:"number"`
+	Entity        string `json:"entity"`
+	EntityKind    string `json:"entity_type"`
+	TransactionID string `json:"transaction_id"`
+}
+
+func NewLoanDTO(l models.Loan) LoanDTO {
+	return LoanDTO{
+		ID:         strconv.Itoa(l.ID),
+		Name:       l.Name,
+		Principal:  l.Principal,
+		AnnualRate: l.AnnualRate,
+		Term:       l.Term,
+		StartDate:  l.StartDate.Format(utils.DateFormat),
+		Entity:     l.Entity,
+		EntityKind: l.Kind,
+		Category:   l.Category,
+		Recorded:   l.Recorded,
+		Payment:    loanPayment(l),
+	}
+}
+
+// NewLoan validates the DTO of a loan.
+func (lDTO LoanDTO) NewLoan(ns *repositories.Namespace) (models.Loan, error) {
+	l := models.Loan{
+		Name:       strings.Trim(lDTO.Name, " "),
+		Principal:  roundAmount(lDTO.Principal),
+		AnnualRate: lDTO.AnnualRate,
+		Term:       lDTO.Term,
+		Entity:     lDTO.Entity,
+		Kind:       lDTO.EntityKind,
+		Category:   strings.Trim(lDTO.Category, " "),
+		Recorded:   lDTO.Recorded,
+	}
+	if l.Name == "" {
+		return l, fmt.Errorf("the name of the loan is mandatory")
+	}
+	if l.Principal <= 0 {
+		return l, fmt.Errorf("the principal %.2f is not valid because it must be positive", lDTO.Principal)
+	}
+	if l.AnnualRate < 0 || l.AnnualRate > 100 {
+		return l, fmt.Errorf("the annual_rate %.2f isn't between 0 and 100", l.AnnualRate)
+	}
+	if l.Term < 1 || l.Term > maxLoanTerm {
+		return l, fmt.Errorf("the term %d isn't between 1 and %d months", l.Term, maxLoanTerm)
+	}
+	if l.Recorded < 0 || l.Recorded > l.Term {
+		return l, fmt.Errorf("the recorded %d isn't between 0 and the term", l.Recorded)
+	}
+	var err error
+	if l.StartDate, err = time.Parse(utils.DateFormat, strings.Trim(lDTO.StartDate, " ")); err != nil {
+		return l, fmt.Errorf("the start_date %q is not valid (%s)", lDTO.StartDate, utils.DateFormat)
+	}
+	if _, err = ns.GetTransRepo(l.Kind, l.Entity); err != nil {
+		return l, err
+	}
+	if l.Category != "" {
+		csRepo, err := ns.GetCategoriesRepo()
+		if err != nil {
+			return l, err
+		}
+		if _, err = csRepo.CategoryDAO(l.Category); err != nil {
+			return l, err
+		}
+	}
+	return l, nil
+}
+
+func newLoan(lDAO repositories.LoanDAO) models.Loan {
+	return models.Loan{
+		ID:         lDAO.ID,
+		Name:       lDAO.Name,
+		Principal:  lDAO.Principal,
+		AnnualRate: lDAO.AnnualRate,
+		Term:       lDAO.Term,
+		StartDate:  lDAO.StartDate,
+		Entity:     lDAO.Entity,
+		Kind:       lDAO.Kind,
+		Category:   lDAO.Category,
+		Recorded:   lDAO.Recorded,
+	}
+}
+
+func newLoanDAO(l models.Loan) repositories.LoanDAO {
+	return repositories.LoanDAO{
+		ID:         l.ID,
+		Name:       l.Name,
+		Principal:  l.Principal,
+		AnnualRate: l.AnnualRate,
+		Term:       l.Term,
+		StartDate:  l.StartDate,
+		Entity:     l.Entity,
+		Kind:       l.Kind,
+		Category:   l.Category,
+		Recorded:   l.Recorded,
+	}
+}
+
+func GetAllLoans(repo *repositories.LoansRepo) (models.Loans, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("loans repo wasn't initialized")
+	}
+	lsDAO, err := repo.GetAllLoans()
+	if err != nil {
+		return nil, err
+	}
+	ls := models.Loans{}
+	for _, lDAO := range lsDAO {
+		ls = append(ls, newLoan(lDAO))
+	}
+	return ls, nil
+}
+
+func GetLoanByID(repo *repositories.LoansRepo, id int) (models.Loan, error) {
+	ls, err := GetAllLoans(repo)
+	if err != nil {
+		return models.Loan{}, err
+	}
+	for _, l := range ls {
+		if l.ID == id {
+			return l, nil
+		}
+	}
+	return models.Loan{}, fmt.Errorf("loan %d not found", id)
+}
+
+func AddLoan(repo *repositories.LoansRepo, l models.Loan) (int, error) {
+	ls, err := GetAllLoans(repo)
+	if err != nil {
+		return -1, err
+	}
+	l.ID = 1
+	for _, other := range ls {
+		if other.ID >= l.ID {
+			l.ID = other.ID + 1
+		}
+	}
+	if err = repo.AddLoan(newLoanDAO(l)); err != nil {
+		return -1, err
+	}
+	return l.ID, nil
+}
+
+// DeleteLoan deletes the loan, keeping the transactions of its installments.
+func DeleteLoan(repo *repositories.LoansRepo, liRepo *repositories.LoansInstallmentsRepo, id int) error {
+	if repo == nil {
+		return fmt.Errorf("loans repo wasn't initialized")
+	}
+	if liRepo == nil {
+		return fmt.Errorf("loans installments repo wasn't initialized")
+	}
+	if err := liRepo.DeleteLoanInstallments(id); err != nil {
+		return err
+	}
+	return repo.DeleteLoan(id)
+}
+
+func loanPayment(l models.Loan) float32 {
+	principal, rate, term := float64(l.Principal), float64(l.AnnualRate)/100/12, float64(l.Term)
+	if rate == 0 {
+		return roundAmount(float32(principal / term))
+	}
+	return roundAmount(float32(principal * rate / (1 - math.Pow(1+rate, -term))))
+}
+
+func installmentDueDate(l models.Loan, number int) time.Time {
+	month := time.Date(l.StartDate.Year(), l.StartDate.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, number, 0)
+	return dayOfMonth(month.Year(), month.Month(), l.StartDate.Day())
+}
+
+// LoanSchedule is the amortization schedule of the loan.
+func LoanSchedule(l models.Loan) []InstallmentDTO {
+	payment, rate := loanPayment(l), float64(l.AnnualRate)/100/12
+	balance := l.Principal
+	schedule := make([]InstallmentDTO, 0, l.Term)
+	for number := 1; number <= l.Term; number++ {
+		i := InstallmentDTO{
+			Number:   number,
+			DueDate:  installmentDueDate(l, number).Format(utils.DateFormat),
+			Interest: roundAmount(float32(float64(balance) * rate)),
+		}
+		i.Principal = roundAmount(payment - i.Interest)
+		if number == l.Term || i.Principal > balance {
+			i.Principal = balance
+		}
+		i.Payment = roundAmount(i.Principal + i.Interest)
+		balance = roundAmount(balance - i.Principal)
+		i.Balance = balance
+		schedule = append(schedule, i)
+	}
+	return schedule
+}
+
+// GetLoanDetails gets the loan with its schedule and installments.
+func GetLoanDetails(liRepo *repositories.LoansInstallmentsRepo, l models.Loan, today time.Time) (LoanDTO, error) {
+	if liRepo == nil {
+		return LoanDTO{}, fmt.Errorf("loans installments repo wasn't initialized")
+	}
+	lisDAO, err := liRepo.GetLoanInstallments(l.ID)
+	if err != nil {
+		return LoanDTO{}, err
+	}
+	transactions := make(map[int]int)
+	for _, liDAO := range lisDAO {
+		transactions[liDAO.Number] = liDAO.TransactionID
+	}
+
+	lDTO := NewLoanDTO(l)
+	lDTO.Installments = LoanSchedule(l)
+	outstanding, interestPaid := l.Principal, float32(0.0)
+	for i := range lDTO.Installments {
+		dueDate := installmentDueDate(l, lDTO.Installments[i].Number)
+		if tID, found := transactions[lDTO.Installments[i].Number]; found {
+			lDTO.Installments[i].TransactionID = strconv.Itoa(tID)
+		}
+		if dueDate.After(today) {
+			if lDTO.NextDueDate == "" {
+				lDTO.NextDueDate = lDTO.Installments[i].DueDate
+			}
+			continue
+		}
+		lDTO.Installments[i].Paid = true
+		outstanding = lDTO.Installments[i].Balance
+		interestPaid += lDTO.Installments[i].Interest
+	}
+	interestPaid = roundAmount(interestPaid)
+	lDTO.OutstandingPrincipal, lDTO.InterestPaid = &outstanding, &interestPaid
+	return lDTO, nil
+}
+
+// GetLoans gets the loans, without their schedules, sorted by start date.
+func GetLoans(repo *repositories.LoansRepo, liRepo *repositories.LoansInstallmentsRepo,
+	today time.Time) (LoansDTO, error) {
+	ls, err := GetAllLoans(repo)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(ls, func(i, j int) bool {
+		if !ls[i].StartDate.Equal(ls[j].StartDate) {
+			return ls[i].StartDate.Before(ls[j].StartDate)
+		}
+		return ls[i].ID < ls[j].ID
+	})
+	lsDTO := LoansDTO{}
+	for _, l := range ls {
+		lDTO, err := GetLoanDetails(liRepo, l, today)
+		if err != nil {
+			return nil, err
+		}
+		lDTO.Installments = nil
+		lsDTO = append(lsDTO, lDTO)
+	}
+	return lsDTO, nil
+}
+
+// RecordDueInstallments records the installments due until the date.
+func RecordDueInstallments(repo *repositories.LoansRepo, liRepo *repositories.LoansInstallmentsRepo,
+	today time.Time) (int, error) {
+	if liRepo == nil {
+		return 0, fmt.Errorf("loans installments repo wasn't initialized")
+	}
+	ls, err := GetAllLoans(repo)
+	if err != nil {
+		return 0, err
+	}
+
+	recorded := 0
+	for _, l := range ls {
+		if l.Recorded >= l.Term || installmentDueDate(l, l.Recorded+1).After(today) {
+			continue
+		}
+		tsRepo, err := repo.Namespace.GetTransRepo(l.Kind, l.Entity)
+		if err != nil {
+			return recorded, err
+		}
+		schedule := LoanSchedule(l)
+		for ; l.Recorded < l.Term && !installmentDueDate(l, l.Recorded+1).After(today); l.Recorded++ {
+			i := schedule[l.Recorded]
+			t := models.Transaction{
+				TransactionDate: installmentDueDate(l, i.Number),
+				Transaction:     fmt.Sprintf("%s %d/%d", l.Name, i.Number, l.Term),
+				Categories:      models.Categories{},
+				Kind:            models.DebitKindTransaction,
+				Amount:          i.Payment,
+				Memo:            fmt.Sprintf("principal %.2f, interest %.2f", i.Principal, i.Interest),
+			}
+			if l.Category != "" {
+				t.Categories = append(t.Categories, models.Category{Label: l.Category})
+			}
+			if err = setTransactionPayee(repo.Namespace, &t, ""); err != nil {
+				return recorded, err
+			}
+			tID, err := AddTransaction(tsRepo, t)
+			if err != nil {
+				return recorded, err
+			}
+			if err = liRepo.AddLoanInstallment(repositories.LoanInstallmentDAO{
+				LoanID:        l.ID,
+				Number:        i.Number,
+				Entity:        l.Entity,
+				Kind:          l.Kind,
+				TransactionID: tID,
+			}); err != nil {
+				return recorded, err
+			}
+			recorded++
+		}
+		if err = repo.UpdateLoan(newLoanDAO(l)); err != nil {
+			return recorded, err
+		}
+	}
+	return recorded, nil
+}
+
+// GetLoansInterestReport sums the interest and the principal by year.
+func GetLoansInterestReport(repo *repositories.LoansRepo, year int, today time.Time) (*LoansInterestReportDTO, error) {
+	ls, err := GetAllLoans(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	years := make(map[int]*YearInterestDTO)
+	for _, l := range ls {
+		byYear := make(map[int]*LoanInterestDTO)
+		for _, i := range LoanSchedule(l) {
+			dueDate := installmentDueDate(l, i.Number)
+			if dueDate.After(today) || year != 0 && dueDate.Year() != year {
+				continue
+			}
+			if byYear[dueDate.Year()] == nil {
+				byYear[dueDate.Year()] = &LoanInterestDTO{ID: strconv.Itoa(l.ID), Name: l.Name}
+			}
+			byYear[dueDate.Year()].Interest += i.Interest
+			byYear[dueDate.Year()].Principal += i.Principal
+		}
+		for y, li := range byYear {
+			if years[y] == nil {
+				years[y] = &YearInterestDTO{Year: y, Loans: []LoanInterestDTO{}}
+			}
+			li.Interest, li.Principal = roundAmount(li.Interest), roundAmount(li.Principal)
+			years[y].Interest += li.Interest
+			years[y].Principal += li.Principal
+			years[y].Loans = append(years[y].Loans, *li)
+		}
+	}
+
+	report := &LoansInterestReportDTO{
+		Years: []YearInterestDTO{},
+	}
+	for _, yi := range years {
+		yi.Interest, yi.Principal = roundAmount(yi.Interest), roundAmount(yi.Principal)
+		sort.Slice(yi.Loans, func(i, j int) bool {
+			return yi.Loans[i].Interest > yi.Loans[j].Interest
+		})
+		report.Years = append(report.Years, *yi)
+	}
+	sort.Slice(report.Years, func(i, j int) bool {
+		return report.Years[i].Year < report.Years[j].Year
+	})
+	return report, nil
+}
+
+func exportLoans(ns *repositories.Namespace) (LoansDTO, []LoanInstallmentBackupDTO, error) {
+	repo, err := ns.GetLoansRepo()
+	if err != nil {
+		return nil, nil, err
+	}
+	ls, err := GetAllLoans(repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	var lsDTO LoansDTO
+	for _, l := range ls {
+		lsDTO = append(lsDTO, NewLoanDTO(l))
+	}
+
+	liRepo, err := ns.GetLoansInstallmentsRepo()
+	if err != nil {
+		return nil, nil, err
+	}
+	lisDAO, err := liRepo.GetAllLoansInstallments()
+	if err != nil {
+		return nil, nil, err
+	}
+	var lisDTO []LoanInstallmentBackupDTO
+	for _, liDAO := range lisDAO {
+		lisDTO = append(lisDTO, LoanInstallmentBackupDTO{
+			LoanID:        strconv.Itoa(liDAO.LoanID),
+			Number:        liDAO.Number,
+			Entity:        liDAO.Entity,
+			EntityKind:    liDAO.Kind,
+			TransactionID: strconv.Itoa(liDAO.TransactionID),
+		})
+	}
+	return lsDTO, lisDTO, nil
+}
+
+func importLoan(repo *repositories.LoansRepo, lDTO LoanDTO, strategy ImportStrategy,
+	counters *ImportCountersDTO) (int, error) {
+	lID, err := strconv.Atoi(lDTO.ID)
+	if err != nil {
+		return -1, fmt.Errorf("invalid loan ID %q", lDTO.ID)
+	}
+	l, err := lDTO.NewLoan(repo.Namespace)
+	if err != nil {
+		return -1, err
+	}
+	l.ID = lID
+
+	if _, err = GetLoanByID(repo, lID); err != nil {
+		if err = repo.AddLoan(newLoanDAO(l)); err != nil {
+			return -1, err
+		}
+		counters.Created++
+		return lID, nil
+	}
+	switch strategy {
+	case OverwriteImportStrategy:
+		if err = repo.UpdateLoan(newLoanDAO(l)); err != nil {
+			return -1, err
+		}
+		counters.Updated++
+		return lID, nil
+	case RenumberImportStrategy:
+		if l.ID, err = AddLoan(repo, l); err != nil {
+			return -1, err
+		}
+		counters.Renumbered++
+		return l.ID, nil
+	default:
+		counters.Skipped++
+		return -1, nil
+	}
+}
+
+func importLoanInstallment(repo *repositories.LoansInstallmentsRepo, liDTO LoanInstallmentBackupDTO, lID, tID int,
+	counters *ImportCountersDTO) error {
+	lisDAO, err := repo.GetAllLoansInstallments()
+	if err != nil {
+		return err
+	}
+	for _, liDAO := range lisDAO {
+		if liDAO.LoanID == lID && liDAO.Number == liDTO.Number ||
+			liDAO.Entity == liDTO.Entity && liDAO.Kind == liDTO.EntityKind && liDAO.TransactionID == tID {
+			counters.Skipped++
+			return nil
+		}
+	}
+	if err = repo.AddLoanInstallment(repositories.LoanInstallmentDAO{
+		LoanID:        lID,
+		Number:        liDTO.Number,
+		Entity:        liDTO.Entity,
+		Kind:          liDTO.EntityKind,
+		TransactionID: tID,
+	}); err != nil {
+		return err
+	}
+	counters.Created++
+	return nil
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+type ParticipantDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ParticipantsDTO []ParticipantDTO
+
+func NewParticipantDTO(p models.Participant) ParticipantDTO {
+	return ParticipantDTO{
+		ID:   strconv.Itoa(p.ID),
+		Name: p.Name,
+	}
+}
+
+func NewParticipantsDTO(ps models.Participants) ParticipantsDTO {
+	psDTO := ParticipantsDTO{}
+	for _, p := range ps {
+		psDTO = append(psDTO, NewParticipantDTO(p))
+	}
+	return psDTO
+}
+
+func (pDTO ParticipantDTO) NewParticipant() (models.Participant, error) {
+	p := models.Participant{
+		Name: strings.Trim(pDTO.Name, " "),
+	}
+	if p.Name == "" {
+		return p, fmt.Errorf("the name of the participant is mandatory")
+	}
+	return p, nil
+}
+
+func newParticipant(pDAO repositories.ParticipantDAO) models.Participant {
+	return models.Participant{
+		ID:   pDAO.ID,
+		Name: pDAO.Name,
+	}
+}
+
+func newParticipantDAO(p models.Participant) repositories.ParticipantDAO {
+	return repositories.ParticipantDAO{
+		ID:   p.ID,
+		Name: p.Name,
+	}
+}
+
+// GetAllParticipants gets the participants sorted by name.
+func GetAllParticipants(repo *repositories.ParticipantsRepo) (models.Participants, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("participants repo wasn't initialized")
+	}
+	psDAO, err := repo.GetAllParticipants()
+	if err != nil {
+		return nil, err
+	}
+	ps := models.Participants{}
+	for _, pDAO := range psDAO {
+		ps = append(ps, newParticipant(pDAO))
+	}
+	sort.SliceStable(ps, func(i, j int) bool {
+		return utils.FoldText(ps[i].Name) < utils.FoldText(ps[j].Name)
+	})
+	return ps, nil
+}
+
+func GetParticipantByID(repo *repositories.ParticipantsRepo, id int) (models.Participant, error) {
+	ps, err := GetAllParticipants(repo)
+	if err != nil {
+		return models.Participant{}, err
+	}
+	for _, p := range ps {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return models.Participant{}, fmt.Errorf("participant %d not found", id)
+}
+
+func findParticipantByName(ps models.Participants, name string) (models.Participant, bool) {
+	for _, p := range ps {
+		if utils.FoldText(p.Name) == utils.FoldText(strings.Trim(name, " ")) {
+			return p, true
+		}
+	}
+	return models.Participant{}, false
+}
+
+// CheckParticipantName checks the name of the participant is free.
+func CheckParticipantName(repo *repositories.ParticipantsRepo, p models.Participant) error {
+	ps, err := GetAllParticipants(repo)
+	if err != nil {
+		return err
+	}
+	if other, found := findParticipantByName(ps, p.Name); found && other.ID != p.ID {
+		return fmt.Errorf("the name %q is already used by the participant %d", p.Name, other.ID)
+	}
+	return nil
+}
+
+func AddParticipant(repo *repositories.ParticipantsRepo, p models.Participant) (int, error) {
+	ps, err := GetAllParticipants(repo)
+	if err != nil {
+		return -1, err
+	}
+	if err = CheckParticipantName(repo, p); err != nil {
+		return -1, err
+	}
+	p.ID = 1
+	for _, other := range ps {
+		if other.ID >= p.ID {
+			p.ID = other.ID + 1
+		}
+	}
+	if err = repo.AddParticipant(newParticipantDAO(p)); err != nil {
+		return -1, err
+	}
+	return p.ID, nil
+}
+
+func UpdateParticipant(repo *repositories.ParticipantsRepo, p models.Participant) error {
+	if err := CheckParticipantName(repo, p); err != nil {
+		return err
+	}
+	return repo.UpdateParticipant(newParticipantDAO(p))
+}
+
+// CheckParticipantIsUnused checks the participant isn't used.
+func CheckParticipantIsUnused(ns *repositories.Namespace, id int) error {
+	shRepo, err := ns.GetTransSharesRepo()
+	if err != nil {
+		return err
+	}
+	stsDAO, err := shRepo.GetAllSharedTransactions()
+	if err != nil {
+		return err
+	}
+	for _, stDAO := range stsDAO {
+		if stDAO.PayerID == id {
+			return fmt.Errorf("the participant %d paid the transaction %d", id, stDAO.TransactionID)
+		}
+		for _, s := range stDAO.Shares {
+			if s.ParticipantID == id {
+				return fmt.Errorf("the participant %d shares the transaction %d", id, stDAO.TransactionID)
+			}
+		}
+	}
+
+	sRepo, err := ns.GetSettlementsRepo()
+	if err != nil {
+		return err
+	}
+	ssDAO, err := sRepo.GetAllSettlements()
+	if err != nil {
+		return err
+	}
+	for _, sDAO := range ssDAO {
+		if sDAO.FromID == id || sDAO.ToID == id {
+			return fmt.Errorf("the participant %d is in the settlement %d", id, sDAO.ID)
+		}
+	}
+	return nil
+}
+
+func DeleteParticipant(repo *repositories.ParticipantsRepo, id int) error {
+	if repo == nil {
+		return fmt.Errorf("participants repo wasn't initialized")
+	}
+	if err := CheckParticipantIsUnused(repo.Namespace, id); err != nil {
+		return err
+	}
+	return repo.DeleteParticipant(id)
+}
+
+func exportParticipants(ns *repositories.Namespace) (ParticipantsDTO, error) {
+	repo, err := ns.GetParticipantsRepo()
+	if err != nil {
+		return nil, err
+	}
+	ps, err := GetAllParticipants(repo)
+	if err != nil {
+		return nil, err
+	}
+	if len(ps) == 0 {
+		return nil, nil
+	}
+	return NewParticipantsDTO(ps), nil
+}
+
+func importParticipant(repo *repositories.ParticipantsRepo, pDTO ParticipantDTO, strategy ImportStrategy,
+	counters *ImportCountersDTO) error {
+	pID, err := strconv.Atoi(pDTO.ID)
+	if err != nil {
+		return fmt.Errorf("invalid participant ID %q", pDTO.ID)
+	}
+	p, err := pDTO.NewParticipant()
+	if err != nil {
+		return err
+	}
+	p.ID = pID
+
+	ps, err := GetAllParticipants(repo)
+	if err != nil {
+		return err
+	}
+	if _, found := findParticipantByName(ps, p.Name); found {
+		counters.Skipped++
+		return nil
+	}
+	if _, err = GetParticipantByID(repo, pID); err != nil {
+		if err = repo.AddParticipant(newParticipantDAO(p)); err != nil {
+			return err
+		}
+		counters.Created++
+		return nil
+	}
+	switch strategy {
+	case OverwriteImportStrategy:
+		if err = repo.UpdateParticipant(newParticipantDAO(p)); err != nil {
+			return err
+		}
+		counters.Updated++
+	case RenumberImportStrategy:
+		if _, err = AddParticipant(repo, p); err != nil {
+			return err
+		}
+		counters.Renumbered++
+	default:
+		counters.Skipped++
+	}
+	return nil
+}
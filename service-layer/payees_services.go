@@ -0,0 +1,402 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+type PayeeDTO struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Aliases         []string `json:"aliases"`
+	Patterns        []string `json:"patterns"`
+	DefaultCategory string   `json:"default_category,omitempty"`
+}
+
+type PayeesDTO []PayeeDTO
+
+type PayeeTotalsDTO struct {
+	Payee        string `json:"payee"`
+	Transactions int    `json:"transactions"`
+	SummaryAmountsDTO
+}
+
+type PayeesReportDTO struct {
+	From   string           `json:"from,omitempty"`
+	To     string           `json:"to,omitempty"`
+	Payees []PayeeTotalsDTO `json:"payees"`
+}
+
+func NewPayeeDTO(p models.Payee) PayeeDTO {
+	return PayeeDTO{
+		ID:              strconv.Itoa(p.ID),
+		Name:            p.Name,
+		Aliases:         p.Aliases,
+		Patterns:        p.Patterns,
+		DefaultCategory: p.DefaultCategory,
+	}
+}
+
+func NewPayeesDTO(ps models.Payees) PayeesDTO {
+	psDTO := PayeesDTO{}
+	for _, p := range ps {
+		psDTO = append(psDTO, NewPayeeDTO(p))
+	}
+	return psDTO
+}
+
+func newPayee(pDAO repositories.PayeeDAO) models.Payee {
+	return models.Payee{
+		ID:              pDAO.ID,
+		Name:            pDAO.Name,
+		Aliases:         pDAO.Aliases,
+		Patterns:        pDAO.Patterns,
+		DefaultCategory: pDAO.DefaultCategory,
+	}
+}
+
+func newPayeeDAO(p models.Payee) repositories.PayeeDAO {
+	return repositories.PayeeDAO{
+		ID:              p.ID,
+		Name:            p.Name,
+		Aliases:         p.Aliases,
+		Patterns:        p.Patterns,
+		DefaultCategory: p.DefaultCategory,
+	}
+}
+
+func trimmedValues(values []string) []string {
+	trimmed := []string{}
+	for _, v := range values {
+		if v = strings.Trim(v, " "); v != "" {
+			trimmed = append(trimmed, v)
+		}
+	}
+	return trimmed
+}
+
+// NewPayee validates the DTO of a payee.
+func (pDTO PayeeDTO) NewPayee(ns *repositories.Namespace) (models.Payee, error) {
+	p := models.Payee{
+		Name:            strings.Trim(pDTO.Name, " "),
+		Aliases:         trimmedValues(pDTO.Aliases),
+		Patterns:        trimmedValues(pDTO.Patterns),
+		DefaultCategory: strings.Trim(pDTO.DefaultCategory, " "),
+	}
+	if p.Name == "" {
+		return p, fmt.Errorf("the name of the payee is mandatory")
+	}
+	for _, v := range append(append([]string{p.Name}, p.Aliases...), p.Patterns...) {
+		if strings.ContainsAny(v, "\r\n") {
+			return p, fmt.Errorf("the value %q of the payee must be a single line", v)
+		}
+	}
+	if p.DefaultCategory != "" {
+		csRepo, err := ns.GetCategoriesRepo()
+		if err != nil {
+			return p, err
+		}
+		if _, err = csRepo.CategoryDAO(p.DefaultCategory); err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
+
+func GetAllPayees(repo *repositories.PayeesRepo) (models.Payees, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("payees repo wasn't initialized")
+	}
+	psDAO, err := repo.GetAllPayees()
+	if err != nil {
+		return nil, err
+	}
+	ps := models.Payees{}
+	for _, pDAO := range psDAO {
+		ps = append(ps, newPayee(pDAO))
+	}
+	return ps, nil
+}
+
+func GetPayeeByID(repo *repositories.PayeesRepo, id int) (models.Payee, error) {
+	ps, err := GetAllPayees(repo)
+	if err != nil {
+		return models.Payee{}, err
+	}
+	for _, p := range ps {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return models.Payee{}, fmt.Errorf("payee %d not found", id)
+}
+
+func checkPayeeNameIsFree(ps models.Payees, p models.Payee) error {
+	for _, other := range ps {
+		if other.ID == p.ID {
+			continue
+		}
+		for _, name := range append([]string{other.Name}, other.Aliases...) {
+			if utils.FoldText(name) == utils.FoldText(p.Name) {
+				return fmt.Errorf("the name %q is already used by the payee %q", p.Name, other.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckPayeeName checks the name of the payee is free.
+func CheckPayeeName(repo *repositories.PayeesRepo, p models.Payee) error {
+	ps, err := GetAllPayees(repo)
+	if err != nil {
+		return err
+	}
+	return checkPayeeNameIsFree(ps, p)
+}
+
+func AddPayee(repo *repositories.PayeesRepo, p models.Payee) (int, error) {
+	ps, err := GetAllPayees(repo)
+	if err != nil {
+		return -1, err
+	}
+	if err = checkPayeeNameIsFree(ps, p); err != nil {
+		return -1, err
+	}
+	p.ID = 1
+	for _, other := range ps {
+		if other.ID >= p.ID {
+			p.ID = other.ID + 1
+		}
+	}
+	if err = repo.AddPayee(newPayeeDAO(p)); err != nil {
+		return -1, err
+	}
+	return p.ID, nil
+}
+
+func UpdatePayee(repo *repositories.PayeesRepo, p models.Payee) error {
+	ps, err := GetAllPayees(repo)
+	if err != nil {
+		return err
+	}
+	if err = checkPayeeNameIsFree(ps, p); err != nil {
+		return err
+	}
+	if err = repo.UpdatePayee(newPayeeDAO(p)); err != nil {
+		return err
+	}
+	invalidateSearchIndex(repo.Namespace)
+	return nil
+}
+
+// DeletePayee deletes the payee and unlinks the transactions from it.
+func DeletePayee(repo *repositories.PayeesRepo, tsRepos []*repositories.TransactionsRepo, id int) error {
+	if repo == nil {
+		return fmt.Errorf("payees repo wasn't initialized")
+	}
+	tpsRepo, err := repo.Namespace.GetTransPayeesRepo()
+	if err != nil {
+		return err
+	}
+	for _, tsRepo := range tsRepos {
+		ts, err := GetAllTransactionsByRepo(tsRepo, false)
+		if err != nil {
+			return err
+		}
+		for _, t := range *ts {
+			if t.PayeeID != id {
+				continue
+			}
+			if err = auditTransactionChange(tsRepo, t.ID); err != nil {
+				return err
+			}
+		}
+	}
+	if err = repo.DeletePayee(id); err != nil {
+		return err
+	}
+	if err = tpsRepo.UnlinkPayee(id); err != nil {
+		return err
+	}
+	for _, tsRepo := range tsRepos {
+		if err = updateRefToTransactions(tsRepo); err != nil {
+			return err
+		}
+	}
+	invalidateSearchIndex(repo.Namespace)
+	return nil
+}
+
+func payeePatternMatches(pattern, foldedDescription string) bool {
+	parts := strings.Split(utils.FoldText(pattern), "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	re, err := regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(foldedDescription)
+}
+
+func containsWords(descriptionWords []string, text string) bool {
+	words := utils.Tokenize(text)
+	if len(words) == 0 {
+		return false
+	}
+	return strings.Contains(" "+strings.Join(descriptionWords, " ")+" ", " "+strings.Join(words, " ")+" ")
+}
+
+func findPayeeByName(ps models.Payees, name string) (models.Payee, bool) {
+	for _, p := range ps {
+		for _, n := range append([]string{p.Name}, p.Aliases...) {
+			if utils.FoldText(n) == utils.FoldText(name) {
+				return p, true
+			}
+		}
+	}
+	return models.Payee{}, false
+}
+
+func resolvePayee(ps models.Payees, description string) (models.Payee, bool) {
+	folded := utils.FoldText(strings.Trim(description, " "))
+	for _, p := range ps {
+		for _, pattern := range p.Patterns {
+			if payeePatternMatches(pattern, folded) {
+				return p, true
+			}
+		}
+	}
+
+	descriptionWords := utils.Tokenize(description)
+	best, bestLength := models.Payee{}, 0
+	for _, p := range ps {
+		for _, n := range append([]string{p.Name}, p.Aliases...) {
+			if len(n) > bestLength && containsWords(descriptionWords, n) {
+				best, bestLength = p, len(n)
+			}
+		}
+	}
+	return best, bestLength > 0
+}
+
+func setTransactionPayee(ns *repositories.Namespace, t *models.Transaction, payeeName string) error {
+	repo, err := ns.GetPayeesRepo()
+	if err != nil {
+		return err
+	}
+	ps, err := GetAllPayees(repo)
+	if err != nil {
+		return err
+	}
+
+	var p models.Payee
+	found := false
+	if payeeName = strings.Trim(payeeName, " "); payeeName != "" {
+		if p, found = findPayeeByName(ps, payeeName); !found {
+			return fmt.Errorf("payee %q not found", payeeName)
+		}
+	} else {
+		p, found = resolvePayee(ps, t.Transaction)
+	}
+	if !found {
+		t.PayeeID = 0
+		return nil
+	}
+
+	t.PayeeID = p.ID
+	if len(t.Categories) == 0 && len(t.Splits) == 0 && p.DefaultCategory != "" {
+		c, err := NewCategory(p.DefaultCategory)
+		if err != nil {
+			return err
+		}
+		t.Categories = append(t.Categories, c)
+	}
+	return nil
+}
+
+// PayeeName is the name of the payee of the transaction, or "" when it has none.
+func PayeeName(ns *repositories.Namespace, t models.Transaction) (string, error) {
+	if t.PayeeID == 0 {
+		return "", nil
+	}
+	repo, err := ns.GetPayeesRepo()
+	if err != nil {
+		return "", err
+	}
+	p, err := GetPayeeByID(repo, t.PayeeID)
+	if err != nil {
+		return "", err
+	}
+	return p.Name, nil
+}
+
+// GetPayeesReport sums the transactions of each payee.
+func GetPayeesReport(repo *repositories.PayeesRepo, etss []EntityTransactions, from, to time.Time,
+	limit int) (*PayeesReportDTO, error) {
+	ps, err := GetAllPayees(repo)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string)
+	for _, p := range ps {
+		names[p.ID] = p.Name
+	}
+
+	totals := make(map[string]*PayeeTotalsDTO)
+	for _, ets := range etss {
+		for _, t := range ets.Transactions {
+			if !from.IsZero() && t.TransactionDate.Before(from) || !to.IsZero() && t.TransactionDate.After(to) {
+				continue
+			}
+			name, found := names[t.PayeeID]
+			if !found {
+				name = models.NoPayeeName
+			}
+			if totals[name] == nil {
+				totals[name] = &PayeeTotalsDTO{Payee: name}
+			}
+			totals[name].Transactions++
+			totals[name].add(signedAmount(t))
+		}
+	}
+
+	report := &PayeesReportDTO{
+		Payees: []PayeeTotalsDTO{},
+	}
+	if !from.IsZero() {
+		report.From = from.Format(utils.DateFormat)
+	}
+	if !to.IsZero() {
+		report.To = to.Format(utils.DateFormat)
+	}
+	var noPayee *PayeeTotalsDTO
+	for name, pt := range totals {
+		if name == models.NoPayeeName {
+			noPayee = pt
+			continue
+		}
+		report.Payees = append(report.Payees, *pt)
+	}
+	sort.Slice(report.Payees, func(i, j int) bool {
+		if report.Payees[i].Expenses != report.Payees[j].Expenses {
+			return report.Payees[i].Expenses > report.Payees[j].Expenses
+		}
+		return report.Payees[i].Payee < report.Payees[j].Payee
+	})
+	if limit > 0 && len(report.Payees) > limit {
+		report.Payees = report.Payees[:limit]
+	}
+	if noPayee != nil {
+		report.Payees = append(report.Payees, *noPayee)
+	}
+	return report, nil
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+const (
+	DateSortField        SortField = "date"
+	AmountSortField      SortField = "amount"
+	TransactionSortField SortField = "transaction"
+	EntitySortField      SortField = "entity"
+	IDSortField          SortField = "id"
+
+	defaultQuerySort  string = "date"
+	DefaultQueryLimit int    = 100
+	MaxQueryLimit     int    = 1000
+
+	fuzzyMaxDistance = 1
+	fuzzyMinLength   = 4
+)
+
+// SortField is a field the transactions can be sorted by.
+type SortField string
+
+var (
+	SortFields = []SortField{
+		DateSortField, AmountSortField, TransactionSortField, EntitySortField, IDSortField,
+	}
+)
+
+// TransactionsQuery filters, sorts and pages the transactions.
+type TransactionsQuery struct {
+	From       time.Time
+	To         time.Time
+	MinAmount  *float32
+	MaxAmount  *float32
+	Text       string
+	Kind       string
+	Status     string
+	Categories *CategoriesFilter
+	Tags       *CategoriesFilter
+	Fields     []FieldCondition
+	Sort       string
+	Limit      int
+	Cursor     string
+}
+
+type queriedTransaction struct {
+	entity      string
+	kind        string
+	transaction models.Transaction
+}
+
+type queryCursor struct {
+	Sort        string  `json:"s"`
+	Date        string  `json:"d"`
+	Amount      float32 `json:"a"`
+	Transaction string  `json:"t"`
+	Entity      string  `json:"e"`
+	Kind        string  `json:"k"`
+	ID          int     `json:"i"`
+}
+
+func SortFieldIsValid(field string) bool {
+	for _, f := range SortFields {
+		if string(f) == field {
+			return true
+		}
+	}
+	return false
+}
+
+func sortFields(s string) ([]SortField, []bool, error) {
+	if s == "" {
+		s = defaultQuerySort
+	}
+	var fields []SortField
+	var descending []bool
+	for _, f := range strings.Split(s, ",") {
+		f = strings.Trim(f, " ")
+		desc := strings.HasPrefix(f, "-")
+		f = strings.TrimPrefix(f, "-")
+		if !SortFieldIsValid(f) {
+			return nil, nil, fmt.Errorf("the value %q for sort is not valid", f)
+		}
+		fields = append(fields, SortField(f))
+		descending = append(descending, desc)
+	}
+	return fields, descending, nil
+}
+
+func decodeQueryCursor(cursor string) (queryCursor, error) {
+	qc := queryCursor{}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return qc, fmt.Errorf("the cursor is not valid")
+	}
+	if err = json.Unmarshal(data, &qc); err != nil {
+		return qc, fmt.Errorf("the cursor is not valid")
+	}
+	return qc, nil
+}
+
+func encodeQueryCursor(sort string, qt queriedTransaction) string {
+	data, _ := json.Marshal(queryCursor{
+		Sort:        sort,
+		Date:        qt.transaction.TransactionDate.Format(utils.DateFormat),
+		Amount:      qt.transaction.Amount,
+		Transaction: qt.transaction.Transaction,
+		Entity:      qt.entity,
+		Kind:        qt.kind,
+		ID:          qt.transaction.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (qc queryCursor) queriedTransaction() (queriedTransaction, error) {
+	date, err := time.Parse(utils.DateFormat, qc.Date)
+	if err != nil {
+		return queriedTransaction{}, fmt.Errorf("the cursor is not valid")
+	}
+	return queriedTransaction{
+		entity: qc.Entity,
+		kind:   qc.Kind,
+		transaction: models.Transaction{
+			ID:              qc.ID,
+			TransactionDate: date,
+			Transaction:     qc.Transaction,
+			Amount:          qc.Amount,
+		},
+	}, nil
+}
+
+// Validate checks the values of the query.
+func (q TransactionsQuery) Validate() error {
+	if q.Kind != "" && q.Kind != models.DebitKindTransaction && q.Kind != models.CreditKindTransaction {
+		return fmt.Errorf("the value %q for kind is not valid", q.Kind)
+	}
+	if q.Status != "" && !TransactionStatusIsValid(q.Status) {
+		return fmt.Errorf("the value %q for status is not valid", q.Status)
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && q.To.Before(q.From) {
+		return fmt.Errorf("the date 'to' %s is before the date 'from' %s", q.To.Format(utils.DateFormat),
+			q.From.Format(utils.DateFormat))
+	}
+	if q.MinAmount != nil && q.MaxAmount != nil && *q.MaxAmount < *q.MinAmount {
+		return fmt.Errorf("the max_amount %.2f is less than the min_amount %.2f", *q.MaxAmount, *q.MinAmount)
+	}
+	if q.Limit < 0 || q.Limit > MaxQueryLimit {
+		return fmt.Errorf("the limit %d is not valid because it must be between 1 and %d", q.Limit, MaxQueryLimit)
+	}
+	if _, _, err := sortFields(q.Sort); err != nil {
+		return err
+	}
+	if q.Cursor != "" {
+		qc, err := decodeQueryCursor(q.Cursor)
+		if err != nil {
+			return err
+		}
+		if qc.Sort != q.Sort {
+			return fmt.Errorf("the cursor was issued for the sort %q and not for %q", qc.Sort, q.Sort)
+		}
+		if _, err = qc.queriedTransaction(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func textMatches(text, description string) bool {
+	folded := utils.FoldText(description)
+	descriptionWords := utils.Tokenize(description)
+	for _, w := range utils.Tokenize(text) {
+		if strings.Contains(folded, w) {
+			continue
+		}
+		matched := false
+		if len([]rune(w)) >= fuzzyMinLength {
+			for _, dw := range descriptionWords {
+				if utils.EditDistance(w, dw) <= fuzzyMaxDistance {
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func (q TransactionsQuery) matches(t models.Transaction) bool {
+	if !q.From.IsZero() && t.TransactionDate.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && t.TransactionDate.After(q.To) {
+		return false
+	}
+	if q.MinAmount != nil && t.Amount < *q.MinAmount {
+		return false
+	}
+	if q.MaxAmount != nil && t.Amount > *q.MaxAmount {
+		return false
+	}
+	if q.Kind != "" && t.Kind != q.Kind {
+		return false
+	}
+	if q.Status != "" && t.Status != q.Status {
+		return false
+	}
+	if q.Categories != nil && !q.Categories.Matches(transactionCategoriesLabels(t)) {
+		return false
+	}
+	if q.Tags != nil && !q.Tags.Matches(t.Tags) {
+		return false
+	}
+	for _, fc := range q.Fields {
+		if !fc.Matches(t) {
+			return false
+		}
+	}
+	if strings.Trim(q.Text, " ") != "" && !textMatches(q.Text, t.Transaction) {
+		return false
+	}
+	return true
+}
+
+func queriedTransactionsLess(a, b queriedTransaction, fields []SortField, descending []bool) bool {
+	for i, f := range fields {
+		c := 0
+		switch f {
+		case DateSortField:
+			c = a.transaction.TransactionDate.Compare(b.transaction.TransactionDate)
+		case AmountSortField:
+			if a.transaction.Amount < b.transaction.Amount {
+				c = -1
+			} else if a.transaction.Amount > b.transaction.Amount {
+				c = 1
+			}
+		case TransactionSortField:
+			c = strings.Compare(utils.FoldText(a.transaction.Transaction), utils.FoldText(b.transaction.Transaction))
+		case EntitySortField:
+			c = strings.Compare(a.entity, b.entity)
+		case IDSortField:
+			c = a.transaction.ID - b.transaction.ID
+		}
+		if c != 0 {
+			return (c < 0) != descending[i]
+		}
+	}
+	if a.entity != b.entity {
+		return a.entity < b.entity
+	}
+	if a.kind != b.kind {
+		return a.kind < b.kind
+	}
+	return a.transaction.ID < b.transaction.ID
+}
+
+func queryTransactions(etss []EntityTransactions, q TransactionsQuery) (qts []queriedTransaction, start, end int, total float32, err error) {
+	if err = q.Validate(); err != nil {
+		return nil, 0, 0, 0, err
+	}
+	fields, descending, _ := sortFields(q.Sort)
+
+	for _, ets := range etss {
+		for _, t := range ets.Transactions {
+			if !q.matches(t) {
+				continue
+			}
+			qts = append(qts, queriedTransaction{entity: ets.Entity, kind: ets.Kind, transaction: t})
+			total += signedAmount(t)
+		}
+	}
+	sort.Slice(qts, func(i, j int) bool {
+		return queriedTransactionsLess(qts[i], qts[j], fields, descending)
+	})
+
+	if q.Cursor != "" {
+		qc, _ := decodeQueryCursor(q.Cursor)
+		last, _ := qc.queriedTransaction()
+		start = sort.Search(len(qts), func(i int) bool {
+			return queriedTransactionsLess(last, qts[i], fields, descending)
+		})
+	}
+	end = len(qts)
+	if q.Limit > 0 && start+q.Limit < end {
+		end = start + q.Limit
+	}
+
+	return qts, start, end, total, nil
+}
+
+// QueryTransactions gets a page of the transactions matching the query.
+func QueryTransactions(ns *repositories.Namespace, etss []EntityTransactions, q TransactionsQuery) (*TransactionsDTO,
+	error) {
+	if q.Limit == 0 {
+		q.Limit = DefaultQueryLimit
+	}
+	qts, start, end, total, err := queryTransactions(etss, q)
+	if err != nil {
+		return nil, err
+	}
+
+	count := len(qts)
+	tsDTO := &TransactionsDTO{
+		TransactionsDTO: []TransactionDTO{},
+		Total:           &total,
+		Count:           &count,
+	}
+	for _, qt := range qts[start:end] {
+		tDTO, err := newTransactionDTO(ns, qt.transaction)
+		if err != nil {
+			return nil, err
+		}
+		tDTO.Entity = qt.entity
+		tDTO.EntityKind = qt.kind
+		tsDTO.TransactionsDTO = append(tsDTO.TransactionsDTO, tDTO)
+	}
+	if end < len(qts) {
+		tsDTO.NextCursor = encodeQueryCursor(q.Sort, qts[end-1])
+	}
+
+	return tsDTO, nil
+}
+
+// QueryEntitiesTransactions gets the transactions matching the query by entity.
+func QueryEntitiesTransactions(etss []EntityTransactions, q TransactionsQuery) ([]EntityTransactions, error) {
+	qts, start, end, _, err := queryTransactions(etss, q)
+	if err != nil {
+		return nil, err
+	}
+
+	queried := make([]EntityTransactions, len(etss))
+	for i, ets := range etss {
+		queried[i] = EntityTransactions{Entity: ets.Entity, Kind: ets.Kind, Transactions: models.Transactions{}}
+	}
+	for _, qt := range qts[start:end] {
+		for i := range queried {
+			if queried[i].Entity == qt.entity && queried[i].Kind == qt.kind {
+				queried[i].Transactions = append(queried[i].Transactions, qt.transaction)
+				break
+			}
+		}
+	}
+
+	return queried, nil
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+const (
+	reconciliationTolerance = 0.005
+)
+
+type ReconciliationDTO struct {
+	ID              string           `json:"id"`
+	Entity          string           `json:"entity"`
+	EntityKind      string           `json:"entity_type"`
+	StatementDate   string           `json:"statement_date"`
+	EndingBalance   float32          `json:"ending_balance"`
+	Status          string           `json:"status"`
+	StartedAt       string           `json:"started_at,omitempty"`
+	FinishedAt      string           `json:"finished_at,omitempty"`
+	TransactionsIDs []string         `json:"transactions_ids,omitempty"`
+	ClearedBalance  *float32         `json:"cleared_balance,omitempty"`
+	Difference      *float32         `json:"difference,omitempty"`
+	Cleared         []TransactionDTO `json:"cleared,omitempty"`
+	Candidates      []TransactionDTO `json:"candidates,omitempty"`
+}
+
+type ReconciliationsDTO []ReconciliationDTO
+
+// NewReconciliationDTO starts a reconciliation.
+type NewReconciliationDTO struct {
+	StatementDate string  `json:"statement_date"`
+	EndingBalance float32 `json:"ending_balance"`
+}
+
+type TransactionStatusDTO struct {
+	Status string `json:"status"`
+	Unlock bool   `json:"unlock,omitempty"`
+}
+
+func TransactionStatusIsValid(status string) bool {
+	for _, s := range models.TransactionStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTransactionStatus checks the status isn't reconciled.
+func CheckTransactionStatus(t models.Transaction) error {
+	if t.Status == models.ReconciledTransactionStatus {
+		return fmt.Errorf("the transactions are reconciled only by finishing a reconciliation")
+	}
+	return nil
+}
+
+// SetTransactionStatus sets the status of the transaction to pending or cleared.
+func SetTransactionStatus(repo *repositories.TransactionsRepo, id int, sDTO TransactionStatusDTO) (models.Transaction,
+	error) {
+	if !TransactionStatusIsValid(sDTO.Status) {
+		return models.Transaction{}, fmt.Errorf("the value %q for status is not valid", sDTO.Status)
+	}
+	t, err := GetTransactionByID(repo, id)
+	if err != nil {
+		return t, err
+	}
+	if err = CheckTransactionStatus(models.Transaction{Status: sDTO.Status}); err != nil {
+		return t, err
+	}
+	if t.Status == models.ReconciledTransactionStatus && !sDTO.Unlock {
+		return t, fmt.Errorf("the transaction %d is reconciled and locked", id)
+	}
+
+	sRepo, err := repo.Namespace.GetTransStatusesRepo()
+	if err != nil {
+		return t, err
+	}
+	if err = auditTransactionChange(repo, id); err != nil {
+		return t, err
+	}
+	if err = sRepo.SetStatus(repo.Entity, repo.Kind, id, sDTO.Status); err != nil {
+		return t, err
+	}
+	t.Status = sDTO.Status
+	if err = updateRefToTransactions(repo); err != nil {
+		return t, err
+	}
+	indexTransaction(repo, t)
+	return t, nil
+}
+
+func newReconciliation(rDAO repositories.ReconciliationDAO) models.Reconciliation {
+	return models.Reconciliation{
+		ID:              rDAO.ID,
+		Entity:          rDAO.Entity,
+		Kind:            rDAO.Kind,
+		StatementDate:   rDAO.StatementDate,
+		EndingBalance:   rDAO.EndingBalance,
+		Status:          rDAO.Status,
+		StartedAt:       rDAO.StartedAt,
+		FinishedAt:      rDAO.FinishedAt,
+		TransactionsIDs: rDAO.TransactionsIDs,
+	}
+}
+
+func newReconciliationDAO(r models.Reconciliation) repositories.ReconciliationDAO {
+	return repositories.ReconciliationDAO{
+		ID:              r.ID,
+		Entity:          r.Entity,
+		Kind:            r.Kind,
+		StatementDate:   r.StatementDate,
+		EndingBalance:   r.EndingBalance,
+		Status:          r.Status,
+		StartedAt:       r.StartedAt,
+		FinishedAt:      r.FinishedAt,
+		TransactionsIDs: r.TransactionsIDs,
+	}
+}
+
+func NewReconciliationDTOFrom(r models.Reconciliation) ReconciliationDTO {
+	rDTO := ReconciliationDTO{
+		ID:            strconv.Itoa(r.ID),
+		Entity:        r.Entity,
+		EntityKind:    r.Kind,
+		StatementDate: r.StatementDate.Format(utils.DateFormat),
+		EndingBalance: r.EndingBalance,
+		Status:        r.Status,
+	}
+	if !r.StartedAt.IsZero() {
+		rDTO.StartedAt = r.StartedAt.Format(time.RFC3339)
+	}
+	if !r.FinishedAt.IsZero() {
+		rDTO.FinishedAt = r.FinishedAt.Format(time.RFC3339)
+	}
+	for _, id := range r.TransactionsIDs {
+		rDTO.TransactionsIDs = append(rDTO.TransactionsIDs, strconv.Itoa(id))
+	}
+	return rDTO
+}
+
+// GetReconciliations gets the reconciliations of the entity.
+func GetReconciliations(repo *repositories.ReconciliationsRepo, tsRepo *repositories.TransactionsRepo) (
+	models.Reconciliations, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("reconciliations repo wasn't initialized")
+	}
+	if tsRepo == nil {
+		return nil, fmt.Errorf("transactions repo wasn't initialized")
+	}
+	rsDAO, err := repo.GetAllReconciliations()
+	if err != nil {
+		return nil, err
+	}
+	rs := models.Reconciliations{}
+	for _, rDAO := range rsDAO {
+		if rDAO.Entity == tsRepo.Entity && rDAO.Kind == tsRepo.Kind {
+			rs = append(rs, newReconciliation(rDAO))
+		}
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].ID > rs[j].ID
+	})
+	return rs, nil
+}
+
+func GetReconciliationByID(repo *repositories.ReconciliationsRepo, tsRepo *repositories.TransactionsRepo,
+	id int) (models.Reconciliation, error) {
+	rs, err := GetReconciliations(repo, tsRepo)
+	if err != nil {
+		return models.Reconciliation{}, err
+	}
+	for _, r := range rs {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return models.Reconciliation{}, fmt.Errorf("reconciliation %d not found", id)
+}
+
+// StartReconciliation starts the reconciliation of the entity against a statement.
+func StartReconciliation(repo *repositories.ReconciliationsRepo, tsRepo *repositories.TransactionsRepo,
+	nrDTO NewReconciliationDTO) (models.Reconciliation, error) {
+	statementDate, err := time.Parse(utils.DateFormat, nrDTO.StatementDate)
+	if err != nil {
+		return models.Reconciliation{}, fmt.Errorf("the statement_date %q is not valid (%s)", nrDTO.StatementDate,
+			utils.DateFormat)
+	}
+	rs, err := GetReconciliations(repo, tsRepo)
+	if err != nil {
+		return models.Reconciliation{}, err
+	}
+	for _, other := range rs {
+		if other.Status == models.OpenReconciliationStatus {
+			return models.Reconciliation{}, fmt.Errorf("the reconciliation %d of the entity is still open", other.ID)
+		}
+		if statementDate.Before(other.StatementDate) {
+			return models.Reconciliation{}, fmt.Errorf("the statement date %s is before the one of the reconciliation %d",
+				nrDTO.StatementDate, other.ID)
+		}
+	}
+
+	all, err := repo.GetAllReconciliations()
+	if err != nil {
+		return models.Reconciliation{}, err
+	}
+	r := models.Reconciliation{
+		ID:            1,
+		Entity:        tsRepo.Entity,
+		Kind:          tsRepo.Kind,
+		StatementDate: statementDate,
+		EndingBalance: nrDTO.EndingBalance,
+		Status:        models.OpenReconciliationStatus,
+		StartedAt:     time.Now().UTC(),
+	}
+	for _, other := range all {
+		if other.ID >= r.ID {
+			r.ID = other.ID + 1
+		}
+	}
+	if err = repo.AddReconciliation(newReconciliationDAO(r)); err != nil {
+		return models.Reconciliation{}, err
+	}
+	return r, nil
+}
+
+// GetReconciliationDetails gets the reconciliation with its balances.
+func GetReconciliationDetails(tsRepo *repositories.TransactionsRepo, r models.Reconciliation) (*ReconciliationDTO,
+	error) {
+	rDTO := NewReconciliationDTOFrom(r)
+	if r.Status != models.OpenReconciliationStatus {
+		return &rDTO, nil
+	}
+
+	ts, err := GetAllTransactionsByRepo(tsRepo, false)
+	if err != nil {
+		return nil, err
+	}
+	bes, err := getEntityBalanceEntries(tsRepo.Namespace, tsRepo.Entity, tsRepo.Kind)
+	if err != nil {
+		return nil, err
+	}
+	clearedBalance := balanceEntriesSum(bes, r.StatementDate)
+	rDTO.Cleared, rDTO.Candidates = []TransactionDTO{}, []TransactionDTO{}
+	for _, t := range *ts {
+		if t.TransactionDate.After(r.StatementDate) {
+			continue
+		}
+		switch t.Status {
+		case models.ReconciledTransactionStatus:
+			clearedBalance += balanceDelta(tsRepo.Kind, t)
+			continue
+		case models.ClearedTransactionStatus:
+			clearedBalance += balanceDelta(tsRepo.Kind, t)
+		}
+		tDTO, err := newTransactionDTO(tsRepo.Namespace, t)
+		if err != nil {
+			return nil, err
+		}
+		if t.Status == models.ClearedTransactionStatus {
+			rDTO.Cleared = append(rDTO.Cleared, tDTO)
+		} else {
+			rDTO.Candidates = append(rDTO.Candidates, tDTO)
+		}
+	}
+	clearedBalance = roundAmount(clearedBalance)
+	difference := roundAmount(r.EndingBalance - clearedBalance)
+	rDTO.ClearedBalance = &clearedBalance
+	rDTO.Difference = &difference
+	return &rDTO, nil
+}
+
+// FinishReconciliation reconciles the cleared transactions.
+func FinishReconciliation(repo *repositories.ReconciliationsRepo, tsRepo *repositories.TransactionsRepo,
+	r models.Reconciliation) (models.Reconciliation, error) {
+	if r.Status != models.OpenReconciliationStatus {
+		return r, fmt.Errorf("the reconciliation %d isn't open", r.ID)
+	}
+	details, err := GetReconciliationDetails(tsRepo, r)
+	if err != nil {
+		return r, err
+	}
+	if math.Abs(float64(*details.Difference)) > reconciliationTolerance {
+		return r, fmt.Errorf("the cleared balance %.2f differs %.2f from the ending balance %.2f", *details.ClearedBalance,
+			*details.Difference, r.EndingBalance)
+	}
+
+	sRepo, err := repo.Namespace.GetTransStatusesRepo()
+	if err != nil {
+		return r, err
+	}
+	r.TransactionsIDs = nil
+	for _, tDTO := range details.Cleared {
+		id, err := strconv.Atoi(tDTO.ID)
+		if err != nil {
+			return r, err
+		}
+		if err = auditTransactionChange(tsRepo, id); err != nil {
+			return r, err
+		}
+		if err = sRepo.SetStatus(tsRepo.Entity, tsRepo.Kind, id, models.ReconciledTransactionStatus); err != nil {
+			return r, err
+		}
+		r.TransactionsIDs = append(r.TransactionsIDs, id)
+	}
+	r.Status = models.FinishedReconciliationStatus
+	r.FinishedAt = time.Now().UTC()
+	if err = repo.UpdateReconciliation(newReconciliationDAO(r)); err != nil {
+		return r, err
+	}
+	if err = updateRefToTransactions(tsRepo); err != nil {
+		return r, err
+	}
+
+	ts, err := GetAllTransactionsByRepo(tsRepo, false)
+	if err != nil {
+		return r, err
+	}
+	for _, t := range *ts {
+		if slices.Contains(r.TransactionsIDs, t.ID) {
+			indexTransaction(tsRepo, t)
+		}
+	}
+	return r, nil
+}
+
+// CancelReconciliation drops the open reconciliation.
+func CancelReconciliation(repo *repositories.ReconciliationsRepo, r models.Reconciliation) error {
+	if r.Status != models.OpenReconciliationStatus {
+		return fmt.Errorf("the reconciliation %d isn't open", r.ID)
+	}
+	return repo.DeleteReconciliation(r.ID)
+}
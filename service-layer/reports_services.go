@@ -0,0 +1,278 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+	"github.com/h-abranches-dev/daily-expenses-be/xlsx"
+)
+
+const (
+	WeekGranularity  Granularity = "week"
+	MonthGranularity Granularity = "month"
+	YearGranularity  Granularity = "year"
+
+	MaxReportPeriods int = 1000
+)
+
+// Granularity is the size of the periods a report is split into.
+type Granularity string
+
+var (
+	Granularities = []Granularity{
+		WeekGranularity, MonthGranularity, YearGranularity,
+	}
+)
+
+type SummaryAmountsDTO struct {
+	Income   float32 `json:"income"`
+	Expenses float32 `json:"expenses"`
+	Net      float32 `json:"net"`
+}
+
+type SummaryPeriodDTO struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	SummaryAmountsDTO
+	Categories      map[string]SummaryAmountsDTO `json:"categories"`
+	Entities        map[string]SummaryAmountsDTO `json:"entities"`
+	OpeningBalances float32                      `json:"opening_balances,omitempty"`
+	Adjustments     float32                      `json:"adjustments,omitempty"`
+}
+
+type SummaryReportDTO struct {
+	From        string             `json:"from"`
+	To          string             `json:"to"`
+	Granularity Granularity        `json:"granularity"`
+	Periods     []SummaryPeriodDTO `json:"periods"`
+	Totals      SummaryPeriodDTO   `json:"totals"`
+}
+
+func GranularityIsValid(granularity string) bool {
+	for _, g := range Granularities {
+		if string(g) == granularity {
+			return true
+		}
+	}
+	return false
+}
+
+func periodStart(date time.Time, granularity Granularity) time.Time {
+	d := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	switch granularity {
+	case WeekGranularity:
+		return d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+	case YearGranularity:
+		return time.Date(d.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func nextPeriodStart(start time.Time, granularity Granularity) time.Time {
+	switch granularity {
+	case WeekGranularity:
+		return start.AddDate(0, 0, 7)
+	case YearGranularity:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+func (a *SummaryAmountsDTO) add(amount float32) {
+	if amount >= 0 {
+		a.Income += amount
+	} else {
+		a.Expenses -= amount
+	}
+	a.Net += amount
+}
+
+func newSummaryPeriodDTO(start, end time.Time) SummaryPeriodDTO {
+	return SummaryPeriodDTO{
+		Start:      start.Format(utils.DateFormat),
+		End:        end.Format(utils.DateFormat),
+		Categories: make(map[string]SummaryAmountsDTO),
+		Entities:   make(map[string]SummaryAmountsDTO),
+	}
+}
+
+func (p *SummaryPeriodDTO) add(entity string, t models.Transaction) {
+	amount := signedAmount(t)
+	p.SummaryAmountsDTO.add(amount)
+
+	ea := p.Entities[entity]
+	ea.add(amount)
+	p.Entities[entity] = ea
+
+	cas := categoriesAmounts(t)
+	if len(cas) == 0 {
+		cas = []categoryAmount{{label: models.NoCategoryLabel, amount: amount}}
+	}
+	for _, ca := range cas {
+		a := p.Categories[ca.label]
+		a.add(ca.amount)
+		p.Categories[ca.label] = a
+	}
+}
+
+func (p *SummaryPeriodDTO) addBalanceEntry(be models.BalanceEntry) {
+	amount := netWorthBalance(be.Kind, be.Amount)
+	if be.Type == models.OpeningBalanceEntry {
+		p.OpeningBalances += amount
+	} else {
+		p.Adjustments += amount
+	}
+}
+
+// GetSummaryReport sums the income and the expenses of each period.
+func GetSummaryReport(ns *repositories.Namespace, etss []EntityTransactions, from, to time.Time,
+	granularity Granularity) (*SummaryReportDTO, error) {
+	if !GranularityIsValid(string(granularity)) {
+		return nil, fmt.Errorf("the value %q for granularity is not valid", granularity)
+	}
+
+	var bes models.BalanceEntries
+	for _, ets := range etss {
+		entityBes, err := getEntityBalanceEntries(ns, ets.Entity, ets.Kind)
+		if err != nil {
+			return nil, err
+		}
+		bes = append(bes, entityBes...)
+	}
+
+	if from.IsZero() || to.IsZero() {
+		first, last := time.Time{}, time.Time{}
+		dates := make([]time.Time, 0, len(bes))
+		for _, be := range bes {
+			dates = append(dates, be.EntryDate)
+		}
+		for _, ets := range etss {
+			for _, t := range ets.Transactions {
+				dates = append(dates, t.TransactionDate)
+			}
+		}
+		for _, d := range dates {
+			if first.IsZero() || d.Before(first) {
+				first = d
+			}
+			if d.After(last) {
+				last = d
+			}
+		}
+		if from.IsZero() {
+			from = first
+		}
+		if to.IsZero() {
+			to = last
+		}
+	}
+	if from.IsZero() && to.IsZero() {
+		from = time.Now().UTC()
+		to = from
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("the date 'to' %s is before the date 'from' %s", to.Format(utils.DateFormat),
+			from.Format(utils.DateFormat))
+	}
+
+	report := &SummaryReportDTO{
+		From:        from.Format(utils.DateFormat),
+		To:          to.Format(utils.DateFormat),
+		Granularity: granularity,
+		Periods:     []SummaryPeriodDTO{},
+		Totals:      newSummaryPeriodDTO(from, to),
+	}
+
+	var starts []time.Time
+	for start := periodStart(from, granularity); !start.After(to); start = nextPeriodStart(start, granularity) {
+		if len(starts) == MaxReportPeriods {
+			return nil, fmt.Errorf("the dates span more than %d periods of a %s", MaxReportPeriods, granularity)
+		}
+		starts = append(starts, start)
+		end := nextPeriodStart(start, granularity).AddDate(0, 0, -1)
+		if end.After(to) {
+			end = to
+		}
+		report.Periods = append(report.Periods, newSummaryPeriodDTO(start, end))
+	}
+
+	for _, ets := range etss {
+		for _, t := range ets.Transactions {
+			if t.TransactionDate.Before(from) || t.TransactionDate.After(to) {
+				continue
+			}
+			idx := sort.Search(len(starts), func(i int) bool {
+				return starts[i].After(t.TransactionDate)
+			}) - 1
+			if idx < 0 {
+				continue
+			}
+			report.Periods[idx].add(ets.Entity, t)
+			report.Totals.add(ets.Entity, t)
+		}
+	}
+	for _, be := range bes {
+		if be.EntryDate.Before(from) || be.EntryDate.After(to) {
+			continue
+		}
+		idx := sort.Search(len(starts), func(i int) bool {
+			return starts[i].After(be.EntryDate)
+		}) - 1
+		if idx < 0 {
+			continue
+		}
+		report.Periods[idx].addBalanceEntry(be)
+		report.Totals.addBalanceEntry(be)
+	}
+
+	return report, nil
+}
+
+// NewSummaryReportWorkbook builds the workbook of the summary report.
+func NewSummaryReportWorkbook(report *SummaryReportDTO) *xlsx.Workbook {
+	wb := xlsx.NewWorkbook()
+	periods := wb.AddSheet(summarySheetName)
+	categories := wb.AddSheet("Categories")
+	entities := wb.AddSheet("Entities")
+
+	periods.AddRow(xlsx.HeaderCell("Start"), xlsx.HeaderCell("End"), xlsx.HeaderCell("Income"),
+		xlsx.HeaderCell("Expenses"), xlsx.HeaderCell("Net"), xlsx.HeaderCell("Opening balances"),
+		xlsx.HeaderCell("Adjustments"))
+	for _, s := range []*xlsx.Sheet{categories, entities} {
+		name := "Category"
+		if s == entities {
+			name = "Entity"
+		}
+		s.AddRow(xlsx.HeaderCell("Start"), xlsx.HeaderCell("End"), xlsx.HeaderCell(name), xlsx.HeaderCell("Income"),
+			xlsx.HeaderCell("Expenses"), xlsx.HeaderCell("Net"))
+	}
+
+	for _, p := range append(report.Periods, report.Totals) {
+		start, _ := time.Parse(utils.DateFormat, p.Start)
+		end, _ := time.Parse(utils.DateFormat, p.End)
+		periods.AddRow(xlsx.DateCell(start), xlsx.DateCell(end), xlsx.NumberCell(cellAmount(p.Income)),
+			xlsx.NumberCell(cellAmount(p.Expenses)), xlsx.NumberCell(cellAmount(p.Net)),
+			xlsx.NumberCell(cellAmount(p.OpeningBalances)), xlsx.NumberCell(cellAmount(p.Adjustments)))
+
+		for s, m := range map[*xlsx.Sheet]map[string]SummaryAmountsDTO{categories: p.Categories, entities: p.Entities} {
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				s.AddRow(xlsx.DateCell(start), xlsx.DateCell(end), xlsx.StringCell(k),
+					xlsx.NumberCell(cellAmount(m[k].Income)), xlsx.NumberCell(cellAmount(m[k].Expenses)),
+					xlsx.NumberCell(cellAmount(m[k].Net)))
+			}
+		}
+	}
+
+	return wb
+}
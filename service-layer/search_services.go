@@ -0,0 +1,295 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+const (
+	DefaultSearchLimit int = 20
+
+	descriptionWeight float64 = 1.0
+	categoryWeight    float64 = 0.7
+	payeeWeight       float64 = 0.7
+	tagWeight         float64 = 0.7
+	memoWeight        float64 = 0.5
+	prefixMatchWeight float64 = 0.5
+)
+
+type SearchHitDTO struct {
+	TransactionDTO
+	Score float32 `json:"score"`
+}
+
+type SearchResultsDTO struct {
+	Query string         `json:"query"`
+	Count int            `json:"count"`
+	Hits  []SearchHitDTO `json:"hits"`
+}
+
+type searchDocKey struct {
+	entity string
+	kind   string
+	id     int
+}
+
+type searchHit struct {
+	key         searchDocKey
+	transaction models.Transaction
+	score       float64
+}
+
+type searchDoc struct {
+	transaction models.Transaction
+	words       map[string]float64
+}
+
+type searchIndex struct {
+	mu       sync.Mutex
+	built    bool
+	docs     map[searchDocKey]searchDoc
+	postings map[string]map[searchDocKey]float64
+	words    []string
+}
+
+var (
+	transactionsSearchIndexes   = map[string]*searchIndex{}
+	transactionsSearchIndexesMu sync.Mutex
+)
+
+func transactionsSearchIndex(ns *repositories.Namespace) *searchIndex {
+	transactionsSearchIndexesMu.Lock()
+	defer transactionsSearchIndexesMu.Unlock()
+	user := ns.User()
+	idx, found := transactionsSearchIndexes[user]
+	if !found {
+		idx = &searchIndex{}
+		transactionsSearchIndexes[user] = idx
+	}
+	return idx
+}
+
+func searchDocWords(ns *repositories.Namespace, t models.Transaction) map[string]float64 {
+	words := make(map[string]float64)
+	for _, w := range utils.Tokenize(t.Transaction) {
+		words[w] += descriptionWeight
+	}
+	for _, l := range transactionCategoriesLabels(t) {
+		for _, w := range utils.Tokenize(l) {
+			words[w] += categoryWeight
+		}
+	}
+	if name, err := PayeeName(ns, t); err == nil {
+		for _, w := range utils.Tokenize(name) {
+			words[w] += payeeWeight
+		}
+	}
+	for _, tag := range t.Tags {
+		for _, w := range utils.Tokenize(tag) {
+			words[w] += tagWeight
+		}
+	}
+	for _, w := range utils.Tokenize(t.Memo) {
+		words[w] += memoWeight
+	}
+	return words
+}
+
+func (idx *searchIndex) remove(key searchDocKey) {
+	doc, found := idx.docs[key]
+	if !found {
+		return
+	}
+	for w := range doc.words {
+		delete(idx.postings[w], key)
+		if len(idx.postings[w]) == 0 {
+			delete(idx.postings, w)
+			idx.words = nil
+		}
+	}
+	delete(idx.docs, key)
+}
+
+func (idx *searchIndex) put(repo *repositories.TransactionsRepo, t models.Transaction) {
+	key := searchDocKey{entity: repo.Entity, kind: repo.Kind, id: t.ID}
+	idx.remove(key)
+
+	doc := searchDoc{
+		transaction: t,
+		words:       searchDocWords(repo.Namespace, t),
+	}
+	for w, weight := range doc.words {
+		if idx.postings[w] == nil {
+			idx.postings[w] = make(map[searchDocKey]float64)
+			idx.words = nil
+		}
+		idx.postings[w][key] = weight
+	}
+	idx.docs[key] = doc
+}
+
+func (idx *searchIndex) build(repos []*repositories.TransactionsRepo) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.built {
+		return nil
+	}
+
+	idx.docs = make(map[searchDocKey]searchDoc)
+	idx.postings = make(map[string]map[searchDocKey]float64)
+	idx.words = nil
+	for _, repo := range repos {
+		ts, err := GetAllTransactionsByRepo(repo, false)
+		if err != nil {
+			return err
+		}
+		for _, t := range *ts {
+			idx.put(repo, t)
+		}
+	}
+	idx.built = true
+	return nil
+}
+
+func indexTransaction(repo *repositories.TransactionsRepo, t models.Transaction) {
+	idx := transactionsSearchIndex(repo.Namespace)
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if !idx.built {
+		return
+	}
+	idx.put(repo, t)
+}
+
+func unindexTransaction(repo *repositories.TransactionsRepo, id int) {
+	idx := transactionsSearchIndex(repo.Namespace)
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if !idx.built {
+		return
+	}
+	idx.remove(searchDocKey{entity: repo.Entity, kind: repo.Kind, id: id})
+}
+
+func invalidateSearchIndex(ns *repositories.Namespace) {
+	idx := transactionsSearchIndex(ns)
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.built = false
+}
+
+func (idx *searchIndex) matchingWords(word string) map[string]float64 {
+	matches := make(map[string]float64)
+	i := sort.SearchStrings(idx.words, word)
+	for ; i < len(idx.words) && strings.HasPrefix(idx.words[i], word); i++ {
+		if idx.words[i] == word {
+			matches[word] = 1
+			continue
+		}
+		matches[idx.words[i]] = prefixMatchWeight * float64(len(word)) / float64(len(idx.words[i]))
+	}
+	return matches
+}
+
+func (idx *searchIndex) search(query string) ([]searchHit, error) {
+	queryWords := utils.Tokenize(query)
+	if len(queryWords) == 0 {
+		return []searchHit{}, nil
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.words == nil {
+		idx.words = make([]string, 0, len(idx.postings))
+		for w := range idx.postings {
+			idx.words = append(idx.words, w)
+		}
+		sort.Strings(idx.words)
+	}
+
+	var scores map[searchDocKey]float64
+	for _, qw := range queryWords {
+		wordScores := make(map[searchDocKey]float64)
+		for w, matchWeight := range idx.matchingWords(qw) {
+			idf := math.Log(1 + float64(len(idx.docs))/float64(len(idx.postings[w])))
+			for key, weight := range idx.postings[w] {
+				wordScores[key] = math.Max(wordScores[key], matchWeight*weight*idf)
+			}
+		}
+		if scores == nil {
+			scores = wordScores
+			continue
+		}
+		for key := range scores {
+			if s, found := wordScores[key]; found {
+				scores[key] += s
+			} else {
+				delete(scores, key)
+			}
+		}
+	}
+
+	hits := make([]searchHit, 0, len(scores))
+	for key, score := range scores {
+		hits = append(hits, searchHit{key: key, transaction: idx.docs[key].transaction, score: score})
+	}
+	return hits, nil
+}
+
+// SearchTransactions gets the transactions best matching the words.
+func SearchTransactions(ns *repositories.Namespace, repos []*repositories.TransactionsRepo, query string,
+	limit int) (*SearchResultsDTO, error) {
+	if limit <= 0 || limit > MaxQueryLimit {
+		return nil, fmt.Errorf("the limit %d is not valid because it must be between 1 and %d", limit, MaxQueryLimit)
+	}
+	idx := transactionsSearchIndex(ns)
+	if err := idx.build(repos); err != nil {
+		return nil, err
+	}
+	hits, err := idx.search(query)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		if !hits[i].transaction.TransactionDate.Equal(hits[j].transaction.TransactionDate) {
+			return hits[i].transaction.TransactionDate.After(hits[j].transaction.TransactionDate)
+		}
+		return queriedTransactionsLess(
+			queriedTransaction{entity: hits[i].key.entity, kind: hits[i].key.kind, transaction: hits[i].transaction},
+			queriedTransaction{entity: hits[j].key.entity, kind: hits[j].key.kind, transaction: hits[j].transaction},
+			nil, nil)
+	})
+
+	results := &SearchResultsDTO{
+		Query: query,
+		Count: len(hits),
+		Hits:  []SearchHitDTO{},
+	}
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	for _, h := range hits {
+		tDTO, err := newTransactionDTO(ns, h.transaction)
+		if err != nil {
+			return nil, err
+		}
+		tDTO.Entity = h.key.entity
+		tDTO.EntityKind = h.key.kind
+		results.Hits = append(results.Hits, SearchHitDTO{
+			TransactionDTO: tDTO,
+			Score:          float32(math.Round(h.score*1000) / 1000),
+		})
+	}
+	return results, nil
+}
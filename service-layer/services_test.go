@@ -0,0 +1,34 @@
+package services
+
+import (
+	"os"
+	"testing"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+)
+
+// TestMain runs the tests in a temporary directory.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "daily-expenses-be")
+	if err != nil {
+		panic(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func addTestUser(t *testing.T, name string) *repositories.Namespace {
+	usersRepo, err := repositories.GetUsersRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = AddUser(usersRepo, models.User{Name: name}); err != nil {
+		t.Fatal(err)
+	}
+	return repositories.UserNamespace(name)
+}
@@ -0,0 +1,626 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+const (
+	sharesTolerance = 0.005
+)
+
+type ShareDTO struct {
+	Participant string   `json:"participant"`
+	Value       *float32 `json:"value,omitempty"`
+	Amount      float32  `json:"amount"`
+}
+
+type SharedTransactionDTO struct {
+	Entity        string     `json:"entity,omitempty"`
+	EntityKind    string     `json:"entity_type,omitempty"`
+	TransactionID string     `json:"transaction_id,omitempty"`
+	PaidBy        string     `json:"paid_by"`
+	Split         string     `json:"split"`
+	Shares        []ShareDTO `json:"shares"`
+}
+
+type SettlementDTO struct {
+	ID     string  `json:"id"`
+	Date   string  `json:"date"`
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Amount float32 `json:"amount"`
+	Note   string  `json:"note,omitempty"`
+}
+
+type SettlementsDTO []SettlementDTO
+
+type ParticipantBalanceDTO struct {
+	Participant string  `json:"participant"`
+	Paid        float32 `json:"paid"`
+	Share       float32 `json:"share"`
+	Settled     float32 `json:"settled"`
+	Balance     float32 `json:"balance"`
+}
+
+type SettlementPaymentDTO struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Amount float32 `json:"amount"`
+}
+
+type SettlementsReportDTO struct {
+	Balances    []ParticipantBalanceDTO `json:"balances"`
+	Payments    []SettlementPaymentDTO  `json:"payments"`
+	Settlements SettlementsDTO          `json:"settlements"`
+}
+
+func ShareSplitIsValid(split string) bool {
+	for _, s := range models.ShareSplits {
+		if s == split {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSharedTransaction validates how the transaction is shared.
+func (stDTO SharedTransactionDTO) NewSharedTransaction(psRepo *repositories.ParticipantsRepo, entity, kind string,
+	tID int, amount float32) (models.SharedTransaction, error) {
+	st := models.SharedTransaction{
+		Entity:        entity,
+		Kind:          kind,
+		TransactionID: tID,
+		Split:         stDTO.Split,
+	}
+	if !ShareSplitIsValid(st.Split) {
+		return st, fmt.Errorf("the value %q for split is not valid", stDTO.Split)
+	}
+	ps, err := GetAllParticipants(psRepo)
+	if err != nil {
+		return st, err
+	}
+	payer, found := findParticipantByName(ps, stDTO.PaidBy)
+	if !found {
+		return st, fmt.Errorf("participant %q not found", stDTO.PaidBy)
+	}
+	st.PayerID = payer.ID
+	if len(stDTO.Shares) == 0 {
+		return st, fmt.Errorf("the transaction must be shared with one participant at least")
+	}
+
+	seen := make(map[int]bool)
+	total := float32(0.0)
+	for _, sDTO := range stDTO.Shares {
+		p, found := findParticipantByName(ps, sDTO.Participant)
+		if !found {
+			return st, fmt.Errorf("participant %q not found", sDTO.Participant)
+		}
+		if seen[p.ID] {
+			return st, fmt.Errorf("the participant %q shares the transaction more than once", p.Name)
+		}
+		seen[p.ID] = true
+		s := models.Share{ParticipantID: p.ID}
+		if st.Split != models.EqualShareSplit {
+			if sDTO.Value == nil || *sDTO.Value < 0 {
+				return st, fmt.Errorf("the share of %q must have a value which isn't negative", p.Name)
+			}
+			s.Value = *sDTO.Value
+			total += s.Value
+		}
+		st.Shares = append(st.Shares, s)
+	}
+	switch st.Split {
+	case models.PercentageShareSplit:
+		if math.Abs(float64(total-100)) > sharesTolerance {
+			return st, fmt.Errorf("the percentages add up to %.2f instead of 100", total)
+		}
+	case models.FixedShareSplit:
+		if math.Abs(float64(total-amount)) > sharesTolerance {
+			return st, fmt.Errorf("the fixed amounts add up to %.2f instead of %.2f", total, amount)
+		}
+	}
+	return st, nil
+}
+
+func newSharedTransaction(stDAO repositories.SharedTransactionDAO) models.SharedTransaction {
+	st := models.SharedTransaction{
+		Entity:        stDAO.Entity,
+		Kind:          stDAO.Kind,
+		TransactionID: stDAO.TransactionID,
+		PayerID:       stDAO.PayerID,
+		Split:         stDAO.Split,
+	}
+	for _, sDAO := range stDAO.Shares {
+		st.Shares = append(st.Shares, models.Share{ParticipantID: sDAO.ParticipantID, Value: sDAO.Value})
+	}
+	return st
+}
+
+func newSharedTransactionDAO(st models.SharedTransaction) repositories.SharedTransactionDAO {
+	stDAO := repositories.SharedTransactionDAO{
+		Entity:        st.Entity,
+		Kind:          st.Kind,
+		TransactionID: st.TransactionID,
+		PayerID:       st.PayerID,
+		Split:         st.Split,
+	}
+	for _, s := range st.Shares {
+		stDAO.Shares = append(stDAO.Shares, repositories.ShareDAO{ParticipantID: s.ParticipantID, Value: s.Value})
+	}
+	return stDAO
+}
+
+func sharesAmounts(st models.SharedTransaction, amount float32) []float32 {
+	amounts := make([]float32, len(st.Shares))
+	sign := float32(1)
+	if amount < 0 {
+		sign = -1
+	}
+	if st.Split == models.FixedShareSplit {
+		for i, s := range st.Shares {
+			amounts[i] = sign * s.Value
+		}
+		return amounts
+	}
+
+	cents := int(math.Round(math.Abs(float64(amount)) * 100))
+	assigned := 0
+	for i, s := range st.Shares {
+		share := cents / len(st.Shares)
+		if st.Split == models.PercentageShareSplit {
+			share = int(math.Floor(float64(cents) * float64(s.Value) / 100))
+		}
+		amounts[i] = float32(share)
+		assigned += share
+	}
+	for i := 0; assigned < cents; i = (i + 1) % len(amounts) {
+		if st.Split == models.PercentageShareSplit && st.Shares[i].Value == 0 {
+			continue
+		}
+		amounts[i]++
+		assigned++
+	}
+	for i := range amounts {
+		amounts[i] = sign * amounts[i] / 100
+	}
+	return amounts
+}
+
+func sharedAmount(t models.Transaction) float32 {
+	return -signedAmount(t)
+}
+
+func participantsNames(ps models.Participants) map[int]string {
+	names := make(map[int]string)
+	for _, p := range ps {
+		names[p.ID] = p.Name
+	}
+	return names
+}
+
+func newSharedTransactionDTO(st models.SharedTransaction, names map[int]string, amount float32) SharedTransactionDTO {
+	stDTO := SharedTransactionDTO{
+		PaidBy: names[st.PayerID],
+		Split:  st.Split,
+		Shares: []ShareDTO{},
+	}
+	for i, a := range sharesAmounts(st, amount) {
+		sDTO := ShareDTO{
+			Participant: names[st.Shares[i].ParticipantID],
+			Amount:      roundAmount(a),
+		}
+		if st.Split != models.EqualShareSplit {
+			value := st.Shares[i].Value
+			sDTO.Value = &value
+		}
+		stDTO.Shares = append(stDTO.Shares, sDTO)
+	}
+	return stDTO
+}
+
+// GetSharedTransaction gets how the transaction is shared.
+func GetSharedTransaction(repo *repositories.TransactionsSharesRepo, psRepo *repositories.ParticipantsRepo,
+	tsRepo *repositories.TransactionsRepo, tID int) (SharedTransactionDTO, error) {
+	if repo == nil {
+		return SharedTransactionDTO{}, fmt.Errorf("transactions shares repo wasn't initialized")
+	}
+	t, err := GetTransactionByID(tsRepo, tID)
+	if err != nil {
+		return SharedTransactionDTO{}, err
+	}
+	stDAO, found, err := repo.GetSharedTransaction(tsRepo.Entity, tsRepo.Kind, tID)
+	if err != nil {
+		return SharedTransactionDTO{}, err
+	}
+	if !found {
+		return SharedTransactionDTO{}, fmt.Errorf("the transaction %d isn't shared", tID)
+	}
+	ps, err := GetAllParticipants(psRepo)
+	if err != nil {
+		return SharedTransactionDTO{}, err
+	}
+	return newSharedTransactionDTO(newSharedTransaction(stDAO), participantsNames(ps), sharedAmount(t)), nil
+}
+
+func SetSharedTransaction(repo *repositories.TransactionsSharesRepo, st models.SharedTransaction) error {
+	if repo == nil {
+		return fmt.Errorf("transactions shares repo wasn't initialized")
+	}
+	return repo.SetSharedTransaction(newSharedTransactionDAO(st))
+}
+
+// DeleteSharedTransaction stops sharing the transaction.
+func DeleteSharedTransaction(repo *repositories.TransactionsSharesRepo, entity, kind string, tID int) error {
+	if repo == nil {
+		return fmt.Errorf("transactions shares repo wasn't initialized")
+	}
+	return repo.DeleteSharedTransaction(entity, kind, tID)
+}
+
+// NewSettlement validates the settlement.
+func (sDTO SettlementDTO) NewSettlement(psRepo *repositories.ParticipantsRepo, today time.Time) (models.Settlement, error) {
+	s := models.Settlement{
+		SettlementDate: today,
+		Amount:         roundAmount(sDTO.Amount),
+		Note:           strings.Trim(sDTO.Note, " "),
+	}
+	if s.Amount <= 0 {
+		return s, fmt.Errorf("the amount %.2f is not valid because it must be positive", sDTO.Amount)
+	}
+	if date := strings.Trim(sDTO.Date, " "); date != "" {
+		var err error
+		if s.SettlementDate, err = time.Parse(utils.DateFormat, date); err != nil {
+			return s, fmt.Errorf("the date %q is not valid (%s)", sDTO.Date, utils.DateFormat)
+		}
+	}
+	ps, err := GetAllParticipants(psRepo)
+	if err != nil {
+		return s, err
+	}
+	from, found := findParticipantByName(ps, sDTO.From)
+	if !found {
+		return s, fmt.Errorf("participant %q not found", sDTO.From)
+	}
+	to, found := findParticipantByName(ps, sDTO.To)
+	if !found {
+		return s, fmt.Errorf("participant %q not found", sDTO.To)
+	}
+	if from.ID == to.ID {
+		return s, fmt.Errorf("the participant %q can't settle with itself", from.Name)
+	}
+	s.FromID, s.ToID = from.ID, to.ID
+	return s, nil
+}
+
+func newSettlement(sDAO repositories.SettlementDAO) models.Settlement {
+	return models.Settlement{
+		ID:             sDAO.ID,
+		SettlementDate: sDAO.SettlementDate,
+		FromID:         sDAO.FromID,
+		ToID:           sDAO.ToID,
+		Amount:         sDAO.Amount,
+		Note:           sDAO.Note,
+	}
+}
+
+func newSettlementDAO(s models.Settlement) repositories.SettlementDAO {
+	return repositories.SettlementDAO{
+		ID:             s.ID,
+		SettlementDate: s.SettlementDate,
+		FromID:         s.FromID,
+		ToID:           s.ToID,
+		Amount:         s.Amount,
+		Note:           s.Note,
+	}
+}
+
+func newSettlementDTO(s models.Settlement, names map[int]string) SettlementDTO {
+	return SettlementDTO{
+		ID:     strconv.Itoa(s.ID),
+		Date:   s.SettlementDate.Format(utils.DateFormat),
+		From:   names[s.FromID],
+		To:     names[s.ToID],
+		Amount: s.Amount,
+		Note:   s.Note,
+	}
+}
+
+// GetAllSettlements gets the settlements sorted by date.
+func GetAllSettlements(repo *repositories.SettlementsRepo) (models.Settlements, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("settlements repo wasn't initialized")
+	}
+	ssDAO, err := repo.GetAllSettlements()
+	if err != nil {
+		return nil, err
+	}
+	ss := models.Settlements{}
+	for _, sDAO := range ssDAO {
+		ss = append(ss, newSettlement(sDAO))
+	}
+	sort.SliceStable(ss, func(i, j int) bool {
+		if !ss[i].SettlementDate.Equal(ss[j].SettlementDate) {
+			return ss[i].SettlementDate.Before(ss[j].SettlementDate)
+		}
+		return ss[i].ID < ss[j].ID
+	})
+	return ss, nil
+}
+
+func AddSettlement(repo *repositories.SettlementsRepo, s models.Settlement) (int, error) {
+	ss, err := GetAllSettlements(repo)
+	if err != nil {
+		return -1, err
+	}
+	s.ID = 1
+	for _, other := range ss {
+		if other.ID >= s.ID {
+			s.ID = other.ID + 1
+		}
+	}
+	if err = repo.AddSettlement(newSettlementDAO(s)); err != nil {
+		return -1, err
+	}
+	return s.ID, nil
+}
+
+func NewSettlementDTO(psRepo *repositories.ParticipantsRepo, s models.Settlement) (SettlementDTO, error) {
+	ps, err := GetAllParticipants(psRepo)
+	if err != nil {
+		return SettlementDTO{}, err
+	}
+	return newSettlementDTO(s, participantsNames(ps)), nil
+}
+
+func DeleteSettlement(repo *repositories.SettlementsRepo, id int) error {
+	if repo == nil {
+		return fmt.Errorf("settlements repo wasn't initialized")
+	}
+	return repo.DeleteSettlement(id)
+}
+
+// GetSettlementsReport computes who owes whom.
+func GetSettlementsReport(shRepo *repositories.TransactionsSharesRepo, psRepo *repositories.ParticipantsRepo,
+	sRepo *repositories.SettlementsRepo) (*SettlementsReportDTO, error) {
+	if shRepo == nil {
+		return nil, fmt.Errorf("transactions shares repo wasn't initialized")
+	}
+	ps, err := GetAllParticipants(psRepo)
+	if err != nil {
+		return nil, err
+	}
+	names := participantsNames(ps)
+	balances := make(map[int]*ParticipantBalanceDTO)
+	for _, p := range ps {
+		balances[p.ID] = &ParticipantBalanceDTO{Participant: p.Name}
+	}
+
+	stsDAO, err := shRepo.GetAllSharedTransactions()
+	if err != nil {
+		return nil, err
+	}
+	for _, stDAO := range stsDAO {
+		tsRepo, err := shRepo.Namespace.GetTransRepo(stDAO.Kind, stDAO.Entity)
+		if err != nil {
+			return nil, err
+		}
+		t, err := GetTransactionByID(tsRepo, stDAO.TransactionID)
+		if err != nil {
+			return nil, err
+		}
+		st := newSharedTransaction(stDAO)
+		amount := sharedAmount(t)
+		if b := balances[st.PayerID]; b != nil {
+			b.Paid += amount
+		}
+		for i, a := range sharesAmounts(st, amount) {
+			if b := balances[st.Shares[i].ParticipantID]; b != nil {
+				b.Share += a
+			}
+		}
+	}
+
+	ss, err := GetAllSettlements(sRepo)
+	if err != nil {
+		return nil, err
+	}
+	report := &SettlementsReportDTO{
+		Balances:    []ParticipantBalanceDTO{},
+		Payments:    []SettlementPaymentDTO{},
+		Settlements: SettlementsDTO{},
+	}
+	for _, s := range ss {
+		if b := balances[s.FromID]; b != nil {
+			b.Settled += s.Amount
+		}
+		if b := balances[s.ToID]; b != nil {
+			b.Settled -= s.Amount
+		}
+		report.Settlements = append(report.Settlements, newSettlementDTO(s, names))
+	}
+
+	type debt struct {
+		name  string
+		cents int
+	}
+	var creditors, debtors []debt
+	for _, p := range ps {
+		b := balances[p.ID]
+		b.Paid, b.Share, b.Settled = roundAmount(b.Paid), roundAmount(b.Share), roundAmount(b.Settled)
+		b.Balance = roundAmount(b.Paid - b.Share + b.Settled)
+		report.Balances = append(report.Balances, *b)
+		cents := int(math.Round(float64(b.Balance) * 100))
+		if cents > 0 {
+			creditors = append(creditors, debt{name: p.Name, cents: cents})
+		} else if cents < 0 {
+			debtors = append(debtors, debt{name: p.Name, cents: -cents})
+		}
+	}
+
+	for len(creditors) > 0 && len(debtors) > 0 {
+		sort.SliceStable(creditors, func(i, j int) bool { return creditors[i].cents > creditors[j].cents })
+		sort.SliceStable(debtors, func(i, j int) bool { return debtors[i].cents > debtors[j].cents })
+		cents := creditors[0].cents
+		if debtors[0].cents < cents {
+			cents = debtors[0].cents
+		}
+		report.Payments = append(report.Payments, SettlementPaymentDTO{
+			From:   debtors[0].name,
+			To:     creditors[0].name,
+			Amount: float32(cents) / 100,
+		})
+		if creditors[0].cents -= cents; creditors[0].cents == 0 {
+			creditors = creditors[1:]
+		}
+		if debtors[0].cents -= cents; debtors[0].cents == 0 {
+			debtors = debtors[1:]
+		}
+	}
+	return report, nil
+}
+
+func exportSharedTransactions(ns *repositories.Namespace) ([]SharedTransactionDTO, error) {
+	repo, err := ns.GetTransSharesRepo()
+	if err != nil {
+		return nil, err
+	}
+	stsDAO, err := repo.GetAllSharedTransactions()
+	if err != nil {
+		return nil, err
+	}
+	psRepo, err := ns.GetParticipantsRepo()
+	if err != nil {
+		return nil, err
+	}
+	ps, err := GetAllParticipants(psRepo)
+	if err != nil {
+		return nil, err
+	}
+	names := participantsNames(ps)
+	var stsDTO []SharedTransactionDTO
+	for _, stDAO := range stsDAO {
+		tsRepo, err := ns.GetTransRepo(stDAO.Kind, stDAO.Entity)
+		if err != nil {
+			return nil, err
+		}
+		t, err := GetTransactionByID(tsRepo, stDAO.TransactionID)
+		if err != nil {
+			return nil, err
+		}
+		stDTO := newSharedTransactionDTO(newSharedTransaction(stDAO), names, sharedAmount(t))
+		stDTO.Entity, stDTO.EntityKind = stDAO.Entity, stDAO.Kind
+		stDTO.TransactionID = strconv.Itoa(stDAO.TransactionID)
+		stsDTO = append(stsDTO, stDTO)
+	}
+	return stsDTO, nil
+}
+
+func importSharedTransaction(repo *repositories.TransactionsSharesRepo, psRepo *repositories.ParticipantsRepo,
+	stDTO SharedTransactionDTO, tID int, counters *ImportCountersDTO) error {
+	_, found, err := repo.GetSharedTransaction(stDTO.Entity, stDTO.EntityKind, tID)
+	if err != nil {
+		return err
+	}
+	if found {
+		counters.Skipped++
+		return nil
+	}
+	tsRepo, err := repo.Namespace.GetTransRepo(stDTO.EntityKind, stDTO.Entity)
+	if err != nil {
+		return err
+	}
+	t, err := GetTransactionByID(tsRepo, tID)
+	if err != nil {
+		return err
+	}
+	st, err := stDTO.NewSharedTransaction(psRepo, stDTO.Entity, stDTO.EntityKind, tID, t.Amount)
+	if err != nil {
+		return err
+	}
+	if err = SetSharedTransaction(repo, st); err != nil {
+		return err
+	}
+	counters.Created++
+	return nil
+}
+
+func exportSettlements(ns *repositories.Namespace) (SettlementsDTO, error) {
+	repo, err := ns.GetSettlementsRepo()
+	if err != nil {
+		return nil, err
+	}
+	ss, err := GetAllSettlements(repo)
+	if err != nil {
+		return nil, err
+	}
+	psRepo, err := ns.GetParticipantsRepo()
+	if err != nil {
+		return nil, err
+	}
+	ps, err := GetAllParticipants(psRepo)
+	if err != nil {
+		return nil, err
+	}
+	names := participantsNames(ps)
+	var ssDTO SettlementsDTO
+	for _, s := range ss {
+		ssDTO = append(ssDTO, newSettlementDTO(s, names))
+	}
+	return ssDTO, nil
+}
+
+func importSettlement(repo *repositories.SettlementsRepo, psRepo *repositories.ParticipantsRepo, sDTO SettlementDTO,
+	strategy ImportStrategy, counters *ImportCountersDTO) error {
+	sID, err := strconv.Atoi(sDTO.ID)
+	if err != nil {
+		return fmt.Errorf("invalid settlement ID %q", sDTO.ID)
+	}
+	s, err := sDTO.NewSettlement(psRepo, time.Now().UTC().Truncate(24*time.Hour))
+	if err != nil {
+		return err
+	}
+	s.ID = sID
+
+	ss, err := GetAllSettlements(repo)
+	if err != nil {
+		return err
+	}
+	exists := false
+	for _, other := range ss {
+		exists = exists || other.ID == sID
+	}
+	if !exists {
+		if err = repo.AddSettlement(newSettlementDAO(s)); err != nil {
+			return err
+		}
+		counters.Created++
+		return nil
+	}
+	switch strategy {
+	case OverwriteImportStrategy:
+		if err = repo.DeleteSettlement(sID); err != nil {
+			return err
+		}
+		if err = repo.AddSettlement(newSettlementDAO(s)); err != nil {
+			return err
+		}
+		counters.Updated++
+	case RenumberImportStrategy:
+		if _, err = AddSettlement(repo, s); err != nil {
+			return err
+		}
+		counters.Renumbered++
+	default:
+		counters.Skipped++
+	}
+	return nil
+}
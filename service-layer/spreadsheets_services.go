@@ -0,0 +1,109 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/xlsx"
+)
+
+const (
+	summarySheetName string = "Summary"
+)
+
+// EntityTransactions are the transactions of one entity.
+type EntityTransactions struct {
+	Entity       string
+	Kind         string
+	Transactions models.Transactions
+}
+
+// GetEntitiesTransactions gets the transactions of each repository.
+func GetEntitiesTransactions(repos []*repositories.TransactionsRepo, categoriesFilter *CategoriesFilter) ([]EntityTransactions, error) {
+	var etss []EntityTransactions
+	for _, repo := range repos {
+		ts, err := GetAllTransactionsByRepo(repo, false)
+		if err != nil {
+			return nil, err
+		}
+		ets := EntityTransactions{
+			Entity:       repo.Entity,
+			Kind:         repo.Kind,
+			Transactions: *ts,
+		}
+		if categoriesFilter != nil {
+			ets.Transactions = FilterTransactionsByCategories(*ts, categoriesFilter)
+		}
+		etss = append(etss, ets)
+	}
+	return etss, nil
+}
+
+// NewTransactionsWorkbook builds a workbook with a sheet per entity.
+func NewTransactionsWorkbook(etss []EntityTransactions) *xlsx.Workbook {
+	wb := xlsx.NewWorkbook()
+
+	summary := wb.AddSheet(summarySheetName)
+	categoriesTotals := make(map[string]float64)
+	categoriesCounts := make(map[string]int)
+
+	for _, ets := range etss {
+		sheet := wb.AddSheet(ets.Entity)
+		sheet.AddRow(xlsx.HeaderCell("ID"), xlsx.HeaderCell("Date"), xlsx.HeaderCell("Transaction"),
+			xlsx.HeaderCell("Categories"), xlsx.HeaderCell("Type"), xlsx.HeaderCell("Amount"))
+
+		total := 0.0
+		for _, t := range ets.Transactions {
+			labels := transactionCategoriesLabels(t)
+			sheet.AddRow(xlsx.IntegerCell(t.ID), xlsx.DateCell(t.TransactionDate), xlsx.StringCell(t.Transaction),
+				xlsx.StringCell(strings.Join(labels, ", ")), xlsx.StringCell(t.Kind), xlsx.NumberCell(cellAmount(t.Amount)))
+			total += cellAmount(signedAmount(t))
+
+			for _, ca := range categoriesAmounts(t) {
+				categoriesTotals[ca.label] += cellAmount(ca.amount)
+			}
+			for _, l := range labels {
+				categoriesCounts[l]++
+			}
+		}
+
+		sheet.AddRow(xlsx.HeaderCell("Total"), xlsx.StringCell(""), xlsx.StringCell(""), xlsx.StringCell(""),
+			xlsx.StringCell(""), xlsx.NumberCell(total))
+	}
+
+	labels := make([]string, 0, len(categoriesTotals))
+	for l := range categoriesTotals {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	summary.AddRow(xlsx.HeaderCell("Category"), xlsx.HeaderCell("Transactions"), xlsx.HeaderCell("Total"))
+	for _, l := range labels {
+		summary.AddRow(xlsx.StringCell(l), xlsx.IntegerCell(categoriesCounts[l]),
+			xlsx.NumberCell(categoriesTotals[l]))
+	}
+
+	return wb
+}
+
+// NewCategoriesCountsWorkbook builds a workbook with the counts by category.
+func NewCategoriesCountsWorkbook(m map[string]int) *xlsx.Workbook {
+	wb := xlsx.NewWorkbook()
+	sheet := wb.AddSheet("Categories")
+	labels := make([]string, 0, len(m))
+	for l := range m {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	sheet.AddRow(xlsx.HeaderCell("Category"), xlsx.HeaderCell("Transactions"))
+	for _, l := range labels {
+		sheet.AddRow(xlsx.StringCell(l), xlsx.IntegerCell(m[l]))
+	}
+	return wb
+}
+
+func cellAmount(amount float32) float64 {
+	return math.Round(float64(amount)*100) / 100
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+	"github.com/h-abranches-dev/daily-expenses-be/utils"
+)
+
+const (
+	tagPrefix string = "#"
+)
+
+type TagDTO struct {
+	ID           string `json:"id"`
+	Tag          string `json:"tag"`
+	Transactions int    `json:"transactions"`
+}
+
+type TagsDTO []TagDTO
+
+type TagTotalsDTO struct {
+	Tag          string `json:"tag"`
+	Transactions int    `json:"transactions"`
+	SummaryAmountsDTO
+}
+
+type TagsReportDTO struct {
+	From string         `json:"from,omitempty"`
+	To   string         `json:"to,omitempty"`
+	Tags []TagTotalsDTO `json:"tags"`
+}
+
+// NormalizeTag lowercases the tag and drops its leading "#".
+func NormalizeTag(tag string) (string, error) {
+	normalized := strings.ToLower(strings.TrimPrefix(strings.Trim(tag, " "), tagPrefix))
+	if normalized == "" {
+		return "", fmt.Errorf("the tag %q is empty", tag)
+	}
+	for _, r := range normalized {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_.", r) {
+			return "", fmt.Errorf("the tag %q is not valid because it includes the char %q", tag, r)
+		}
+	}
+	return normalized, nil
+}
+
+func newTags(tags []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		n, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[n] {
+			seen[n] = true
+			normalized = append(normalized, n)
+		}
+	}
+	return normalized, nil
+}
+
+// ParseTagsFilter parses a boolean expression over the tags, e.g. "vacation-2026,!reimbursable".
+func ParseTagsFilter(expression string) (*CategoriesFilter, error) {
+	f, err := ParseCategoriesFilter(expression)
+	if err != nil {
+		return nil, fmt.Errorf("the tags filter is not valid: %w", err)
+	}
+	if err = f.normalizeTags(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *CategoriesFilter) normalizeTags() error {
+	if f.Operator == LabelCategoriesFilterOperator {
+		var err error
+		f.Label, err = NormalizeTag(f.Label)
+		return err
+	}
+	for _, o := range f.Operands {
+		if err := o.normalizeTags(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAllTags gets the tags with their counts.
+func GetAllTags(repo *repositories.TagsRepo, etss []EntityTransactions) (TagsDTO, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("tags repo wasn't initialized")
+	}
+	tagsDAO, err := repo.GetAllTags()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, ets := range etss {
+		for _, t := range ets.Transactions {
+			for _, tag := range t.Tags {
+				counts[tag]++
+			}
+		}
+	}
+
+	tagsDTO := TagsDTO{}
+	for _, tDAO := range tagsDAO {
+		tagsDTO = append(tagsDTO, TagDTO{
+			ID:           strconv.Itoa(tDAO.ID),
+			Tag:          tDAO.Tag,
+			Transactions: counts[tDAO.Tag],
+		})
+	}
+	sort.Slice(tagsDTO, func(i, j int) bool {
+		return tagsDTO[i].Tag < tagsDTO[j].Tag
+	})
+	return tagsDTO, nil
+}
+
+// GetTagsReport sums the transactions of each tag.
+func GetTagsReport(etss []EntityTransactions, from, to time.Time, limit int) *TagsReportDTO {
+	totals := make(map[string]*TagTotalsDTO)
+	for _, ets := range etss {
+		for _, t := range ets.Transactions {
+			if !from.IsZero() && t.TransactionDate.Before(from) || !to.IsZero() && t.TransactionDate.After(to) {
+				continue
+			}
+			for _, tag := range t.Tags {
+				if totals[tag] == nil {
+					totals[tag] = &TagTotalsDTO{Tag: tag}
+				}
+				totals[tag].Transactions++
+				totals[tag].add(signedAmount(t))
+			}
+		}
+	}
+
+	report := &TagsReportDTO{
+		Tags: []TagTotalsDTO{},
+	}
+	if !from.IsZero() {
+		report.From = from.Format(utils.DateFormat)
+	}
+	if !to.IsZero() {
+		report.To = to.Format(utils.DateFormat)
+	}
+	for _, tt := range totals {
+		report.Tags = append(report.Tags, *tt)
+	}
+	sort.Slice(report.Tags, func(i, j int) bool {
+		if report.Tags[i].Expenses != report.Tags[j].Expenses {
+			return report.Tags[i].Expenses > report.Tags[j].Expenses
+		}
+		return report.Tags[i].Tag < report.Tags[j].Tag
+	})
+	if limit > 0 && len(report.Tags) > limit {
+		report.Tags = report.Tags[:limit]
+	}
+	return report
+}
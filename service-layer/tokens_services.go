@@ -0,0 +1,275 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+)
+
+const (
+	tokenPrefix    string = "dex_"
+	tokenSizeBytes int    = 32
+)
+
+type TokenDTO struct {
+	ID        string   `json:"id"`
+	User      string   `json:"user"`
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	CreatedAt string   `json:"created_at,omitempty"`
+	Token     string   `json:"token,omitempty"`
+}
+
+type TokensDTO []TokenDTO
+
+func NewTokenDTO(usersRepo *repositories.UsersRepo, t models.Token) (TokenDTO, error) {
+	u, err := GetUserByID(usersRepo, t.UserID)
+	if err != nil {
+		return TokenDTO{}, err
+	}
+	return TokenDTO{
+		ID:        strconv.Itoa(t.ID),
+		User:      u.Name,
+		Name:      t.Name,
+		Scopes:    t.Scopes,
+		CreatedAt: t.CreatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+func NewTokensDTO(usersRepo *repositories.UsersRepo, ts models.Tokens) (TokensDTO, error) {
+	tsDTO := TokensDTO{}
+	for _, t := range ts {
+		tDTO, err := NewTokenDTO(usersRepo, t)
+		if err != nil {
+			return nil, err
+		}
+		tsDTO = append(tsDTO, tDTO)
+	}
+	return tsDTO, nil
+}
+
+// NewToken checks the token to create.
+func (tDTO TokenDTO) NewToken(usersRepo *repositories.UsersRepo, defaultUser models.User,
+	now time.Time) (models.Token, error) {
+	t := models.Token{
+		UserID:    defaultUser.ID,
+		Name:      strings.Trim(tDTO.Name, " "),
+		CreatedAt: now,
+	}
+	if t.Name == "" {
+		return t, fmt.Errorf("the name of the token is mandatory")
+	}
+	if userName := strings.Trim(tDTO.User, " "); userName != "" {
+		u, err := GetUserByName(usersRepo, userName)
+		if err != nil {
+			return t, err
+		}
+		t.UserID = u.ID
+	}
+	var err error
+	if t.Scopes, err = newScopes(tDTO.Scopes); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+func newScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("the scopes are mandatory")
+	}
+	checked := []string{}
+	for _, scope := range scopes {
+		if !slices.Contains(models.Scopes, scope) {
+			return nil, fmt.Errorf("the scope %q is not valid because it must be one of %s", scope,
+				strings.Join(models.Scopes, ", "))
+		}
+		if !slices.Contains(checked, scope) {
+			checked = append(checked, scope)
+		}
+	}
+	return checked, nil
+}
+
+func newToken(tDAO repositories.TokenDAO) models.Token {
+	return models.Token{
+		ID:        tDAO.ID,
+		UserID:    tDAO.UserID,
+		Name:      tDAO.Name,
+		Scopes:    tDAO.Scopes,
+		Hash:      tDAO.Hash,
+		CreatedAt: tDAO.CreatedAt,
+	}
+}
+
+func newTokenDAO(t models.Token) repositories.TokenDAO {
+	return repositories.TokenDAO{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		Scopes:    t.Scopes,
+		Hash:      t.Hash,
+		CreatedAt: t.CreatedAt,
+		Name:      t.Name,
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ScopesGrant tells if the scopes grant the scope.
+func ScopesGrant(scopes []string, scope string) bool {
+	required := -1
+	for i, s := range models.Scopes {
+		if s == scope {
+			required = i
+		}
+	}
+	if required == -1 {
+		return false
+	}
+	for i, s := range models.Scopes {
+		if i >= required && slices.Contains(scopes, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func GetAllTokens(repo *repositories.TokensRepo) (models.Tokens, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("tokens repo wasn't initialized")
+	}
+	tsDAO, err := repo.GetAllTokens()
+	if err != nil {
+		return nil, err
+	}
+	ts := models.Tokens{}
+	for _, tDAO := range tsDAO {
+		ts = append(ts, newToken(tDAO))
+	}
+	return ts, nil
+}
+
+func GetTokenByID(repo *repositories.TokensRepo, id int) (models.Token, error) {
+	ts, err := GetAllTokens(repo)
+	if err != nil {
+		return models.Token{}, err
+	}
+	for _, t := range ts {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return models.Token{}, fmt.Errorf("token %d not found", id)
+}
+
+// AddToken generates the token and keeps its hash.
+func AddToken(repo *repositories.TokensRepo, t models.Token) (models.Token, string, error) {
+	ts, err := GetAllTokens(repo)
+	if err != nil {
+		return t, "", err
+	}
+	secret := make([]byte, tokenSizeBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return t, "", err
+	}
+	token := tokenPrefix + hex.EncodeToString(secret)
+	t.Hash = hashToken(token)
+
+	t.ID = 1
+	for _, other := range ts {
+		if other.ID >= t.ID {
+			t.ID = other.ID + 1
+		}
+	}
+	if err = repo.AddToken(newTokenDAO(t)); err != nil {
+		return t, "", err
+	}
+	return t, token, nil
+}
+
+func DeleteToken(repo *repositories.TokensRepo, id int) error {
+	if repo == nil {
+		return fmt.Errorf("tokens repo wasn't initialized")
+	}
+	return repo.DeleteToken(id)
+}
+
+// AuthenticateToken gets the token, and its user, of the bearer token of a request.
+func AuthenticateToken(repo *repositories.TokensRepo, usersRepo *repositories.UsersRepo,
+	token string) (models.Token, models.User, error) {
+	if !strings.HasPrefix(token, tokenPrefix) {
+		return models.Token{}, models.User{}, fmt.Errorf("the token is not valid")
+	}
+	ts, err := GetAllTokens(repo)
+	if err != nil {
+		return models.Token{}, models.User{}, err
+	}
+	hash := hashToken(token)
+	for _, t := range ts {
+		if t.Hash == hash {
+			u, err := GetUserByID(usersRepo, t.UserID)
+			if err != nil {
+				return models.Token{}, models.User{}, err
+			}
+			return t, u, nil
+		}
+	}
+	return models.Token{}, models.User{}, fmt.Errorf("the token is not valid")
+}
+
+// BootstrapAdminToken creates the first admin user and token.
+func BootstrapAdminToken(repo *repositories.TokensRepo, usersRepo *repositories.UsersRepo, userName string,
+	now time.Time) (string, error) {
+	ts, err := GetAllTokens(repo)
+	if err != nil {
+		return "", err
+	}
+	us, err := GetAllUsers(usersRepo)
+	if err != nil {
+		return "", err
+	}
+	if len(ts) > 0 {
+		if slices.ContainsFunc(us, func(u models.User) bool { return u.ServerAdmin }) {
+			return "", nil
+		}
+		u, err := GetUserByName(usersRepo, userName)
+		if err != nil {
+			return "", nil
+		}
+		u.ServerAdmin = true
+		return "", usersRepo.UpdateUser(newUserDAO(u))
+	}
+
+	u, err := GetUserByName(usersRepo, userName)
+	if err != nil {
+		if u, err = (UserDTO{Name: userName}).NewUser(); err != nil {
+			return "", err
+		}
+		if u.ID, err = AddUser(usersRepo, u); err != nil {
+			return "", err
+		}
+	}
+	if !u.ServerAdmin {
+		u.ServerAdmin = true
+		if err = usersRepo.UpdateUser(newUserDAO(u)); err != nil {
+			return "", err
+		}
+	}
+	_, token, err := AddToken(repo, models.Token{
+		UserID:    u.ID,
+		Name:      "bootstrap",
+		Scopes:    []string{models.AdminScope},
+		CreatedAt: now,
+	})
+	return token, err
+}
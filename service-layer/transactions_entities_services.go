@@ -6,12 +6,15 @@ import (
 	"strings"
 models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
 repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+"github.com/h-abranches-dev/daily-expenses-be/utils"
+"time"
 )
 type TransactionsEntityDTO struct {
 	ID      string `json:"id"`
 	Entity  string `json:"entity"`
 	Kind    string `json:"type"`
 	Balance string `json:"balance"`
+	OpeningDate string `json:"opening_date,omitempty"`
 }
 
 type TransactionsEntitiesDTO []TransactionsEntityDTO
@@ -61,6 +64,12 @@ func (tseDTO TransactionsEntityDTO) NewTransactionsEntity() (models.Transactions
 		}
 	}
 
+	if tseDTO.OpeningDate != "" {
+		if _, err := time.Parse(utils.DateFormat, tseDTO.OpeningDate); err != nil {
+			return tse, fmt.Errorf("the opening_date %q is not valid (%s)", tseDTO.OpeningDate, utils.DateFormat)
+		}
+	}
+
 	tse.Entity = tseDTO.Entity
 	tse.Kind = tseDTO.Kind
 	tse.Balance = float32(balance)
@@ -129,6 +138,9 @@ func AddTransactionsEntity(repo *repositories.TransactionsEntitiesRepo, tse mode
 	if err != nil {
 		return -1, err
 	}
+	if err = auditEntityChange(repo.Namespace, tseDAO); err != nil {
+		return -1, err
+	}
 	err = repo.AddTransactionsEntity(tseDAO)
 	if err != nil {
 		return -1, err
@@ -141,6 +153,9 @@ func updateTransactionsEntity(repo *repositories.TransactionsEntitiesRepo, tse m
 		return fmt.Errorf("transactions entities repo wasn't initialized")
 	}
 	tseDAO := newTransactionsEntityDAO(tse)
+	if err := auditEntityChange(repo.Namespace, tseDAO); err != nil {
+		return err
+	}
 	err := repo.UpdateTransactionsEntity(tseDAO)
 	if err != nil {
 		return err
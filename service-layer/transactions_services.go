@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
@@ -9,22 +10,43 @@ repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
 "github.com/h-abranches-dev/daily-expenses-be/utils"
 "time"
 )
+const (
+	splitsAmountsTolerance = 0.005
+)
+
 type TransactionDTO struct {
-	ID              string   `json:"id"`
-	TransactionDate string   `json:"transaction_date"`
-	Transaction     string   `json:"transaction"`
-	Categories      []string `json:"categories"`
-	Kind            string   `json:"type,omitempty"`
-	Amount          float32  `json:"amount"`
+	ID              string         `json:"id"`
+	TransactionDate string         `json:"transaction_date"`
+	Transaction     string         `json:"transaction"`
+	Categories      []string       `json:"categories"`
+	Kind            string         `json:"type,omitempty"`
+	Amount          float32        `json:"amount"`
+	Splits          []SplitDTO     `json:"splits,omitempty"`
+	Payee           string         `json:"payee,omitempty"`
+	Tags            []string       `json:"tags,omitempty"`
+	Memo            string         `json:"memo,omitempty"`
+	Fields          map[string]any `json:"fields,omitempty"`
+	Status          string         `json:"status,omitempty"`
+	RunningBalance  *float32       `json:"running_balance,omitempty"`
+	Entity          string         `json:"entity,omitempty"`
+	EntityKind      string         `json:"entity_type,omitempty"`
+}
+
+type SplitDTO struct {
+	Category string  `json:"category"`
+	Amount   float32 `json:"amount"`
+	Note     string  `json:"note,omitempty"`
 }
 
 type TransactionsDTO struct {
 	TransactionsDTO []TransactionDTO `json:"transactions"`
 	Total           *float32         `json:"total,omitempty"`
+	Count           *int             `json:"count,omitempty"`
+	NextCursor      string           `json:"next_cursor,omitempty"`
 }
 
-func newTransactionDTO(t models.Transaction) (TransactionDTO, error) {
-	categoriesRepo, err := repositories.GetCategoriesRepo()
+func newTransactionDTO(ns *repositories.Namespace, t models.Transaction) (TransactionDTO, error) {
+	categoriesRepo, err := ns.GetCategoriesRepo()
 	if err != nil {
 		return TransactionDTO{}, err
 	}
@@ -44,6 +66,20 @@ func newTransactionDTO(t models.Transaction) (TransactionDTO, error) {
 		categoriesLabels = append(categoriesLabels, c.Label)
 	}
 
+	var splitsDTO []SplitDTO
+	for _, sp := range t.Splits {
+		splitsDTO = append(splitsDTO, SplitDTO{
+			Category: sp.Category.Label,
+			Amount:   sp.Amount,
+			Note:     sp.Note,
+		})
+	}
+
+	payee, err := PayeeName(ns, t)
+	if err != nil {
+		return TransactionDTO{}, err
+	}
+
 	return TransactionDTO{
 		ID:              strconv.Itoa(t.ID),
 		TransactionDate: t.TransactionDate.Format(utils.DateFormat),
@@ -51,13 +87,19 @@ func newTransactionDTO(t models.Transaction) (TransactionDTO, error) {
 		Categories:      categoriesLabels,
 		Kind:            t.Kind,
 		Amount:          t.Amount,
+		Splits:          splitsDTO,
+		Payee:           payee,
+		Tags:            t.Tags,
+		Memo:            t.Memo,
+		Fields:          NewFieldsDTO(t.Fields),
+		Status:          t.Status,
 	}, nil
 }
 
-func NewTransactionsDTO(ts models.Transactions) (TransactionsDTO, error) {
+func NewTransactionsDTO(ns *repositories.Namespace, ts models.Transactions) (TransactionsDTO, error) {
 	var tsDTO TransactionsDTO
 	for i := 0; i < len(ts); i++ {
-		ntDTO, err := newTransactionDTO(ts[i])
+		ntDTO, err := newTransactionDTO(ns, ts[i])
 		if err != nil {
 			return TransactionsDTO{}, err
 		}
@@ -72,6 +114,24 @@ func newTransaction(tDAO repositories.TransactionDAO) models.Transaction {
 		categories = append(categories, models.Category(c))
 	}
 
+	var splits models.Splits
+	for _, sp := range tDAO.Splits {
+		splits = append(splits, models.Split{
+			Category: newCategory(sp.Category),
+			Amount:   sp.Amount,
+			Note:     sp.Note,
+		})
+	}
+
+	var fields models.FieldValues
+	for _, fv := range tDAO.Fields {
+		fields = append(fields, models.FieldValue{
+			Name:  fv.Name,
+			Type:  models.CustomFieldType(fv.Type),
+			Value: fv.Value,
+		})
+	}
+
 	return models.Transaction{
 		ID:              tDAO.ID,
 		TransactionDate: tDAO.TransactionDate,
@@ -79,6 +139,12 @@ func newTransaction(tDAO repositories.TransactionDAO) models.Transaction {
 		Categories:      categories,
 		Kind:            tDAO.Kind,
 		Amount:          tDAO.Amount,
+		Splits:          splits,
+		PayeeID:         tDAO.PayeeID,
+		Tags:            tDAO.Tags,
+		Memo:            tDAO.Memo,
+		Fields:          fields,
+		Status:          tDAO.Status,
 	}
 }
 
@@ -96,11 +162,30 @@ func NewCategory(categoryLabel string) (models.Category, error) {
 	}, nil
 }
 
-func (tDTO TransactionDTO) NewTransaction() (models.Transaction, error) {
+// NewTransaction validates the DTO of a transaction of an entity of the kind.
+func (tDTO TransactionDTO) NewTransaction(ns *repositories.Namespace, entityKind string) (models.Transaction, error) {
 	t := models.Transaction{}
 	if (tDTO.Kind != models.DebitKindTransaction) && (tDTO.Kind != models.CreditKindTransaction) && (tDTO.Kind != "") {
 		return t, fmt.Errorf("the value %q for type field is not valid", tDTO.Kind)
 	}
+	kind, amount := tDTO.Kind, tDTO.Amount
+	switch models.TransactionKind(entityKind) {
+	case models.DebitBankAccountKind:
+		if kind == "" {
+			kind, amount = kindFromSignedAmount(amount)
+		}
+	case models.DebitCreditBankAccountKind:
+		if kind == "" {
+			return t, fmt.Errorf("the type field is mandatory for the entities of kind %q", entityKind)
+		}
+	default:
+		return t, fmt.Errorf("the entity kind %q is not valid", entityKind)
+	}
+	if amount <= 0 {
+		return t, fmt.Errorf("the amount %.2f is not valid because it must be positive, the type telling if it's a %s or a %s",
+			amount, models.DebitKindTransaction, models.CreditKindTransaction)
+	}
+
 	tDate, err := time.Parse(utils.DateFormat, strings.Trim(tDTO.TransactionDate, " "))
 	if err != nil {
 		return t, err
@@ -119,18 +204,199 @@ func (tDTO TransactionDTO) NewTransaction() (models.Transaction, error) {
 	t.TransactionDate = tDate
 	t.Transaction = strings.Trim(tDTO.Transaction, " ")
 	t.Categories = categories
-	t.Kind = tDTO.Kind
-	t.Amount = tDTO.Amount
+	t.Kind = kind
+	t.Amount = amount
+
+	if len(tDTO.Splits) != 0 {
+		if t.Splits, err = newSplits(tDTO.Splits, amount); err != nil {
+			return t, err
+		}
+		t.Categories = make([]models.Category, 0)
+		seen := make(map[string]bool)
+		for _, sp := range t.Splits {
+			if !seen[sp.Category.Label] {
+				seen[sp.Category.Label] = true
+				t.Categories = append(t.Categories, sp.Category)
+			}
+		}
+	}
+
+	if t.Tags, err = newTags(tDTO.Tags); err != nil {
+		return t, err
+	}
+
+	if tDTO.Status != "" && !TransactionStatusIsValid(tDTO.Status) {
+		return t, fmt.Errorf("the value %q for status is not valid", tDTO.Status)
+	}
+	t.Status = tDTO.Status
+
+	t.Memo = strings.Trim(tDTO.Memo, " ")
+	if strings.ContainsAny(t.Memo, "\r\n") {
+		return t, fmt.Errorf("the memo must be a single line")
+	}
+	for name, v := range tDTO.Fields {
+		value, err := fieldValueString(name, v)
+		if err != nil {
+			return t, err
+		}
+		t.Fields = append(t.Fields, models.FieldValue{Name: name, Value: value})
+	}
+
+	if err = setTransactionPayee(ns, &t, tDTO.Payee); err != nil {
+		return t, err
+	}
 
 	return t, nil
 }
 
+func newSplits(splitsDTO []SplitDTO, amount float32) (models.Splits, error) {
+	splits := make(models.Splits, 0, len(splitsDTO))
+	sum := float32(0.0)
+	for _, spDTO := range splitsDTO {
+		label := strings.Trim(spDTO.Category, " ")
+		if label == "" {
+			return nil, fmt.Errorf("the category of the split is mandatory")
+		}
+		if spDTO.Amount <= 0 {
+			return nil, fmt.Errorf("the amount %.2f of the split of category %q is not valid because it must be positive",
+				spDTO.Amount, label)
+		}
+		note := strings.Trim(spDTO.Note, " ")
+		if strings.ContainsAny(note, "\r\n") {
+			return nil, fmt.Errorf("the note of the split of category %q must be a single line", label)
+		}
+		c, err := NewCategory(label)
+		if err != nil {
+			return nil, err
+		}
+		splits = append(splits, models.Split{
+			Category: c,
+			Amount:   spDTO.Amount,
+			Note:     note,
+		})
+		sum += spDTO.Amount
+	}
+	if math.Abs(float64(sum-amount)) >= splitsAmountsTolerance {
+		return nil, fmt.Errorf("the amounts of the splits add up to %.2f instead of the amount %.2f", sum, amount)
+	}
+	return splits, nil
+}
+
+type categoryAmount struct {
+	label  string
+	amount float32
+}
+
+func categoriesAmounts(t models.Transaction) []categoryAmount {
+	var cas []categoryAmount
+	if len(t.Splits) != 0 {
+		sign := signedAmount(t) / t.Amount
+		for _, sp := range t.Splits {
+			cas = append(cas, categoryAmount{label: sp.Category.Label, amount: sign * sp.Amount})
+		}
+		return cas
+	}
+	for _, c := range t.Categories {
+		cas = append(cas, categoryAmount{label: c.Label, amount: signedAmount(t)})
+	}
+	return cas
+}
+
+// CheckTransactionCategories checks the categories of the transaction exist.
+func CheckTransactionCategories(ns *repositories.Namespace, t models.Transaction) error {
+	csRepo, err := ns.GetCategoriesRepo()
+	if err != nil {
+		return err
+	}
+	for _, c := range t.Categories {
+		if _, err = csRepo.CategoryDAO(c.Label); err != nil {
+			return err
+		}
+	}
+	for _, sp := range t.Splits {
+		if _, err = csRepo.CategoryDAO(sp.Category.Label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func kindFromSignedAmount(amount float32) (string, float32) {
+	if amount < 0 {
+		return models.DebitKindTransaction, -amount
+	}
+	return models.CreditKindTransaction, amount
+}
+
+func normalizeLegacyAmount(kind string, amount float32) (string, float32) {
+	if kind == "" {
+		return kindFromSignedAmount(amount)
+	}
+	if amount < 0 {
+		return kind, -amount
+	}
+	return kind, amount
+}
+
+// NormalizeTransactionsAmounts migrates the transactions with negative amounts.
+func NormalizeTransactionsAmounts(tsesRepo *repositories.TransactionsEntitiesRepo, tsRepos []*repositories.TransactionsRepo) (int, error) {
+	normalized := 0
+	for _, tsRepo := range tsRepos {
+		ts, err := GetAllTransactionsByRepo(tsRepo, true)
+		if err != nil {
+			return normalized, err
+		}
+		for _, t := range *ts {
+			kind, amount := normalizeLegacyAmount(t.Kind, t.Amount)
+			if kind == t.Kind && amount == t.Amount {
+				continue
+			}
+			t.Kind, t.Amount = kind, amount
+			if err = auditTransactionChange(tsRepo, t.ID); err != nil {
+				return normalized, err
+			}
+			if err = tsRepo.UpdateTransaction(newTransactionDAO(t)); err != nil {
+				return normalized, err
+			}
+			normalized++
+		}
+		if err = updateRefToTransactions(tsRepo); err != nil {
+			return normalized, err
+		}
+		if err = updateBalance(tsesRepo, tsRepo); err != nil {
+			return normalized, err
+		}
+	}
+	if normalized > 0 {
+		invalidateSearchIndex(tsesRepo.Namespace)
+	}
+	return normalized, nil
+}
+
 func newTransactionDAO(t models.Transaction) repositories.TransactionDAO {
 	categories := make(repositories.CategoriesDAO, 0)
 	for _, c := range t.Categories {
 		categories = append(categories, newCategoryDAO(c))
 	}
 
+	var splits repositories.SplitsDAO
+	for _, sp := range t.Splits {
+		splits = append(splits, repositories.SplitDAO{
+			Category: newCategoryDAO(sp.Category),
+			Amount:   sp.Amount,
+			Note:     sp.Note,
+		})
+	}
+
+	var fields repositories.FieldValuesDAO
+	for _, fv := range t.Fields {
+		fields = append(fields, repositories.FieldValueDAO{
+			Name:  fv.Name,
+			Type:  string(fv.Type),
+			Value: fv.Value,
+		})
+	}
+
 	return repositories.TransactionDAO{
 		ID:              t.ID,
 		TransactionDate: t.TransactionDate,
@@ -138,6 +404,12 @@ func newTransactionDAO(t models.Transaction) repositories.TransactionDAO {
 		Categories:      categories,
 		Kind:            t.Kind,
 		Amount:          t.Amount,
+		Splits:          splits,
+		PayeeID:         t.PayeeID,
+		Tags:            t.Tags,
+		Memo:            t.Memo,
+		Fields:          fields,
+		Status:          t.Status,
 	}
 }
 
@@ -145,8 +417,7 @@ func GetAllTransactionsByRepo(repo *repositories.TransactionsRepo, mandatoryUseO
 	if repo == nil {
 		return nil, fmt.Errorf("transactions repo wasn't initialized")
 	}
-	key := models.EntityKindKey(models.TransactionEntity(repo.Entity), models.TransactionKind(repo.Kind))
-	refToTransactions := (*models.RefsToTransactions)[key]
+	refToTransactions := repo.RefToTransactions
 	if refToTransactions == nil || mandatoryUseOfDB {
 		tsDAO, err := repo.GetAllTransactions()
 		if err != nil {
@@ -155,7 +426,7 @@ func GetAllTransactionsByRepo(repo *repositories.TransactionsRepo, mandatoryUseO
 		refToTransactions = new(models.Transactions)
 		*refToTransactions = newTransactions(tsDAO)
 	}
-	(*models.RefsToTransactions)[key] = refToTransactions
+	repo.RefToTransactions = refToTransactions
 	return refToTransactions, nil
 }
 
@@ -165,8 +436,7 @@ func GetAllTransactions(repos []*repositories.TransactionsRepo, mandatoryUseOfDB
 		if r == nil {
 			return nil, fmt.Errorf("transactions repo wasn't initialized")
 		}
-		key := models.EntityKindKey(models.TransactionEntity(r.Entity), models.TransactionKind(r.Kind))
-		refToTransactions := (*models.RefsToTransactions)[key]
+		refToTransactions := r.RefToTransactions
 		if refToTransactions == nil || mandatoryUseOfDB {
 			tsDAO, err := r.GetAllTransactions()
 			if err != nil {
@@ -175,7 +445,7 @@ func GetAllTransactions(repos []*repositories.TransactionsRepo, mandatoryUseOfDB
 			refToTransactions = new(models.Transactions)
 			*refToTransactions = newTransactions(tsDAO)
 		}
-		(*models.RefsToTransactions)[key] = refToTransactions
+		r.RefToTransactions = refToTransactions
 
 		*allTransactions = append(*allTransactions, *refToTransactions...)
 	}
@@ -187,7 +457,7 @@ func transactionsNextAvailableID(repo *repositories.TransactionsRepo) (int, erro
 	if repo == nil {
 		return -1, fmt.Errorf("transactions repo wasn't initialized")
 	}
-	refToTransactions := (*models.RefsToTransactions)[fmt.Sprintf("%s_#_%s", repo.Entity, repo.Kind)]
+	refToTransactions := repo.RefToTransactions
 	if refToTransactions == nil {
 		tsDAO, err := repo.GetAllTransactions()
 		if err != nil {
@@ -211,14 +481,36 @@ func transactionsNextAvailableID(repo *repositories.TransactionsRepo) (int, erro
 	return maxID + 1, nil
 }
 
+func signedAmount(t models.Transaction) float32 {
+	if t.Kind == models.DebitKindTransaction {
+		return -t.Amount
+	}
+	return t.Amount
+}
+
+func roundAmount(amount float32) float32 {
+	return float32(math.Round(float64(amount)*100) / 100)
+}
+
+func balanceDelta(entityKind string, t models.Transaction) float32 {
+	if models.AccountNatures[models.TransactionKind(entityKind)] == models.LiabilityAccountNature {
+		return -signedAmount(t)
+	}
+	return signedAmount(t)
+}
+
 func getCurrentBalance(repo *repositories.TransactionsRepo) (float32, error) {
-	sum := float32(0.0)
 	ts, err := GetAllTransactionsByRepo(repo, false)
 	if err != nil {
 		return 0.0, err
 	}
+	bes, err := getEntityBalanceEntries(repo.Namespace, repo.Entity, repo.Kind)
+	if err != nil {
+		return 0.0, err
+	}
+	sum := balanceEntriesSum(bes, time.Time{})
 	for _, t := range *ts {
-		sum += t.Amount
+		sum += balanceDelta(repo.Kind, t)
 	}
 	return sum, nil
 }
@@ -245,6 +537,9 @@ func AddTransaction(repo *repositories.TransactionsRepo, t models.Transaction) (
 		return -1, err
 	}
 
+	if err = auditTransactionChange(repo, tDAO.ID); err != nil {
+		return -1, err
+	}
 	if err = repo.AddTransaction(tDAO); err != nil {
 		return -1, err
 	}
@@ -253,7 +548,7 @@ func AddTransaction(repo *repositories.TransactionsRepo, t models.Transaction) (
 		return -1, err
 	}
 
-	tsesRepo, err := repositories.NewTransactionsEntitiesRepo()
+	tsesRepo, err := repo.Namespace.GetTransEntRepo()
 	if err != nil {
 		return -1, err
 	}
@@ -263,6 +558,12 @@ func AddTransaction(repo *repositories.TransactionsRepo, t models.Transaction) (
 		return -1, err
 	}
 
+	t.ID = tDAO.ID
+	if t.Status == "" {
+		t.Status = models.PendingTransactionStatus
+	}
+	indexTransaction(repo, t)
+
 	return tDAO.ID, nil
 }
 
@@ -271,8 +572,21 @@ func UpdateTransaction(repo *repositories.TransactionsRepo, t models.Transaction
 		return fmt.Errorf("transactions repo wasn't initialized")
 	}
 
-	var err error
+	existing, err := GetTransactionByID(repo, t.ID)
+	if err != nil {
+		return err
+	}
+	if existing.Status == models.ReconciledTransactionStatus {
+		return fmt.Errorf("the transaction %d is reconciled and locked", t.ID)
+	}
+	if t.Status == "" {
+		t.Status = existing.Status
+	}
+
 	tDAO := newTransactionDAO(t)
+	if err = auditTransactionChange(repo, t.ID); err != nil {
+		return err
+	}
 	if err = repo.UpdateTransaction(tDAO); err != nil {
 		return err
 	}
@@ -281,7 +595,7 @@ func UpdateTransaction(repo *repositories.TransactionsRepo, t models.Transaction
 		return err
 	}
 
-	tsesRepo, err := repositories.NewTransactionsEntitiesRepo()
+	tsesRepo, err := repo.Namespace.GetTransEntRepo()
 	if err != nil {
 		return err
 	}
@@ -290,6 +604,47 @@ func UpdateTransaction(repo *repositories.TransactionsRepo, t models.Transaction
 		return err
 	}
 
+	indexTransaction(repo, t)
+
+	return nil
+}
+
+// DeleteTransaction deletes the transaction with its linked data.
+func DeleteTransaction(repo *repositories.TransactionsRepo, id int) error {
+	if repo == nil {
+		return fmt.Errorf("transactions repo wasn't initialized")
+	}
+
+	existing, err := GetTransactionByID(repo, id)
+	if err != nil {
+		return err
+	}
+	if existing.Status == models.ReconciledTransactionStatus {
+		return fmt.Errorf("the transaction %d is reconciled and locked", id)
+	}
+
+	if err = auditTransactionChange(repo, id); err != nil {
+		return err
+	}
+	if err = repo.DeleteTransaction(id); err != nil {
+		return err
+	}
+
+	if err = updateRefToTransactions(repo); err != nil {
+		return err
+	}
+
+	tsesRepo, err := repo.Namespace.GetTransEntRepo()
+	if err != nil {
+		return err
+	}
+	err = updateBalance(tsesRepo, repo)
+	if err != nil {
+		return err
+	}
+
+	unindexTransaction(repo, id)
+
 	return nil
 }
 
@@ -314,6 +669,9 @@ func updateBalance(tsesRepo *repositories.TransactionsEntitiesRepo, tsRepo *repo
 
 	tseDAO.Balance = balance
 
+	if err = auditEntityChange(tsesRepo.Namespace, tseDAO); err != nil {
+		return err
+	}
 	if err = tsesRepo.UpdateTransactionsEntity(tseDAO); err != nil {
 		return err
 	}
@@ -332,38 +690,14 @@ func GetCategoriesLabelsInTransactionsMap(transactions *models.Transactions) map
 	return m
 }
 
-func GetTransactionsFilteredByCategories(transactions *models.Transactions, categoriesLabelsProvided []string) (*TransactionsDTO, error) {
-	tsDTO := &TransactionsDTO{}
-	total := new(float32)
-	*total = float32(0.0)
-	for _, t := range *transactions {
-		match := true
-		counter := 0
-		match = false
-		for _, clp := range categoriesLabelsProvided {
-			for _, c := range t.Categories {
-				if clp == c.Label {
-					counter++
-					if counter == len(categoriesLabelsProvided) {
-						match = true
-						break
-					}
-				}
-			}
-			if match {
-				break
-			}
+// FilterTransactionsByCategories keeps the transactions satisfying the filter.
+func FilterTransactionsByCategories(transactions models.Transactions, filter *CategoriesFilter) models.Transactions {
+	ts := models.Transactions{}
+	for _, t := range transactions {
+		if filter.Matches(transactionCategoriesLabels(t)) {
+			ts = append(ts, t)
 		}
-		if match {
-			ntDTO, err := newTransactionDTO(t)
-			if err != nil {
-				return nil, err
-			}
-			tsDTO.TransactionsDTO = append(tsDTO.TransactionsDTO, ntDTO)
-			*total += t.Amount
-		}
-		tsDTO.Total = total
 	}
 
-	return tsDTO, nil
+	return ts
 }
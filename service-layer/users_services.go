@@ -0,0 +1,201 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	models "github.com/h-abranches-dev/daily-expenses-be/domain-layer"
+	repositories "github.com/h-abranches-dev/daily-expenses-be/persistence-layer"
+)
+
+var (
+	userNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+)
+
+type UserDTO struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Password    string   `json:"password,omitempty"`
+	Scopes      []string `json:"scopes,omitempty"`
+	ServerAdmin bool     `json:"server_admin,omitempty"`
+}
+
+type UsersDTO []UserDTO
+
+func NewUserDTO(u models.User) UserDTO {
+	return UserDTO{
+		ID:          strconv.Itoa(u.ID),
+		Name:        u.Name,
+		ServerAdmin: u.ServerAdmin,
+	}
+}
+
+func NewUsersDTO(us models.Users) UsersDTO {
+	usDTO := UsersDTO{}
+	for _, u := range us {
+		usDTO = append(usDTO, NewUserDTO(u))
+	}
+	return usDTO
+}
+
+func (uDTO UserDTO) NewUser() (models.User, error) {
+	u := models.User{
+		Name:        strings.Trim(uDTO.Name, " "),
+		ServerAdmin: uDTO.ServerAdmin,
+	}
+	if !userNameRegexp.MatchString(u.Name) {
+		return u, fmt.Errorf("the name %q is not valid because it must have up to 32 lowercase letters, digits, "+
+			"'-' or '_', starting with a letter or a digit", u.Name)
+	}
+	if len(uDTO.Scopes) > 0 {
+		if uDTO.Password == "" {
+			return u, fmt.Errorf("the scopes are of the sessions, which need a password")
+		}
+		if _, err := newScopes(uDTO.Scopes); err != nil {
+			return u, err
+		}
+	}
+	if uDTO.Password != "" {
+		if err := CheckPassword(uDTO.Password); err != nil {
+			return u, err
+		}
+	}
+	return u, nil
+}
+
+func newUser(uDAO repositories.UserDAO) models.User {
+	return models.User{
+		ID:          uDAO.ID,
+		Name:        uDAO.Name,
+		ServerAdmin: uDAO.ServerAdmin,
+	}
+}
+
+func newUserDAO(u models.User) repositories.UserDAO {
+	return repositories.UserDAO{
+		ID:          u.ID,
+		Name:        u.Name,
+		ServerAdmin: u.ServerAdmin,
+	}
+}
+
+func GetAllUsers(repo *repositories.UsersRepo) (models.Users, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("users repo wasn't initialized")
+	}
+	usDAO, err := repo.GetAllUsers()
+	if err != nil {
+		return nil, err
+	}
+	us := models.Users{}
+	for _, uDAO := range usDAO {
+		us = append(us, newUser(uDAO))
+	}
+	return us, nil
+}
+
+func GetUserByID(repo *repositories.UsersRepo, id int) (models.User, error) {
+	us, err := GetAllUsers(repo)
+	if err != nil {
+		return models.User{}, err
+	}
+	for _, u := range us {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return models.User{}, fmt.Errorf("user %d not found", id)
+}
+
+func GetUserByName(repo *repositories.UsersRepo, name string) (models.User, error) {
+	us, err := GetAllUsers(repo)
+	if err != nil {
+		return models.User{}, err
+	}
+	for _, u := range us {
+		if u.Name == name {
+			return u, nil
+		}
+	}
+	return models.User{}, fmt.Errorf("user %q not found", name)
+}
+
+// AddUser adds the user with their namespace.
+func AddUser(repo *repositories.UsersRepo, u models.User) (int, error) {
+	us, err := GetAllUsers(repo)
+	if err != nil {
+		return -1, err
+	}
+	u.ID = 1
+	for _, other := range us {
+		if other.Name == u.Name {
+			return -1, fmt.Errorf("the name %q is already used by the user %d", u.Name, other.ID)
+		}
+		if other.ID >= u.ID {
+			u.ID = other.ID + 1
+		}
+	}
+	if len(us) == 0 {
+		if err = repositories.AdoptLegacyDBFiles(u.Name); err != nil {
+			return -1, err
+		}
+	}
+	if err = repo.AddUser(newUserDAO(u)); err != nil {
+		return -1, err
+	}
+
+	// nobody else uses the namespace of the new user yet
+	ns := repositories.UserNamespace(u.Name)
+	ns.Lock()
+	defer ns.Unlock()
+	if err = addMissingTransactionsEntities(ns); err != nil {
+		return -1, err
+	}
+	if err = addDefaultCategories(ns); err != nil {
+		return -1, err
+	}
+	return u.ID, nil
+}
+
+func addDefaultCategories(ns *repositories.Namespace) error {
+	csRepo, err := ns.GetCategoriesRepo()
+	if err != nil {
+		return err
+	}
+	csDAO, err := csRepo.GetAllCategories()
+	if err != nil || len(csDAO) > 0 {
+		return err
+	}
+	for _, label := range models.DefaultCategoriesLabels {
+		if _, err = AddCategory(csRepo, models.Category{Label: label}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addMissingTransactionsEntities(ns *repositories.Namespace) error {
+	tsesRepo, err := ns.GetTransEntRepo()
+	if err != nil {
+		return err
+	}
+	for i, entity := range models.TransactionEntities {
+		tse := models.TransactionsEntity{
+			Entity: string(entity),
+			Kind:   string(models.TransactionKinds[i]),
+		}
+		tseDAO, err := tsesRepo.GetTransactionsEntity(tse.Entity, tse.Kind)
+		if err != nil {
+			return err
+		}
+		if tseDAO.ID != 0 {
+			continue
+		}
+		if _, err = AddTransactionsEntity(tsesRepo, tse); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+var (
+	foldedRunes = map[rune]string{
+		'á': "a", 'à': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a",
+		'ç': "c", 'ć': "c", 'č': "c",
+		'é': "e", 'è': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e",
+		'í': "i", 'ì': "i", 'î': "i", 'ï': "i", 'ī': "i",
+		'ñ': "n", 'ń': "n",
+		'ó': "o", 'ò': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o",
+		'ú': "u", 'ù': "u", 'û': "u", 'ü': "u", 'ū': "u",
+		'ý': "y", 'ÿ': "y",
+		'ß': "ss", 'æ': "ae", 'œ': "oe",
+		'ª': "a", 'º': "o",
+	}
+)
+
+// FoldText lowercases the text and removes the accents.
+func FoldText(text string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if folded, found := foldedRunes[r]; found {
+			sb.WriteString(folded)
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// Tokenize splits the folded text into its words.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(FoldText(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// EditDistance is the Levenshtein distance between the two words.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
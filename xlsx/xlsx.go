@@ -0,0 +1,283 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ContentType string = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	maxSheetNameLength = 31
+)
+
+const (
+	stringCell cellKind = iota
+	numberCell
+	integerCell
+	dateCell
+)
+
+// the styles indexes in the cellXfs of styles.xml
+const (
+	defaultStyle = iota
+	dateStyle
+	numberStyle
+	boldStyle
+	integerStyle
+)
+
+type cellKind int
+
+type Cell struct {
+	kind   cellKind
+	text   string
+	number float64
+	date   time.Time
+	bold   bool
+}
+
+type Sheet struct {
+	name string
+	rows [][]Cell
+}
+
+type Workbook struct {
+	sheets []*Sheet
+}
+
+var (
+	// the day 0 of the spreadsheets serial dates, already taking into account the nonexistent 29/02/1900
+	serialDatesEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+	invalidSheetNameChars = "[]:*?/\\"
+)
+
+func StringCell(text string) Cell {
+	return Cell{kind: stringCell, text: text}
+}
+
+func NumberCell(number float64) Cell {
+	return Cell{kind: numberCell, number: number}
+}
+
+func IntegerCell(number int) Cell {
+	return Cell{kind: integerCell, number: float64(number)}
+}
+
+func DateCell(date time.Time) Cell {
+	return Cell{kind: dateCell, date: date}
+}
+
+// HeaderCell is a bold string cell.
+func HeaderCell(text string) Cell {
+	return Cell{kind: stringCell, text: text, bold: true}
+}
+
+func NewWorkbook() *Workbook {
+	return &Workbook{}
+}
+
+// AddSheet adds a sheet whose name is made valid and unique in the workbook.
+func (wb *Workbook) AddSheet(name string) *Sheet {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(invalidSheetNameChars, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", len(wb.sheets)+1)
+	}
+	base := []rune(name)
+	for i := 2; wb.hasSheet(name); i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		if len(base)+len(suffix) > maxSheetNameLength {
+			base = base[:maxSheetNameLength-len(suffix)]
+		}
+		name = string(base) + suffix
+	}
+	if len([]rune(name)) > maxSheetNameLength {
+		name = string([]rune(name)[:maxSheetNameLength])
+	}
+
+	s := &Sheet{name: name}
+	wb.sheets = append(wb.sheets, s)
+	return s
+}
+
+func (wb *Workbook) hasSheet(name string) bool {
+	for _, s := range wb.sheets {
+		if strings.EqualFold(s.name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Sheet) AddRow(cells ...Cell) {
+	s.rows = append(s.rows, cells)
+}
+
+func (wb *Workbook) Write(w io.Writer) error {
+	if len(wb.sheets) == 0 {
+		wb.AddSheet("")
+	}
+
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", wb.contentTypes()},
+		{"_rels/.rels", []byte(rootRels)},
+		{"xl/workbook.xml", wb.workbook()},
+		{"xl/_rels/workbook.xml.rels", wb.workbookRels()},
+		{"xl/styles.xml", []byte(styles)},
+	}
+	for i, s := range wb.sheets {
+		content, err := s.worksheet()
+		if err != nil {
+			return err
+		}
+		files = append(files, struct {
+			name    string
+			content []byte
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), content})
+	}
+
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err = fw.Write(f.content); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func (wb *Workbook) contentTypes() []byte {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	sb.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	sb.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	sb.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	sb.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range wb.sheets {
+		sb.WriteString(fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1))
+	}
+	sb.WriteString(`</Types>`)
+	return []byte(sb.String())
+}
+
+func (wb *Workbook) workbook() []byte {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, s := range wb.sheets {
+		sb.WriteString(fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(s.name), i+1, i+1))
+	}
+	sb.WriteString(`</sheets></workbook>`)
+	return []byte(sb.String())
+}
+
+func (wb *Workbook) workbookRels() []byte {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range wb.sheets {
+		sb.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1))
+	}
+	sb.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(wb.sheets)+1))
+	sb.WriteString(`</Relationships>`)
+	return []byte(sb.String())
+}
+
+func (s *Sheet) worksheet() ([]byte, error) {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range s.rows {
+		sb.WriteString(fmt.Sprintf(`<row r="%d">`, i+1))
+		for j, c := range row {
+			ref := fmt.Sprintf("%s%d", columnName(j), i+1)
+			switch c.kind {
+			case numberCell:
+				sb.WriteString(fmt.Sprintf(`<c r="%s" s="%d"><v>%s</v></c>`, ref, numberStyle,
+					strconv.FormatFloat(c.number, 'f', -1, 64)))
+			case integerCell:
+				sb.WriteString(fmt.Sprintf(`<c r="%s" s="%d"><v>%s</v></c>`, ref, integerStyle,
+					strconv.FormatFloat(c.number, 'f', 0, 64)))
+			case dateCell:
+				sb.WriteString(fmt.Sprintf(`<c r="%s" s="%d"><v>%s</v></c>`, ref, dateStyle,
+					strconv.FormatFloat(serialDate(c.date), 'f', -1, 64)))
+			case stringCell:
+				style := defaultStyle
+				if c.bold {
+					style = boldStyle
+				}
+				sb.WriteString(fmt.Sprintf(`<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`,
+					ref, style, escape(c.text)))
+			default:
+				return nil, fmt.Errorf("invalid kind of cell at %s", ref)
+			}
+		}
+		sb.WriteString(`</row>`)
+	}
+	sb.WriteString(`</sheetData></worksheet>`)
+	return []byte(sb.String()), nil
+}
+
+func columnName(idx int) string {
+	name := ""
+	for idx >= 0 {
+		name = string(rune('A'+idx%26)) + name
+		idx = idx/26 - 1
+	}
+	return name
+}
+
+func serialDate(t time.Time) float64 {
+	d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return float64(d.Sub(serialDatesEpoch) / (24 * time.Hour))
+}
+
+func escape(s string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+const (
+	rootRels = xml.Header +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	// numFmtId 14 is the built-in short date, 4 is the built-in "#,##0.00" and 1 is the built-in "0"
+	styles = xml.Header +
+		`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="5">` +
+		`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="14" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="1" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`</cellXfs>` +
+		`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+		`</styleSheet>`
+)